@@ -2,111 +2,310 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/compress"
 	"go-api-tech-challenge/internal/config"
+	"go-api-tech-challenge/internal/consistency"
+	"go-api-tech-challenge/internal/corspolicy"
 	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/idempotency"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/ratelimit"
+	"go-api-tech-challenge/internal/retry"
 	"go-api-tech-challenge/internal/routes"
 	"go-api-tech-challenge/internal/services"
 	"go-api-tech-challenge/internal/swagger"
+	"go-api-tech-challenge/internal/tlsconfig"
+	"go-api-tech-challenge/internal/tracing"
+	"io"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
-	"github.com/go-chi/httplog/v2"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 func main() {
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "config" {
+		if err := runConfig(os.Stdout, args[1:]); err != nil {
+			log.Fatalf("config failed. err: %v", err)
+		}
+		return
+	}
+
 	ctx := context.Background()
-	if err := run(ctx); err != nil {
+	if err := run(ctx, args); err != nil {
 		log.Fatalf("Startup failed. err: %v", err)
 	}
 }
 
-func run(ctx context.Context) error {
+// runConfig implements the `config print [--redacted] [flags]` command, which prints the resolved
+// configuration without starting the server.
+func runConfig(w io.Writer, args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		return errors.New("[in runConfig]: usage: config print [--redacted] [flags]")
+	}
+
+	redacted := false
+	var rest []string
+	for _, arg := range args[1:] {
+		if arg == "--redacted" || arg == "-redacted" {
+			redacted = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+
+	cfg, err := config.New(rest)
+	if err != nil {
+		return fmt.Errorf("[in runConfig]: %w", err)
+	}
+	return cfg.Print(w, redacted)
+}
+
+func run(ctx context.Context, args []string) error {
 	// Setup
-	cfg, err := config.New()
+	cfg, err := config.New(args)
+	if err != nil {
+		return fmt.Errorf("[in run]: %w", err)
+	}
+
+	logger, err := logging.New("api", logging.Options{
+		Level:           cfg.LogLevel,
+		Format:          cfg.LogFormat,
+		Concise:         cfg.LogConcise,
+		SubsystemLevels: cfg.LogSubsystemLevels,
+		RequestHeaders:  cfg.LogRequestHeaders,
+		ResponseHeaders: cfg.LogResponseHeaders,
+		RedactFields:    cfg.LogRedactFields,
+		Secrets:         cfg.Secrets(),
+	})
 	if err != nil {
 		return fmt.Errorf("[in run]: %w", err)
 	}
+	// Code running outside a request logs through logging.FromContext's fallback.
+	slog.SetDefault(logger.Logger)
 
-	logger := httplog.NewLogger("api", httplog.Options{
-		LogLevel:        cfg.LogLevel,
-		JSON:            false,
-		Concise:         true,
-		ResponseHeaders: false,
+	shutdownTracing, err := tracing.New(ctx, tracing.Options{
+		ServiceName:  cfg.TracingServiceName,
+		Exporter:     cfg.TracingExporter,
+		OTLPEndpoint: cfg.TracingOTLPEndpoint,
+		OTLPInsecure: cfg.TracingOTLPInsecure,
+		FilePath:     cfg.TracingFilePath,
+		SampleRatio:  cfg.TracingSampleRatio,
 	})
+	if err != nil {
+		return fmt.Errorf("[in run]: %w", err)
+	}
+	logger.Info("Tracing configured", "exporter", cfg.TracingExporter)
 
-	connString := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-		cfg.DBHost,
-		cfg.DBUser,
-		cfg.DBPassword,
-		cfg.DBName,
-		cfg.DBPort,
-	)
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("Error flushing traces", "err", err)
+		}
+	}()
 
+	poolOptions := database.PoolOptions{
+		MaxConns:               cfg.DBMaxOpenConns,
+		MinConns:               cfg.DBMinConns,
+		ConnMaxLifetime:        cfg.DBConnMaxLifetime,
+		ConnMaxIdleTime:        cfg.DBConnMaxIdleTime,
+		HealthCheckPeriod:      cfg.DBHealthCheckPeriod,
+		QueryExecMode:          cfg.DBQueryExecMode,
+		StatementCacheCapacity: cfg.DBStatementCacheCapacity,
+	}
 	db, err := database.New(
 		ctx,
-		connString,
-		logger,
-		time.Duration(cfg.DBRetryDuration)*time.Second,
+		cfg.DatabaseConnString(),
+		logging.WithSubsystem(logger.Logger, logging.SubsystemDatabase),
+		retry.Policy{
+			MaxAttempts:  cfg.DBRetryMaxAttempts,
+			MaxElapsed:   time.Duration(cfg.DBRetryDuration) * time.Second,
+			InitialDelay: cfg.DBRetryInitialBackoff,
+			MaxDelay:     cfg.DBRetryMaxBackoff,
+			Jitter:       0.5,
+		},
+		poolOptions,
 	)
 	if err != nil {
 		return fmt.Errorf("[in run]: %w", err)
 	}
 
-	defer func() {
-		if err = db.Close(); err != nil {
-			logger.Error("Error closing db connection", "err", err)
-		}
-	}()
+	defer db.Close()
+
+	replicas, closeReplicas, err := openReplicas(ctx, cfg, poolOptions, logging.WithSubsystem(logger.Logger, logging.SubsystemDatabase))
+	if err != nil {
+		return fmt.Errorf("[in run]: %w", err)
+	}
+	defer closeReplicas()
 
 	router := chi.NewRouter()
 
-	router.Use(httplog.RequestLogger(logger))
+	router.Use(tracing.Middleware)
+	// Compression wraps the request logger so that logged response bodies are the JSON written by
+	// the handlers, which can be redacted, rather than the encoded bytes.
+	if cfg.HTTPCompression {
+		router.Use(compress.Middleware(cfg.HTTPCompressionMinBytes))
+	}
+	router.Use(logging.RequestLogger(logger, logging.BodyOptions{
+		Request:  cfg.LogRequestBody,
+		Response: cfg.LogResponseBody,
+		MaxBytes: cfg.LogBodyMaxBytes,
+	}))
 	router.Use(middleware.Recoverer)
-	router.Use(cors.Handler(cors.Options{
-		AllowedOrigins: []string{"*"},
-		AllowedMethods: []string{"GET", "PUT", "POST", "DELETE"},
-		MaxAge:         300,
+	corsPolicy := corspolicy.New(corsOptions(cfg))
+	router.Use(corsPolicy.Handler)
+	// Probes are exempt so that a tight limit cannot take the service out of rotation.
+	limiter := ratelimit.New(rateLimit(cfg))
+	router.Use(middleware.Maybe(limiter.Middleware, func(r *http.Request) bool {
+		return !strings.HasPrefix(r.URL.Path, "/api/health/")
 	}))
+	if replicas.Len() > 0 {
+		router.Use(consistency.Middleware(cfg.DBReadYourWritesWindow))
+	}
 
-	svsCourse := services.NewCourseService(db)
-	svsPerson := services.NewPersonService(db)
+	serviceOptions := []services.Option{
+		services.WithStatementTimeout(cfg.DBStatementTimeout),
+		services.WithStreamTimeout(cfg.DBStreamTimeout),
+		services.WithReplicas(replicas),
+	}
+	routeOptions := []routes.Option{
+		routes.WithRegisterHealthRoute(true),
+		routes.WithCacheMaxAge(cfg.HTTPCacheMaxAge),
+		routes.WithImportMaxRows(cfg.ImportMaxRows),
+		routes.WithBatchMaxOperations(cfg.BatchMaxOperations),
+	}
+	if cfg.CacheMaxEntries > 0 {
+		readCache := cache.NewAside(cache.NewLRU(cfg.CacheMaxEntries), cfg.CacheTTL)
+		serviceOptions = append(serviceOptions, services.WithCache(readCache))
+		routeOptions = append(routeOptions, routes.WithCacheStats(readCache))
+	}
+	svsCourse := services.NewCourseService(db, serviceOptions...)
+	svsPerson := services.NewPersonService(db, serviceOptions...)
+	svsDepartment := services.NewDepartmentService(db, serviceOptions...)
+	svsTerm := services.NewTermService(db, serviceOptions...)
+	svsHealth := services.NewHealthService(db, time.Duration(cfg.DBPingTimeout)*time.Second)
+	svsBatch := services.NewBatchService(db)
+	if cfg.IdempotencyKeyTTL > 0 {
+		svsIdempotency := services.NewIdempotencyService(db, serviceOptions...)
+		routeOptions = append(routeOptions, routes.WithIdempotency(svsIdempotency, cfg.IdempotencyKeyTTL))
+		purgeCtx, stopPurge := context.WithCancel(ctx)
+		defer stopPurge()
+		go idempotency.RunPurge(purgeCtx, svsIdempotency, logging.WithSubsystem(logger.Logger, logging.SubsystemDatabase))
+	}
 
-	routes.RegisterRoutes(router, logger, svsCourse, svsPerson, routes.WithRegisterHealthRoute(true))
+	// Substring search stands in where the database cannot search for itself.
+	searchOption := routes.WithSearch(services.NewSubstringSearchService(svsPerson, svsCourse))
+	if cfg.SearchBackend == "postgres" {
+		svsSearch := services.NewSearchService(db, serviceOptions...)
+		available, err := svsSearch.Available(ctx)
+		if err != nil {
+			return fmt.Errorf("[in run]: %w", err)
+		}
+		if available {
+			searchOption = routes.WithSearch(svsSearch)
+		} else {
+			logger.Warn("The pg_trgm extension is not installed, falling back to substring search")
+		}
+	}
+	routeOptions = append(routeOptions, searchOption)
+
+	routes.RegisterRoutes(router, svsCourse, svsPerson, svsDepartment, svsTerm, svsHealth, svsBatch, routeOptions...)
 
+	scheme := "http"
+	if cfg.TLSEnabled() {
+		scheme = "https"
+	}
 	if cfg.HTTPUseSwagger {
-		swagger.RunSwagger(router, logger, cfg.SwaggerHTTPDomain+cfg.HTTPPort)
+		swagger.RunSwagger(router, logger, scheme, cfg.SwaggerHTTPDomain+cfg.HTTPPort)
 	}
 
 	serverInstance := &http.Server{
 		Addr:              cfg.HTTPDomain + cfg.HTTPPort,
-		IdleTimeout:       time.Minute,
-		ReadHeaderTimeout: 500 * time.Millisecond,
-		ReadTimeout:       500 * time.Millisecond,
-		WriteTimeout:      500 * time.Millisecond,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		MaxHeaderBytes:    cfg.HTTPMaxHeaderBytes,
 		Handler:           router,
 	}
 
+	var certs *tlsconfig.Reloader
+	if cfg.TLSEnabled() {
+		certs, err = tlsconfig.New(tlsconfig.Options{
+			CertFile:       cfg.TLSCertFile,
+			KeyFile:        cfg.TLSKeyFile,
+			ClientCAFile:   cfg.TLSClientCAFile,
+			ClientAuth:     cfg.ClientAuth(),
+			MinVersion:     cfg.TLSMinVersion,
+			ReloadInterval: cfg.TLSReloadInterval,
+			HTTP2:          cfg.HTTP2Enabled,
+		}, logger.Logger)
+		if err != nil {
+			return fmt.Errorf("[in run]: %w", err)
+		}
+		serverInstance.TLSConfig = certs.Config()
+	}
+
+	switch {
+	case !cfg.HTTP2Enabled:
+		// A non-nil, empty map stops net/http from enabling HTTP/2 on TLS connections.
+		serverInstance.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+	case cfg.TLSEnabled():
+		if err := http2.ConfigureServer(serverInstance, &http2.Server{IdleTimeout: cfg.HTTPIdleTimeout}); err != nil {
+			return fmt.Errorf("[in run]: %w", err)
+		}
+	case cfg.HTTP2Cleartext:
+		serverInstance.Handler = h2c.NewHandler(router, &http2.Server{IdleTimeout: cfg.HTTPIdleTimeout})
+	}
+
+	// Config reload
+	holder := config.NewHolder(cfg, args)
+
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			logger.Info("Reload signal received")
+			reloadConfig(holder, logger.Logger, limiter, corsPolicy)
+			if certs != nil {
+				if err := certs.Reload(); err != nil {
+					logger.Error("Error reloading TLS files, keeping current certificate", "err", err)
+				}
+			}
+		}
+	}()
+
 	// Graceful shutdown
 	serverCtx, serverStopCtx := context.WithCancel(context.Background())
 
 	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 	go func() {
 		<-sig
 
 		fmt.Println()
 		logger.Info("Shutdown signal received")
 
+		// Fail readiness checks first so load balancers stop sending traffic before the
+		// listener is closed.
+		svsHealth.SetDraining(true)
+		drainDuration := time.Duration(cfg.HTTPDrainDuration) * time.Second
+		logger.Info("Draining connections", "duration", drainDuration)
+		time.Sleep(drainDuration)
+
 		shutdownCtx, err := context.WithTimeout(
 			serverCtx, time.Duration(cfg.HTTPShutdownDuration)*time.Second,
 		)
@@ -128,8 +327,14 @@ func run(ctx context.Context) error {
 	}()
 
 	// Run
-	logger.Info(fmt.Sprintf("Server is listening on %s", serverInstance.Addr))
-	err = serverInstance.ListenAndServe()
+	logger.Info(fmt.Sprintf("Server is listening on %s", serverInstance.Addr),
+		"tls", cfg.TLSEnabled(), "clientAuth", cfg.ClientAuth(), "http2", cfg.HTTP2Enabled)
+	if cfg.TLSEnabled() {
+		// The certificate comes from TLSConfig, so no files are passed here.
+		err = serverInstance.ListenAndServeTLS("", "")
+	} else {
+		err = serverInstance.ListenAndServe()
+	}
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
 		return err
 	}
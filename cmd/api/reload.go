@@ -0,0 +1,57 @@
+package main
+
+import (
+	"go-api-tech-challenge/internal/config"
+	"go-api-tech-challenge/internal/corspolicy"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/ratelimit"
+	"log/slog"
+)
+
+func corsOptions(cfg config.Configuration) corspolicy.Options {
+	return corspolicy.Options{
+		AllowedOrigins:   cfg.CORSAllowedOrigins,
+		AllowedMethods:   cfg.CORSAllowedMethods,
+		AllowedHeaders:   cfg.CORSAllowedHeaders,
+		ExposedHeaders:   cfg.CORSExposedHeaders,
+		AllowCredentials: cfg.CORSAllowCredentials,
+		MaxAge:           cfg.CORSMaxAge,
+	}
+}
+
+func rateLimit(cfg config.Configuration) ratelimit.Limit {
+	return ratelimit.Limit{
+		RequestsPerSecond: cfg.RateLimitRPS,
+		Burst:             cfg.RateLimitBurst,
+	}
+}
+
+// reloadConfig re-reads the configuration and applies the settings that can change while the
+// server is running. An invalid configuration is logged and ignored, leaving the server as it was.
+func reloadConfig(holder *config.Holder, logger *slog.Logger, limiter *ratelimit.Limiter, cors *corspolicy.Policy) {
+	changes, err := holder.Reload()
+	if err != nil {
+		logger.Error("Config reload rejected, keeping current config", "err", err)
+		return
+	}
+	if len(changes) == 0 {
+		logger.Info("Config reloaded, nothing changed")
+		return
+	}
+
+	for _, change := range changes {
+		if change.Applied {
+			logger.Info("Config changed", "key", change.Key, "old", change.Old, "new", change.New)
+		} else {
+			logger.Warn("Config change requires a restart, ignoring", "key", change.Key, "old", change.Old, "new", change.New)
+		}
+	}
+
+	cfg := holder.Load()
+	// Levels were validated with the rest of the configuration, so this cannot fail.
+	if err := logging.SetLevels(logger, cfg.LogLevel, cfg.LogSubsystemLevels); err != nil {
+		logger.Error("Error applying log levels", "err", err)
+	}
+	limiter.SetLimit(rateLimit(cfg))
+	cors.Set(corsOptions(cfg))
+}
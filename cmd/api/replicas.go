@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/config"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/services"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// openReplicas creates a pool for every configured read replica and starts health-checking them
+// in the background. The returned function stops the health checks and closes the pools.
+func openReplicas(ctx context.Context, cfg config.Configuration, pool database.PoolOptions, logger *slog.Logger) (*database.ReplicaSet[services.Replica], func(), error) {
+	var (
+		pools    []*pgxpool.Pool
+		replicas []database.Replica[services.Replica]
+	)
+	closeAll := func() {
+		for _, p := range pools {
+			p.Close()
+		}
+	}
+
+	for i, connString := range cfg.ReplicaConnStrings() {
+		p, err := database.NewReplicaPool(ctx, connString, pool)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("[in openReplicas] replica %d: %w", i+1, err)
+		}
+		pools = append(pools, p)
+		replicas = append(replicas, database.Replica[services.Replica]{Name: replicaName(cfg.DBReplicaURLs[i]), DB: p})
+	}
+
+	set := database.NewReplicaSet(logger, replicas...)
+	if set.Len() > 0 {
+		logger.Info("Routing reads to replicas", "replicas", set.Len(), "read your writes window", cfg.DBReadYourWritesWindow)
+	}
+
+	checkCtx, stopChecks := context.WithCancel(ctx)
+	checked := make(chan struct{})
+	go func() {
+		defer close(checked)
+		set.Run(checkCtx, cfg.DBReplicaCheckInterval, time.Duration(cfg.DBPingTimeout)*time.Second)
+	}()
+
+	return set, func() {
+		stopChecks()
+		<-checked
+		closeAll()
+	}, nil
+}
+
+// replicaName identifies a replica in logs by its host.
+func replicaName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "replica"
+	}
+	return u.Host
+}
@@ -1,47 +1,143 @@
+DROP TABLE IF EXISTS idempotency_key;
+DROP TABLE IF EXISTS waitlist;
+DROP TABLE IF EXISTS enrollment;
+-- person_course held enrollments before sections; it is dropped so older databases can be re-seeded.
 DROP TABLE IF EXISTS person_course;
+DROP TABLE IF EXISTS course_section;
+DROP TABLE IF EXISTS term;
 DROP TABLE IF EXISTS course;
 DROP TABLE IF EXISTS person;
+DROP TABLE IF EXISTS department;
 
--- person
+-- pg_trgm provides the trigram similarity that lets searches match misspelled names.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- department groups courses and the professors who teach them.
+CREATE TABLE department
+(
+    id   SERIAL PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+-- Department names are unique regardless of case.
+CREATE UNIQUE INDEX department_name ON department (lower(name));
+
+INSERT INTO department (name)
+VALUES ('Computer Science'),
+       ('Design');
+
+-- person. age is only read for persons without a date_of_birth; the age of the rest is derived from
+-- it, so that it does not go stale.
 CREATE TABLE person
 (
-    id         SERIAL PRIMARY KEY,
-    first_name TEXT                                          NOT NULL,
-    last_name  TEXT                                          NOT NULL,
-    type       TEXT CHECK (type IN ('professor', 'student')) NOT NULL,
-    age        INTEGER                                       NOT NULL
+    id            SERIAL PRIMARY KEY,
+    first_name    TEXT                                          NOT NULL,
+    last_name     TEXT                                          NOT NULL,
+    type          TEXT CHECK (type IN ('professor', 'student')) NOT NULL,
+    age           INTEGER                                       NOT NULL,
+    external_id   TEXT,
+    email         TEXT,
+    phone         TEXT,
+    date_of_birth DATE,
+    created_at    TIMESTAMPTZ                                   NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ                                   NOT NULL DEFAULT now(),
+    department_id INTEGER,
+    CONSTRAINT person_department FOREIGN KEY (department_id) REFERENCES department (id) ON DELETE SET NULL
 );
 
-INSERT INTO person (first_name, last_name, type, age)
-VALUES ('Steve', 'Jobs', 'professor', 56),
-       ('Jeff', 'Bezos', 'professor', 60),
-       ('Larry', 'Page', 'student', 51),
-       ('Bill', 'Gates', 'student', 67),
-       ('Elon', 'Musk', 'student', 52);
+-- external_id is an optional student or staff number, unique when it is set.
+CREATE UNIQUE INDEX person_external_id ON person (external_id);
+-- Email addresses are unique regardless of case.
+CREATE UNIQUE INDEX person_email ON person (lower(email));
+
+-- Searches match a person's full name by word and by trigram. The expressions must match the ones
+-- in services.SearchService for the indexes to be used.
+CREATE INDEX person_name_search ON person USING gin (to_tsvector('simple', first_name || ' ' || last_name));
+CREATE INDEX person_name_trgm ON person USING gin ((first_name || ' ' || last_name) gin_trgm_ops);
+
+INSERT INTO person (first_name, last_name, type, age, department_id)
+VALUES ('Steve', 'Jobs', 'professor', 56, 2),
+       ('Jeff', 'Bezos', 'professor', 60, 1),
+       ('Larry', 'Page', 'student', 51, NULL),
+       ('Bill', 'Gates', 'student', 67, NULL),
+       ('Elon', 'Musk', 'student', 52, NULL);
 
 -- course
 CREATE TABLE course
 (
-    id   SERIAL PRIMARY KEY,
-    name TEXT NOT NULL
+    id            SERIAL PRIMARY KEY,
+    code          TEXT    NOT NULL,
+    name          TEXT    NOT NULL,
+    credits       INTEGER NOT NULL DEFAULT 3 CHECK (credits BETWEEN 1 AND 12),
+    description   TEXT    NOT NULL DEFAULT '',
+    level         TEXT    NOT NULL DEFAULT 'undergraduate' CHECK (level IN ('undergraduate', 'graduate')),
+    active        BOOLEAN NOT NULL DEFAULT true,
+    department_id INTEGER,
+    -- capacity is how many students each section of the course holds, NULL for no limit.
+    capacity      INTEGER CHECK (capacity > 0),
+    CONSTRAINT course_department FOREIGN KEY (department_id) REFERENCES department (id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX course_code ON course (upper(code));
+
+CREATE INDEX course_name_search ON course USING gin (to_tsvector('simple', name));
+CREATE INDEX course_name_trgm ON course USING gin (name gin_trgm_ops);
+
+INSERT INTO course (code, name, credits, description, level, department_id, capacity)
+VALUES ('CS101', 'Programming', 4, 'Writing, testing and debugging programs.', 'undergraduate', 1, 30),
+       ('CS220', 'Databases', 3, 'Relational modelling, SQL and transactions.', 'undergraduate', 1, 3),
+       ('DES310', 'UI Design', 3, 'Designing and evaluating user interfaces.', 'undergraduate', 2, NULL);
+
+-- term is a teaching period, such as Fall 2026, in which courses are given.
+CREATE TABLE term
+(
+    id        SERIAL PRIMARY KEY,
+    name      TEXT NOT NULL,
+    starts_on DATE NOT NULL,
+    ends_on   DATE NOT NULL,
+    CHECK (starts_on < ends_on)
 );
 
-INSERT INTO course (name)
-VALUES ('Programming'),
-       ('Databases'),
-       ('UI Design');
+-- Term names are unique regardless of case.
+CREATE UNIQUE INDEX term_name ON term (lower(name));
+
+INSERT INTO term (name, starts_on, ends_on)
+VALUES ('Fall 2026', '2026-09-01', '2026-12-18'),
+       ('Spring 2027', '2027-01-18', '2027-05-14');
 
--- person_course
-CREATE TABLE person_course
+-- course_section is one running of a course in a term, taught by a professor. A course may run in
+-- several numbered sections in the same term.
+CREATE TABLE course_section
 (
-    person_id INTEGER NOT NULL,
-    course_id INTEGER NOT NULL,
-    PRIMARY KEY (person_id, course_id),
+    id            SERIAL PRIMARY KEY,
+    course_id     INTEGER NOT NULL,
+    term_id       INTEGER NOT NULL,
+    number        INTEGER NOT NULL CHECK (number > 0),
+    instructor_id INTEGER,
+    CONSTRAINT course_section_course FOREIGN KEY (course_id) REFERENCES course (id) ON DELETE CASCADE,
+    CONSTRAINT course_section_term FOREIGN KEY (term_id) REFERENCES term (id),
+    CONSTRAINT course_section_instructor FOREIGN KEY (instructor_id) REFERENCES person (id) ON DELETE SET NULL
+);
+
+CREATE UNIQUE INDEX course_section_number ON course_section (course_id, term_id, number);
+
+INSERT INTO course_section (course_id, term_id, number, instructor_id)
+VALUES (1, 1, 1, 2),
+       (2, 1, 1, 2),
+       (3, 1, 1, 1);
+
+-- enrollment is a person taking, or teaching, a course section. The courses of a person are those
+-- of their sections.
+CREATE TABLE enrollment
+(
+    person_id  INTEGER NOT NULL,
+    section_id INTEGER NOT NULL,
+    PRIMARY KEY (person_id, section_id),
     FOREIGN KEY (person_id) REFERENCES person (id),
-    FOREIGN KEY (course_id) REFERENCES course (id)
+    CONSTRAINT enrollment_section FOREIGN KEY (section_id) REFERENCES course_section (id)
 );
 
-INSERT INTO person_course (person_id, course_id)
+INSERT INTO enrollment (person_id, section_id)
 VALUES (1, 1),
        (1, 2),
        (1, 3),
@@ -56,4 +152,32 @@ VALUES (1, 1),
        (4, 3),
        (5, 1),
        (5, 2),
-       (5, 3);
\ No newline at end of file
+       (5, 3);
+
+-- waitlist holds the students waiting for a seat in a full section, first come first served.
+CREATE TABLE waitlist
+(
+    id         SERIAL PRIMARY KEY,
+    person_id  INTEGER NOT NULL,
+    section_id INTEGER NOT NULL,
+    CONSTRAINT waitlist_person_section UNIQUE (person_id, section_id),
+    FOREIGN KEY (person_id) REFERENCES person (id) ON DELETE CASCADE,
+    FOREIGN KEY (section_id) REFERENCES course_section (id) ON DELETE CASCADE
+);
+
+CREATE INDEX waitlist_section ON waitlist (section_id, id);
+
+-- idempotency_key holds the keys sent in Idempotency-Key headers and the responses to replay to
+-- retries. status is NULL while the first request with the key is still running.
+CREATE TABLE idempotency_key
+(
+    key          TEXT PRIMARY KEY,
+    request_hash TEXT        NOT NULL,
+    status       INTEGER,
+    content_type TEXT,
+    body         BYTEA,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idempotency_key_expires_at ON idempotency_key (expires_at);
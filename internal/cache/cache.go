@@ -0,0 +1,141 @@
+// Package cache puts a cache-aside layer in front of reads. Values are cached as JSON so that the
+// in-process LRU can be swapped for an external cache, such as Redis or memcached, by implementing
+// Cache.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores encoded values by key. Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the value stored under key, or false if there is none or it has expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores value under key until ttl passes.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// DeletePrefix removes every value whose key starts with prefix.
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// Sizer is implemented by caches that can report their size, such as LRU. Stats includes these
+// figures when the cache provides them.
+type Sizer interface {
+	Len() int
+	Evictions() uint64
+}
+
+// Aside reads through a Cache, loading and storing values on a miss, and counts the outcome of
+// every lookup. A nil *Aside is valid and always loads, so callers need not check whether caching
+// is enabled.
+type Aside struct {
+	cache Cache
+	ttl   time.Duration
+
+	// generation changes on every invalidation, so that a value loaded before an invalidation is
+	// not stored after it.
+	generation    atomic.Uint64
+	hits          atomic.Uint64
+	misses        atomic.Uint64
+	errors        atomic.Uint64
+	invalidations atomic.Uint64
+}
+
+// NewAside returns a cache-aside layer that keeps values in cache for ttl.
+func NewAside(cache Cache, ttl time.Duration) *Aside {
+	return &Aside{cache: cache, ttl: ttl}
+}
+
+// Load returns the value cached under key, or calls load and caches its result. Reads that must
+// see the latest data, marked with database.WithPrimary, skip the cache. A failing cache is logged
+// and bypassed rather than failing the read.
+func Load[T any](ctx context.Context, a *Aside, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	if a == nil || database.PrimaryRequired(ctx) {
+		return load(ctx)
+	}
+	logger := logging.Subsystem(ctx, logging.SubsystemCache)
+
+	encoded, found, err := a.cache.Get(ctx, key)
+	if err != nil {
+		a.errors.Add(1)
+		logger.Warn("Failed to read from cache", "key", key, "err", err)
+	}
+	if found {
+		var value T
+		if err := json.Unmarshal(encoded, &value); err == nil {
+			a.hits.Add(1)
+			return value, nil
+		}
+		a.errors.Add(1)
+		logger.Warn("Discarding undecodable cache entry", "key", key, "err", err)
+	}
+	a.misses.Add(1)
+
+	generation := a.generation.Load()
+	value, err := load(ctx)
+	if err != nil {
+		return value, err
+	}
+	if a.generation.Load() != generation {
+		return value, nil
+	}
+
+	encoded, err = json.Marshal(value)
+	if err == nil {
+		err = a.cache.Set(ctx, key, encoded, a.ttl)
+	}
+	if err != nil {
+		a.errors.Add(1)
+		logger.Warn("Failed to write to cache", "key", key, "err", err)
+	}
+	return value, nil
+}
+
+// Invalidate removes every cached value whose key starts with one of prefixes. It is called after
+// a change has been written, so a failure is logged rather than returned; the stale values then
+// expire with their TTL. Inside a shared transaction the values are removed once it commits.
+func (a *Aside) Invalidate(ctx context.Context, prefixes ...string) {
+	if a == nil {
+		return
+	}
+	database.AfterCommit(ctx, func() {
+		a.invalidate(context.WithoutCancel(ctx), prefixes)
+	})
+}
+
+func (a *Aside) invalidate(ctx context.Context, prefixes []string) {
+	a.generation.Add(1)
+	a.invalidations.Add(1)
+	for _, prefix := range prefixes {
+		if err := a.cache.DeletePrefix(ctx, prefix); err != nil {
+			a.errors.Add(1)
+			logging.Subsystem(ctx, logging.SubsystemCache).Warn("Failed to invalidate cache",
+				"prefix", prefix,
+				"err", err,
+			)
+		}
+	}
+}
+
+// CacheStats reports lookup counts since the layer was created.
+func (a *Aside) CacheStats(ctx context.Context) models.CacheStats {
+	stats := models.CacheStats{
+		Hits:          a.hits.Load(),
+		Misses:        a.misses.Load(),
+		Errors:        a.errors.Load(),
+		Invalidations: a.invalidations.Load(),
+	}
+	if lookups := stats.Hits + stats.Misses; lookups > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(lookups)
+	}
+	if sizer, ok := a.cache.(Sizer); ok {
+		stats.Entries = sizer.Len()
+		stats.Evictions = sizer.Evictions()
+	}
+	return stats
+}
@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingCache is a Cache whose every call fails, like an unreachable external cache.
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("connection refused")
+}
+
+func (failingCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingCache) DeletePrefix(ctx context.Context, prefix string) error {
+	return errors.New("connection refused")
+}
+
+func TestLoad(t *testing.T) {
+	ctx := context.Background()
+	errLoad := errors.New("database down")
+
+	loader := func(loads *int, err error) func(ctx context.Context) (models.Course, error) {
+		return func(ctx context.Context) (models.Course, error) {
+			*loads++
+			return models.Course{ID: *loads, Name: "Programming"}, err
+		}
+	}
+
+	tests := map[string]struct {
+		aside         *Aside
+		ctx           context.Context
+		loadErr       error
+		expected      models.Course
+		expectedErr   error
+		expectedLoads int
+		expectedStats models.CacheStats
+	}{
+		"second read is a hit": {
+			aside:         NewAside(NewLRU(10), time.Minute),
+			ctx:           ctx,
+			expected:      models.Course{ID: 1, Name: "Programming"},
+			expectedLoads: 1,
+			expectedStats: models.CacheStats{Hits: 1, Misses: 1, HitRatio: 0.5, Entries: 1},
+		},
+		"errors are not cached": {
+			aside:         NewAside(NewLRU(10), time.Minute),
+			ctx:           ctx,
+			loadErr:       errLoad,
+			expected:      models.Course{ID: 2, Name: "Programming"},
+			expectedErr:   errLoad,
+			expectedLoads: 2,
+			expectedStats: models.CacheStats{Misses: 2},
+		},
+		"reads that need the primary skip the cache": {
+			aside:         NewAside(NewLRU(10), time.Minute),
+			ctx:           database.WithPrimary(ctx),
+			expected:      models.Course{ID: 2, Name: "Programming"},
+			expectedLoads: 2,
+		},
+		"a failing cache falls back to loading": {
+			aside:         NewAside(failingCache{}, time.Minute),
+			ctx:           ctx,
+			expected:      models.Course{ID: 2, Name: "Programming"},
+			expectedLoads: 2,
+			expectedStats: models.CacheStats{Misses: 2, Errors: 4},
+		},
+		"nil layer always loads": {
+			ctx:           ctx,
+			expected:      models.Course{ID: 2, Name: "Programming"},
+			expectedLoads: 2,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			loads := 0
+			load := loader(&loads, tc.loadErr)
+
+			_, _ = Load(tc.ctx, tc.aside, "course:id:1", load)
+			course, err := Load(tc.ctx, tc.aside, "course:id:1", load)
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			assert.Equal(t, tc.expected, course)
+			assert.Equal(t, tc.expectedLoads, loads)
+			if tc.aside != nil {
+				assert.Equal(t, tc.expectedStats, tc.aside.CacheStats(ctx))
+			}
+		})
+	}
+}
+
+func TestInvalidate(t *testing.T) {
+	ctx := context.Background()
+	aside := NewAside(NewLRU(10), time.Minute)
+
+	loads := 0
+	load := func(ctx context.Context) ([]models.Course, error) {
+		loads++
+		return []models.Course{{ID: loads, Name: "Programming"}}, nil
+	}
+
+	_, _ = Load(ctx, aside, "course:list", load)
+	aside.Invalidate(ctx, "course:")
+	courses, err := Load(ctx, aside, "course:list", load)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: 2, Name: "Programming"}}, courses)
+	assert.Equal(t, uint64(1), aside.CacheStats(ctx).Invalidations)
+
+	// A value loaded while an invalidation happens is returned but not cached, since it may
+	// predate the change.
+	courses, err = Load(ctx, aside, "course:id:3", func(ctx context.Context) ([]models.Course, error) {
+		aside.Invalidate(ctx, "course:")
+		return load(ctx)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{{ID: 3, Name: "Programming"}}, courses)
+	_, found, _ := aside.cache.Get(ctx, "course:id:3")
+	assert.False(t, found)
+}
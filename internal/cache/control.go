@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Control sets the Cache-Control header on responses that do not set their own. Successful GET and
+// HEAD responses may be reused by the client for maxAge, or must be revalidated when maxAge is
+// zero. They are marked private because they can depend on the client's read consistency. Every
+// other response must not be stored.
+func Control(maxAge time.Duration) func(http.Handler) http.Handler {
+	cacheable := "no-cache"
+	if seconds := int(maxAge.Seconds()); seconds > 0 {
+		cacheable = fmt.Sprintf("private, max-age=%d", seconds)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			value := "no-store"
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				value = cacheable
+			}
+			next.ServeHTTP(&controlWriter{ResponseWriter: w, value: value}, r)
+		})
+	}
+}
+
+// controlWriter sets Cache-Control just before the status is written, once it is known whether the
+// response succeeded.
+type controlWriter struct {
+	http.ResponseWriter
+	value       string
+	wroteHeader bool
+}
+
+func (w *controlWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if w.Header().Get("Cache-Control") == "" {
+			if status >= http.StatusOK && status < http.StatusMultipleChoices {
+				w.Header().Set("Cache-Control", w.value)
+			} else {
+				w.Header().Set("Cache-Control", "no-store")
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *controlWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *controlWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestControl(t *testing.T) {
+	tests := map[string]struct {
+		maxAge   time.Duration
+		method   string
+		status   int
+		header   string
+		expected string
+	}{
+		"reads revalidate by default": {
+			method:   http.MethodGet,
+			status:   http.StatusOK,
+			expected: "no-cache",
+		},
+		"reads may be reused for max age": {
+			maxAge:   90 * time.Second,
+			method:   http.MethodGet,
+			status:   http.StatusOK,
+			expected: "private, max-age=90",
+		},
+		"errors are not stored": {
+			maxAge:   90 * time.Second,
+			method:   http.MethodGet,
+			status:   http.StatusNotFound,
+			expected: "no-store",
+		},
+		"writes are not stored": {
+			maxAge:   90 * time.Second,
+			method:   http.MethodPost,
+			status:   http.StatusCreated,
+			expected: "no-store",
+		},
+		"handler header wins": {
+			maxAge:   90 * time.Second,
+			method:   http.MethodGet,
+			status:   http.StatusOK,
+			header:   "public, max-age=3600",
+			expected: "public, max-age=3600",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := Control(tc.maxAge)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.header != "" {
+					w.Header().Set("Cache-Control", tc.header)
+				}
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte("{}"))
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(tc.method, "/api/course", nil))
+
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Equal(t, tc.expected, rr.Header().Get("Cache-Control"))
+		})
+	}
+}
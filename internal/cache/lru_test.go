@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		run           func(c *LRU)
+		expected      map[string]string
+		expectedLen   int
+		expectedEvict uint64
+	}{
+		"stores and returns values": {
+			run: func(c *LRU) {
+				_ = c.Set(ctx, "course:list", []byte("a"), time.Minute)
+			},
+			expected:    map[string]string{"course:list": "a"},
+			expectedLen: 1,
+		},
+		"evicts the least recently used entry": {
+			run: func(c *LRU) {
+				_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+				_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+				_, _, _ = c.Get(ctx, "a")
+				_ = c.Set(ctx, "c", []byte("3"), time.Minute)
+			},
+			expected:      map[string]string{"a": "1", "b": "", "c": "3"},
+			expectedLen:   2,
+			expectedEvict: 1,
+		},
+		"overwriting does not evict": {
+			run: func(c *LRU) {
+				_ = c.Set(ctx, "a", []byte("1"), time.Minute)
+				_ = c.Set(ctx, "b", []byte("2"), time.Minute)
+				_ = c.Set(ctx, "a", []byte("3"), time.Minute)
+			},
+			expected:    map[string]string{"a": "3", "b": "2"},
+			expectedLen: 2,
+		},
+		"expired entries are dropped": {
+			run: func(c *LRU) {
+				_ = c.Set(ctx, "a", []byte("1"), time.Second)
+				_ = c.Set(ctx, "b", []byte("2"), time.Hour)
+				now = now.Add(time.Minute)
+			},
+			expected:    map[string]string{"a": "", "b": "2"},
+			expectedLen: 1,
+		},
+		"deletes by prefix": {
+			run: func(c *LRU) {
+				_ = c.Set(ctx, "course:id:1", []byte("1"), time.Minute)
+				_ = c.Set(ctx, "person:list", []byte("2"), time.Minute)
+				_ = c.DeletePrefix(ctx, "course:")
+			},
+			expected:    map[string]string{"course:id:1": "", "person:list": "2"},
+			expectedLen: 1,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := NewLRU(2)
+			c.now = func() time.Time { return now }
+
+			tc.run(c)
+
+			for key, expected := range tc.expected {
+				value, found, err := c.Get(ctx, key)
+				assert.NoError(t, err)
+				assert.Equal(t, expected != "", found, key)
+				assert.Equal(t, expected, string(value), key)
+			}
+			assert.Equal(t, tc.expectedLen, c.Len())
+			assert.Equal(t, tc.expectedEvict, c.Evictions())
+		})
+	}
+}
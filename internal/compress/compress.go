@@ -0,0 +1,251 @@
+// Package compress compresses responses with brotli or gzip, whichever the client prefers. Small
+// responses, and content that is already compressed, are sent as they are. Responses that are
+// flushed while they are written, such as streamed lists, are compressed incrementally.
+package compress
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+)
+
+// Content codings the middleware can apply.
+const (
+	Brotli   = "br"
+	Gzip     = "gzip"
+	Identity = "identity"
+)
+
+// brotliLevel trades some compression for speed, since responses are compressed as they are sent.
+const brotliLevel = 4
+
+// compressible lists the media types worth compressing; images, archives and the like already are.
+var compressible = map[string]bool{
+	"application/json":         true,
+	"application/problem+json": true,
+	"application/x-ndjson":     true,
+	"application/javascript":   true,
+	"application/xml":          true,
+	"image/svg+xml":            true,
+	"text/css":                 true,
+	"text/csv":                 true,
+	"text/html":                true,
+	"text/javascript":          true,
+	"text/plain":               true,
+}
+
+var (
+	gzipWriters = sync.Pool{New: func() any {
+		writer, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return writer
+	}}
+	brotliWriters = sync.Pool{New: func() any {
+		return brotli.NewWriterLevel(nil, brotliLevel)
+	}}
+)
+
+// encoder is the part of gzip.Writer and brotli.Writer the middleware uses.
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// Middleware compresses responses of at least minSize bytes with the coding negotiated from the
+// request's Accept-Encoding header.
+func Middleware(minSize int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", "Accept-Encoding")
+
+			coding := Negotiate(r.Header.Get("Accept-Encoding"))
+			if coding == Identity || r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{ResponseWriter: w, coding: coding, minSize: minSize}
+			defer cw.close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// Negotiate picks the content coding for a response from an Accept-Encoding header, preferring
+// brotli to gzip when the client rates them equally. It returns Identity when neither is
+// acceptable.
+func Negotiate(acceptEncoding string) string {
+	qualities := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(part, ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		quality := 1.0
+		if key, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(key) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		qualities[name] = quality
+	}
+
+	best, bestQuality := Identity, 0.0
+	for _, coding := range []string{Brotli, Gzip} {
+		quality, ok := qualities[coding]
+		if !ok {
+			quality, ok = qualities["*"]
+		}
+		if ok && quality > bestQuality {
+			best, bestQuality = coding, quality
+		}
+	}
+	return best
+}
+
+// compressWriter holds back the status and the first minSize bytes of a response, so that it can
+// decide whether the response is worth compressing before anything is sent.
+type compressWriter struct {
+	http.ResponseWriter
+	coding  string
+	minSize int
+
+	status  int
+	buf     bytes.Buffer
+	decided bool
+	encoder encoder
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	switch {
+	case w.decided || w.status != 0:
+		// Like net/http, ignore a second status.
+	case status < http.StatusOK:
+		w.ResponseWriter.WriteHeader(status)
+	case status == http.StatusNoContent || status == http.StatusNotModified:
+		w.decided = true
+		w.ResponseWriter.WriteHeader(status)
+	default:
+		w.status = status
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 && !w.decided {
+		w.status = http.StatusOK
+	}
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf.Write(b)
+	if w.buf.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush sends what has been written so far. A response flushed before it reaches minSize is still
+// compressed, since more is evidently on its way.
+func (w *compressWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		if err := w.decide(true); err != nil {
+			return
+		}
+	}
+	if w.encoder != nil {
+		if err := w.encoder.Flush(); err != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide sends the status and headers, choosing whether to compress, then writes out anything held
+// back.
+func (w *compressWriter) decide(bigEnough bool) error {
+	w.decided = true
+	header := w.Header()
+	if bigEnough && w.compressible() {
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", w.coding)
+		w.encoder = newEncoder(w.coding, w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+func (w *compressWriter) compressible() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buf.Bytes())
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && compressible[mediaType]
+}
+
+// close finishes the response once the handler returns, sending anything still held back.
+func (w *compressWriter) close() {
+	if !w.decided && w.status != 0 {
+		_ = w.decide(w.buf.Len() >= w.minSize)
+	}
+	if w.encoder == nil {
+		return
+	}
+	_ = w.encoder.Close()
+	w.encoder.Reset(nil)
+	switch w.coding {
+	case Brotli:
+		brotliWriters.Put(w.encoder)
+	case Gzip:
+		gzipWriters.Put(w.encoder)
+	}
+	w.encoder = nil
+}
+
+func newEncoder(coding string, w io.Writer) encoder {
+	var e encoder
+	switch coding {
+	case Brotli:
+		e = brotliWriters.Get().(*brotli.Writer)
+	default:
+		e = gzipWriters.Get().(*gzip.Writer)
+	}
+	e.Reset(w)
+	return e
+}
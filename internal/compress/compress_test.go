@@ -0,0 +1,172 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := map[string]struct {
+		acceptEncoding string
+		expected       string
+	}{
+		"no header":                 {acceptEncoding: "", expected: Identity},
+		"gzip only":                 {acceptEncoding: "gzip", expected: Gzip},
+		"brotli preferred on a tie": {acceptEncoding: "gzip, deflate, br", expected: Brotli},
+		"quality decides":           {acceptEncoding: "br;q=0.5, gzip;q=0.8", expected: Gzip},
+		"refused coding":            {acceptEncoding: "br;q=0, gzip", expected: Gzip},
+		"wildcard":                  {acceptEncoding: "*", expected: Brotli},
+		"wildcard with exclusion":   {acceptEncoding: "*;q=0.5, br;q=0", expected: Gzip},
+		"nothing supported":         {acceptEncoding: "deflate, zstd", expected: Identity},
+		"case insensitive":          {acceptEncoding: "GZIP", expected: Gzip},
+		"malformed quality ignored": {acceptEncoding: "br;q=high, gzip", expected: Gzip},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Negotiate(tc.acceptEncoding))
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	large := `{"persons":[` + strings.Repeat(`{"first_name":"Steve","last_name":"Jobs"},`, 50) + `{}]}`
+
+	tests := map[string]struct {
+		acceptEncoding   string
+		contentType      string
+		contentEncoding  string
+		status           int
+		body             string
+		expectedEncoding string
+	}{
+		"gzip": {
+			acceptEncoding:   "gzip",
+			contentType:      "application/json",
+			status:           http.StatusOK,
+			body:             large,
+			expectedEncoding: Gzip,
+		},
+		"brotli": {
+			acceptEncoding:   "gzip, br",
+			contentType:      "application/json",
+			status:           http.StatusOK,
+			body:             large,
+			expectedEncoding: Brotli,
+		},
+		"error bodies are compressed too": {
+			acceptEncoding:   "gzip",
+			contentType:      "application/json",
+			status:           http.StatusInternalServerError,
+			body:             large,
+			expectedEncoding: Gzip,
+		},
+		"small bodies are not compressed": {
+			acceptEncoding: "gzip",
+			contentType:    "application/json",
+			status:         http.StatusOK,
+			body:           `{"message":"ok"}`,
+		},
+		"client without compression": {
+			contentType: "application/json",
+			status:      http.StatusOK,
+			body:        large,
+		},
+		"incompressible content": {
+			acceptEncoding: "gzip",
+			contentType:    "image/png",
+			status:         http.StatusOK,
+			body:           large,
+		},
+		"already encoded": {
+			acceptEncoding:   "gzip",
+			contentType:      "application/json",
+			contentEncoding:  "zstd",
+			status:           http.StatusOK,
+			body:             large,
+			expectedEncoding: "zstd",
+		},
+		"no content": {
+			acceptEncoding: "gzip",
+			status:         http.StatusNoContent,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler := Middleware(256)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tc.contentType != "" {
+					w.Header().Set("Content-Type", tc.contentType)
+				}
+				if tc.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tc.contentEncoding)
+				}
+				w.WriteHeader(tc.status)
+				// Write in pieces, as a streaming encoder would.
+				for _, chunk := range strings.Split(tc.body, "},") {
+					_, _ = io.WriteString(w, chunk)
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/person", nil)
+			if tc.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			expectedBody := strings.ReplaceAll(tc.body, "},", "")
+			assert.Equal(t, tc.status, rr.Code)
+			assert.Equal(t, tc.expectedEncoding, rr.Header().Get("Content-Encoding"))
+			assert.Equal(t, "Accept-Encoding", rr.Header().Get("Vary"))
+			assert.Equal(t, expectedBody, decode(t, tc.expectedEncoding, rr.Body))
+		})
+	}
+}
+
+func TestMiddlewareFlush(t *testing.T) {
+	flushed := make(chan string, 1)
+	handler := Middleware(1024)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"persons":[`)
+		assert.NoError(t, http.NewResponseController(w).Flush())
+		flushed <- w.Header().Get("Content-Encoding")
+		_, _ = io.WriteString(w, `]}`)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/person", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, Gzip, <-flushed, "a flushed response should be compressed even below the minimum size")
+	assert.True(t, rr.Flushed)
+	assert.Equal(t, `{"persons":[]}`, decode(t, Gzip, rr.Body))
+}
+
+func decode(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+	var reader io.Reader
+	switch encoding {
+	case Gzip:
+		gzipReader, err := gzip.NewReader(body)
+		if !assert.NoError(t, err) {
+			return ""
+		}
+		reader = gzipReader
+	case Brotli:
+		reader = brotli.NewReader(body)
+	default:
+		reader = body
+	}
+	decoded, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(decoded)
+}
@@ -1,36 +1,463 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v11"
-	"github.com/joho/godotenv"
 )
 
+// Configuration is the application configuration. Every field is keyed by its `env` tag, which is
+// also the key used in config files (case-insensitive, with nested sections joined by `_`) and, in
+// lower-kebab-case, the name of its command-line flag. Fields tagged `redact` are masked when the
+// configuration is printed with redaction, and fields tagged `reload` are applied on a SIGHUP reload
+// without restarting the server.
 type Configuration struct {
-	Env                  string     `env:"ENV,required,required"`
-	LogLevel             slog.Level `env:"LOG_LEVEL,required,required"`
-	DBName               string     `env:"DATABASE_NAME,required"`
-	DBUser               string     `env:"DATABASE_USER,required"`
-	DBPassword           string     `env:"DATABASE_PASSWORD,required"`
-	DBHost               string     `env:"DATABASE_HOST,required"`
-	DBPort               string     `env:"DATABASE_PORT,required"`
-	DBRetryDuration      int        `env:"DATABASE_RETRY_DURATION_SECONDS,required"`
-	HTTPPort             string     `env:"HTTP_PORT,required"`
-	HTTPDomain           string     `env:"HTTP_DOMAIN,required"`
-	SwaggerHTTPDomain    string     `env:"SWAGGER_HTTP_DOMAIN,required"`
-	HTTPUseSwagger       bool       `env:"HTTP_USE_SWAGGER,required"`
-	HTTPShutdownDuration int        `env:"HTTP_SHUTDOWN_DURATION,required"`
-}
-
-func New() (Configuration, error) {
-	_ = godotenv.Load()
-
-	cfg, err := env.ParseAs[Configuration]()
+	Env                      string            `env:"ENV" envDefault:"production"`
+	LogLevel                 slog.Level        `env:"LOG_LEVEL" envDefault:"info" reload:"true"`
+	LogFormat                string            `env:"LOG_FORMAT" envDefault:"text"`
+	LogConcise               bool              `env:"LOG_CONCISE" envDefault:"true"`
+	LogSubsystemLevels       map[string]string `env:"LOG_SUBSYSTEM_LEVELS" reload:"true"`
+	LogRequestHeaders        bool              `env:"LOG_REQUEST_HEADERS" envDefault:"false"`
+	LogResponseHeaders       bool              `env:"LOG_RESPONSE_HEADERS" envDefault:"false"`
+	LogRequestBody           bool              `env:"LOG_REQUEST_BODY" envDefault:"false"`
+	LogResponseBody          bool              `env:"LOG_RESPONSE_BODY" envDefault:"false"`
+	LogBodyMaxBytes          int               `env:"LOG_BODY_MAX_BYTES" envDefault:"2048"`
+	LogRedactFields          []string          `env:"LOG_REDACT_FIELDS" envDefault:"authorization,cookie,set-cookie,password,age,email,phone,date_of_birth"`
+	DBURL                    string            `env:"DATABASE_URL" redact:"url"`
+	DBName                   string            `env:"DATABASE_NAME" envDefault:"postgres"`
+	DBUser                   string            `env:"DATABASE_USER" envDefault:"postgres"`
+	DBPassword               string            `env:"DATABASE_PASSWORD" redact:"true"`
+	DBHost                   string            `env:"DATABASE_HOST" envDefault:"localhost"`
+	DBPort                   string            `env:"DATABASE_PORT" envDefault:"5432"`
+	DBRetryDuration          int               `env:"DATABASE_RETRY_DURATION_SECONDS" envDefault:"30"`
+	DBRetryMaxAttempts       int               `env:"DATABASE_RETRY_MAX_ATTEMPTS" envDefault:"0"`
+	DBRetryInitialBackoff    time.Duration     `env:"DATABASE_RETRY_INITIAL_BACKOFF" envDefault:"100ms"`
+	DBRetryMaxBackoff        time.Duration     `env:"DATABASE_RETRY_MAX_BACKOFF" envDefault:"5s"`
+	DBPingTimeout            int               `env:"DATABASE_PING_TIMEOUT_SECONDS" envDefault:"2"`
+	DBMaxOpenConns           int               `env:"DATABASE_MAX_OPEN_CONNS" envDefault:"25"`
+	DBMinConns               int               `env:"DATABASE_MIN_CONNS" envDefault:"0"`
+	DBConnMaxLifetime        time.Duration     `env:"DATABASE_CONN_MAX_LIFETIME" envDefault:"30m"`
+	DBConnMaxIdleTime        time.Duration     `env:"DATABASE_CONN_MAX_IDLE_TIME" envDefault:"5m"`
+	DBStatementTimeout       time.Duration     `env:"DATABASE_STATEMENT_TIMEOUT" envDefault:"5s"`
+	DBStreamTimeout          time.Duration     `env:"DATABASE_STREAM_TIMEOUT" envDefault:"30s"`
+	DBHealthCheckPeriod      time.Duration     `env:"DATABASE_HEALTH_CHECK_PERIOD" envDefault:"1m"`
+	DBQueryExecMode          string            `env:"DATABASE_QUERY_EXEC_MODE" envDefault:"cache_statement"`
+	DBStatementCacheCapacity int               `env:"DATABASE_STATEMENT_CACHE_CAPACITY" envDefault:"512"`
+	DBApplicationName        string            `env:"DATABASE_APPLICATION_NAME" envDefault:"go-api-tech-challenge"`
+	DBReplicaURLs            []string          `env:"DATABASE_REPLICA_URLS" redact:"url"`
+	DBReplicaCheckInterval   time.Duration     `env:"DATABASE_REPLICA_CHECK_INTERVAL" envDefault:"5s"`
+	DBReadYourWritesWindow   time.Duration     `env:"DATABASE_READ_YOUR_WRITES_WINDOW" envDefault:"5s"`
+	CacheMaxEntries          int               `env:"CACHE_MAX_ENTRIES" envDefault:"1000"`
+	CacheTTL                 time.Duration     `env:"CACHE_TTL" envDefault:"30s"`
+	ImportMaxRows            int               `env:"IMPORT_MAX_ROWS" envDefault:"10000"`
+	BatchMaxOperations       int               `env:"BATCH_MAX_OPERATIONS" envDefault:"100"`
+	IdempotencyKeyTTL        time.Duration     `env:"IDEMPOTENCY_KEY_TTL" envDefault:"24h"`
+	SearchBackend            string            `env:"SEARCH_BACKEND" envDefault:"postgres"`
+	HTTPPort                 string            `env:"HTTP_PORT" envDefault:":8000"`
+	HTTPDomain               string            `env:"HTTP_DOMAIN"`
+	SwaggerHTTPDomain        string            `env:"SWAGGER_HTTP_DOMAIN" envDefault:"localhost"`
+	HTTPUseSwagger           bool              `env:"HTTP_USE_SWAGGER" envDefault:"false"`
+	HTTPShutdownDuration     int               `env:"HTTP_SHUTDOWN_DURATION" envDefault:"10"`
+	HTTPDrainDuration        int               `env:"HTTP_DRAIN_DURATION" envDefault:"5"`
+	HTTPReadHeaderTimeout    time.Duration     `env:"HTTP_READ_HEADER_TIMEOUT" envDefault:"5s"`
+	HTTPReadTimeout          time.Duration     `env:"HTTP_READ_TIMEOUT" envDefault:"30s"`
+	HTTPWriteTimeout         time.Duration     `env:"HTTP_WRITE_TIMEOUT" envDefault:"60s"`
+	HTTPIdleTimeout          time.Duration     `env:"HTTP_IDLE_TIMEOUT" envDefault:"2m"`
+	HTTPMaxHeaderBytes       int               `env:"HTTP_MAX_HEADER_BYTES" envDefault:"1048576"`
+	HTTPCacheMaxAge          time.Duration     `env:"HTTP_CACHE_MAX_AGE" envDefault:"0s"`
+	HTTPCompression          bool              `env:"HTTP_COMPRESSION" envDefault:"true"`
+	HTTPCompressionMinBytes  int               `env:"HTTP_COMPRESSION_MIN_BYTES" envDefault:"1024"`
+	HTTP2Enabled             bool              `env:"HTTP2_ENABLED" envDefault:"true"`
+	HTTP2Cleartext           bool              `env:"HTTP2_CLEARTEXT" envDefault:"false"`
+	TLSCertFile              string            `env:"TLS_CERT_FILE"`
+	TLSKeyFile               string            `env:"TLS_KEY_FILE"`
+	TLSClientCAFile          string            `env:"TLS_CLIENT_CA_FILE"`
+	TLSClientAuth            string            `env:"TLS_CLIENT_AUTH"`
+	TLSMinVersion            string            `env:"TLS_MIN_VERSION" envDefault:"1.2"`
+	TLSReloadInterval        time.Duration     `env:"TLS_RELOAD_INTERVAL" envDefault:"1m"`
+	TracingExporter          string            `env:"TRACING_EXPORTER" envDefault:"none"`
+	TracingServiceName       string            `env:"TRACING_SERVICE_NAME" envDefault:"go-api-tech-challenge"`
+	TracingOTLPEndpoint      string            `env:"TRACING_OTLP_ENDPOINT"`
+	TracingOTLPInsecure      bool              `env:"TRACING_OTLP_INSECURE"`
+	TracingFilePath          string            `env:"TRACING_FILE_PATH" envDefault:"traces.json"`
+	TracingSampleRatio       float64           `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+	RateLimitRPS             float64           `env:"RATE_LIMIT_REQUESTS_PER_SECOND" envDefault:"0" reload:"true"`
+	RateLimitBurst           int               `env:"RATE_LIMIT_BURST" envDefault:"20" reload:"true"`
+	CORSAllowedOrigins       []string          `env:"CORS_ALLOWED_ORIGINS" reload:"true"`
+	CORSAllowedMethods       []string          `env:"CORS_ALLOWED_METHODS" envDefault:"GET,POST,PUT,PATCH,DELETE,OPTIONS" reload:"true"`
+	CORSAllowedHeaders       []string          `env:"CORS_ALLOWED_HEADERS" envDefault:"Accept,Authorization,Content-Type,X-Request-ID,Traceparent,X-Read-Consistency,Idempotency-Key" reload:"true"`
+	CORSExposedHeaders       []string          `env:"CORS_EXPOSED_HEADERS" envDefault:"X-Request-ID,Traceparent,Retry-After,Content-Disposition,Idempotent-Replayed" reload:"true"`
+	CORSAllowCredentials     bool              `env:"CORS_ALLOW_CREDENTIALS" envDefault:"false" reload:"true"`
+	CORSMaxAge               int               `env:"CORS_MAX_AGE" envDefault:"300" reload:"true"`
+}
+
+// New builds the configuration from, in increasing order of precedence: the defaults in the
+// `envDefault` tags, an optional YAML or TOML config file, environment variables (including any
+// set in a `.env` file) and command-line flags. The config file is named by the `--config` flag or
+// the CONFIG_FILE environment variable. The result is validated before it is returned.
+func New(args []string) (Configuration, error) {
+	return load(args, "")
+}
+
+// load builds the configuration as described on New. Unless appliedEnv is empty, the default CORS
+// origins are those of appliedEnv rather than of the ENV just read, so that a reload does not
+// partly switch to an environment that only takes effect after a restart.
+func load(args []string, appliedEnv string) (Configuration, error) {
+	flagValues, configFile, err := parseFlags(args)
+	if err != nil {
+		return Configuration{}, fmt.Errorf("[in config.New] failed to parse flags: %w", err)
+	}
+
+	// .env is read rather than loaded into the process environment so that edits to it are picked
+	// up by a reload. Variables set in the real environment take precedence over it.
+	environment, err := readDotEnv()
+	if err != nil {
+		environment = map[string]string{}
+	}
+	merge(environment, env.ToMap(environ()))
+	if configFile == "" {
+		configFile = environment["CONFIG_FILE"]
+	}
+
+	sources := map[string]string{}
+	if configFile != "" {
+		fileValues, err := loadFile(configFile)
+		if err != nil {
+			return Configuration{}, fmt.Errorf("[in config.New] failed to load config file: %w", err)
+		}
+		merge(sources, fileValues)
+	}
+	merge(sources, environment)
+	merge(sources, flagValues)
+
+	cfg, err := env.ParseAsWithOptions[Configuration](env.Options{Environment: sources})
 	if err != nil {
 		return Configuration{}, fmt.Errorf("[in config.New] failed to parse config: %w", err)
 	}
+	if _, ok := sources["CORS_ALLOWED_ORIGINS"]; !ok {
+		if appliedEnv == "" {
+			appliedEnv = cfg.Env
+		}
+		cfg.CORSAllowedOrigins = defaultCORSOrigins(appliedEnv)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Configuration{}, fmt.Errorf("[in config.New] invalid config: %w", err)
+	}
 
 	return cfg, nil
 }
+
+// Validate checks the configuration for values that parse but cannot work, reporting every problem
+// found rather than only the first.
+func (c Configuration) Validate() error {
+	var errs []error
+
+	for i, replica := range c.DBReplicaURLs {
+		if _, err := parsePostgresURL(fmt.Sprintf("DATABASE_REPLICA_URLS entry %d", i+1), replica); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if c.DBURL != "" {
+		if _, err := c.parseDBURL(); err != nil {
+			errs = append(errs, err)
+		}
+	} else {
+		if c.DBPassword == "" {
+			errs = append(errs, errors.New("DATABASE_PASSWORD is required when DATABASE_URL is not set"))
+		}
+		if err := validatePort("DATABASE_PORT", c.DBPort); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := validatePort("HTTP_PORT", strings.TrimPrefix(c.HTTPPort, ":")); err != nil {
+		errs = append(errs, err)
+	}
+
+	positive := map[string]int{
+		"DATABASE_RETRY_DURATION_SECONDS": c.DBRetryDuration,
+		"DATABASE_PING_TIMEOUT_SECONDS":   c.DBPingTimeout,
+		"HTTP_SHUTDOWN_DURATION":          c.HTTPShutdownDuration,
+		"IMPORT_MAX_ROWS":                 c.ImportMaxRows,
+		"BATCH_MAX_OPERATIONS":            c.BatchMaxOperations,
+	}
+	for _, name := range sortedKeys(positive) {
+		if positive[name] <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %d", name, positive[name]))
+		}
+	}
+
+	nonNegative := map[string]int{
+		"DATABASE_MAX_OPEN_CONNS":           c.DBMaxOpenConns,
+		"DATABASE_RETRY_MAX_ATTEMPTS":       c.DBRetryMaxAttempts,
+		"DATABASE_MIN_CONNS":                c.DBMinConns,
+		"DATABASE_STATEMENT_CACHE_CAPACITY": c.DBStatementCacheCapacity,
+		"CACHE_MAX_ENTRIES":                 c.CacheMaxEntries,
+		"HTTP_DRAIN_DURATION":               c.HTTPDrainDuration,
+		"HTTP_COMPRESSION_MIN_BYTES":        c.HTTPCompressionMinBytes,
+		"LOG_BODY_MAX_BYTES":                c.LogBodyMaxBytes,
+	}
+	for _, name := range sortedKeys(nonNegative) {
+		if nonNegative[name] < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %d", name, nonNegative[name]))
+		}
+	}
+
+	timeouts := map[string]time.Duration{
+		"HTTP_READ_HEADER_TIMEOUT":         c.HTTPReadHeaderTimeout,
+		"HTTP_READ_TIMEOUT":                c.HTTPReadTimeout,
+		"HTTP_WRITE_TIMEOUT":               c.HTTPWriteTimeout,
+		"HTTP_IDLE_TIMEOUT":                c.HTTPIdleTimeout,
+		"TLS_RELOAD_INTERVAL":              c.TLSReloadInterval,
+		"DATABASE_CONN_MAX_LIFETIME":       c.DBConnMaxLifetime,
+		"DATABASE_CONN_MAX_IDLE_TIME":      c.DBConnMaxIdleTime,
+		"DATABASE_STATEMENT_TIMEOUT":       c.DBStatementTimeout,
+		"DATABASE_STREAM_TIMEOUT":          c.DBStreamTimeout,
+		"DATABASE_HEALTH_CHECK_PERIOD":     c.DBHealthCheckPeriod,
+		"DATABASE_RETRY_INITIAL_BACKOFF":   c.DBRetryInitialBackoff,
+		"DATABASE_RETRY_MAX_BACKOFF":       c.DBRetryMaxBackoff,
+		"DATABASE_READ_YOUR_WRITES_WINDOW": c.DBReadYourWritesWindow,
+		"HTTP_CACHE_MAX_AGE":               c.HTTPCacheMaxAge,
+		"IDEMPOTENCY_KEY_TTL":              c.IdempotencyKeyTTL,
+	}
+	for _, name := range sortedKeys(timeouts) {
+		if timeouts[name] < 0 {
+			errs = append(errs, fmt.Errorf("%s must not be negative, got %s", name, timeouts[name]))
+		}
+	}
+	if c.DBMaxOpenConns > 0 && c.DBMinConns > c.DBMaxOpenConns {
+		errs = append(errs, fmt.Errorf("DATABASE_MIN_CONNS (%d) must not exceed DATABASE_MAX_OPEN_CONNS (%d)", c.DBMinConns, c.DBMaxOpenConns))
+	}
+	if err := validateOneOf("DATABASE_QUERY_EXEC_MODE", c.DBQueryExecMode,
+		"cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol"); err != nil {
+		errs = append(errs, err)
+	}
+	if len(c.DBReplicaURLs) > 0 && c.DBReplicaCheckInterval <= 0 {
+		errs = append(errs, fmt.Errorf("DATABASE_REPLICA_CHECK_INTERVAL must be positive when replicas are set, got %s", c.DBReplicaCheckInterval))
+	}
+	if c.CacheMaxEntries > 0 && c.CacheTTL <= 0 {
+		errs = append(errs, fmt.Errorf("CACHE_TTL must be positive when the cache is enabled, got %s", c.CacheTTL))
+	}
+	if c.HTTPMaxHeaderBytes <= 0 {
+		errs = append(errs, fmt.Errorf("HTTP_MAX_HEADER_BYTES must be positive, got %d", c.HTTPMaxHeaderBytes))
+	}
+	errs = append(errs, c.validateTLS()...)
+
+	errs = append(errs, c.validateCORS()...)
+
+	if c.RateLimitRPS < 0 {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_REQUESTS_PER_SECOND must not be negative, got %g", c.RateLimitRPS))
+	}
+	if c.RateLimitRPS > 0 && c.RateLimitBurst < 1 {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_BURST must be at least 1 when rate limiting is enabled, got %d", c.RateLimitBurst))
+	}
+	for _, subsystem := range sortedKeys(c.LogSubsystemLevels) {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(c.LogSubsystemLevels[subsystem])); err != nil {
+			errs = append(errs, fmt.Errorf("LOG_SUBSYSTEM_LEVELS has an invalid level for %q: %w", subsystem, err))
+		}
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", c.TracingSampleRatio))
+	}
+	if err := validateOneOf("TRACING_EXPORTER", c.TracingExporter, "none", "otlp", "stdout", "file"); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateOneOf("LOG_FORMAT", strings.ToLower(c.LogFormat), "text", "json"); err != nil {
+		errs = append(errs, err)
+	}
+	if err := validateOneOf("SEARCH_BACKEND", c.SearchBackend, "postgres", "substring"); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (c Configuration) validateTLS() []error {
+	var errs []error
+	if (c.TLSCertFile == "") != (c.TLSKeyFile == "") {
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE must be set together"))
+	}
+	if !c.TLSEnabled() && (c.TLSClientCAFile != "" || c.TLSClientAuth != "") {
+		errs = append(errs, errors.New("TLS_CLIENT_CA_FILE and TLS_CLIENT_AUTH require TLS_CERT_FILE and TLS_KEY_FILE"))
+	}
+	if c.TLSClientAuth != "" {
+		if err := validateOneOf("TLS_CLIENT_AUTH", c.TLSClientAuth, "none", "request", "verify_if_given", "require"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if (c.TLSClientAuth == "verify_if_given" || c.TLSClientAuth == "require") && c.TLSClientCAFile == "" {
+		errs = append(errs, fmt.Errorf("TLS_CLIENT_AUTH %q requires TLS_CLIENT_CA_FILE", c.TLSClientAuth))
+	}
+	if err := validateOneOf("TLS_MIN_VERSION", c.TLSMinVersion, "1.2", "1.3"); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func (c Configuration) validateCORS() []error {
+	var errs []error
+	for _, origin := range c.CORSAllowedOrigins {
+		switch {
+		case origin == "*":
+			if c.CORSAllowCredentials {
+				errs = append(errs, errors.New("CORS_ALLOWED_ORIGINS cannot be * when CORS_ALLOW_CREDENTIALS is set"))
+			}
+		case !strings.Contains(origin, "://"):
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q must include a scheme, e.g. https://", origin))
+		case strings.Count(origin, "*") > 1:
+			errs = append(errs, fmt.Errorf("CORS_ALLOWED_ORIGINS entry %q may contain at most one *", origin))
+		}
+	}
+	if c.CORSMaxAge < 0 {
+		errs = append(errs, fmt.Errorf("CORS_MAX_AGE must not be negative, got %d", c.CORSMaxAge))
+	}
+	return errs
+}
+
+// defaultCORSOrigins returns the origins allowed when CORS_ALLOWED_ORIGINS is not set. Development
+// and test environments accept any origin; every other environment must list its origins.
+func defaultCORSOrigins(environment string) []string {
+	switch strings.ToLower(environment) {
+	case "development", "dev", "local", "test":
+		return []string{"*"}
+	}
+	return []string{}
+}
+
+// TLSEnabled reports whether the server should serve HTTPS.
+func (c Configuration) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// ClientAuth returns the client certificate policy. It defaults to requiring a verified client
+// certificate when a client CA is configured and to none otherwise.
+func (c Configuration) ClientAuth() string {
+	if c.TLSClientAuth != "" {
+		return c.TLSClientAuth
+	}
+	if c.TLSClientCAFile != "" {
+		return "require"
+	}
+	return "none"
+}
+
+// DatabaseConnString returns DATABASE_URL when it is set, and otherwise builds a key/value
+// connection string from the individual DATABASE_* settings. DATABASE_APPLICATION_NAME is added as
+// application_name, which identifies the service in pg_stat_activity, unless the URL already sets
+// one.
+func (c Configuration) DatabaseConnString() string {
+	if c.DBURL != "" {
+		return c.withApplicationName(c.DBURL)
+	}
+
+	connString := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		quoteConnValue(c.DBHost),
+		quoteConnValue(c.DBUser),
+		quoteConnValue(c.DBPassword),
+		quoteConnValue(c.DBName),
+		quoteConnValue(c.DBPort),
+	)
+	if c.DBApplicationName != "" {
+		connString += " application_name=" + quoteConnValue(c.DBApplicationName)
+	}
+	return connString
+}
+
+// ReplicaConnStrings returns the connection string of every read replica, with application_name
+// added as for DatabaseConnString.
+func (c Configuration) ReplicaConnStrings() []string {
+	connStrings := make([]string, len(c.DBReplicaURLs))
+	for i, replica := range c.DBReplicaURLs {
+		connStrings[i] = c.withApplicationName(replica)
+	}
+	return connStrings
+}
+
+// withApplicationName adds DATABASE_APPLICATION_NAME to a connection URL that does not set
+// application_name itself.
+func (c Configuration) withApplicationName(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || c.DBApplicationName == "" {
+		return rawURL
+	}
+	query := u.Query()
+	if query.Get("application_name") == "" {
+		query.Set("application_name", c.DBApplicationName)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// Secrets returns the literal secret values in the configuration so that they can be masked in
+// logs.
+func (c Configuration) Secrets() []string {
+	secrets := []string{c.DBPassword}
+	for _, rawURL := range append([]string{c.DBURL}, c.DBReplicaURLs...) {
+		if u, err := url.Parse(rawURL); err == nil && u.User != nil {
+			if password, ok := u.User.Password(); ok {
+				secrets = append(secrets, password)
+			}
+		}
+	}
+	return secrets
+}
+
+func (c Configuration) parseDBURL() (*url.URL, error) {
+	return parsePostgresURL("DATABASE_URL", c.DBURL)
+}
+
+func parsePostgresURL(name string, rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a valid URL: %w", name, err)
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		return nil, fmt.Errorf("%s must use the postgres:// scheme, got %q", name, u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("%s must include a host", name)
+	}
+	if port := u.Port(); port != "" {
+		if err := validatePort(name+" port", port); err != nil {
+			return nil, err
+		}
+	}
+	return u, nil
+}
+
+func validatePort(name string, value string) error {
+	port, err := strconv.Atoi(value)
+	if err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("%s must be a port between 1 and 65535, got %q", name, value)
+	}
+	return nil
+}
+
+func validateOneOf(name string, value string, allowed ...string) error {
+	for _, a := range allowed {
+		if value == a {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s must be one of %s, got %q", name, strings.Join(allowed, ", "), value)
+}
+
+// quoteConnValue quotes a value for a key/value connection string when it contains characters
+// that would otherwise end the value.
+func quoteConnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `'`, `\'`)
+	return "'" + value + "'"
+}
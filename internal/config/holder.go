@@ -0,0 +1,120 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Holder holds the live configuration. Readers call Load on every use instead of keeping a copy, so
+// that they see settings applied by Reload.
+type Holder struct {
+	args    []string
+	current atomic.Pointer[Configuration]
+	// mu serializes reloads; Load never blocks.
+	mu sync.Mutex
+}
+
+// Change describes a setting whose value differs between two configurations. Values are rendered
+// with secrets redacted so that changes can be logged.
+type Change struct {
+	Key string
+	Old string
+	New string
+	// Applied is false for settings that only take effect after a restart.
+	Applied bool
+}
+
+// NewHolder returns a Holder serving cfg. args are the command-line arguments cfg was built from,
+// which are re-applied on every reload so that flags keep their precedence.
+func NewHolder(cfg Configuration, args []string) *Holder {
+	h := &Holder{args: args}
+	h.current.Store(&cfg)
+	return h
+}
+
+// Load returns the current configuration.
+func (h *Holder) Load() Configuration {
+	return *h.current.Load()
+}
+
+// Reload rebuilds the configuration from its sources and swaps in the settings that are safe to
+// change while running. An invalid configuration is rejected and the current one is kept. Changes
+// to other settings are returned with Applied set to false.
+func (h *Holder) Reload() ([]Change, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	current := h.Load()
+	loaded, err := load(h.args, current.Env)
+	if err != nil {
+		return nil, fmt.Errorf("[in config.Holder.Reload] %w", err)
+	}
+
+	next := current
+	changes := Diff(current, loaded)
+
+	nextValue := reflect.ValueOf(&next).Elem()
+	loadedValue := reflect.ValueOf(loaded)
+	for _, field := range reflect.VisibleFields(nextValue.Type()) {
+		if reloadable(field) {
+			nextValue.FieldByIndex(field.Index).Set(loadedValue.FieldByIndex(field.Index))
+		}
+	}
+
+	h.current.Store(&next)
+	return changes, nil
+}
+
+// Diff returns the settings that differ between old and new, in declaration order.
+func Diff(old Configuration, new Configuration) []Change {
+	oldValue := reflect.ValueOf(old)
+	newValue := reflect.ValueOf(new)
+
+	var changes []Change
+	for _, field := range reflect.VisibleFields(oldValue.Type()) {
+		key := envKey(field)
+		if key == "" {
+			continue
+		}
+		o := oldValue.FieldByIndex(field.Index).Interface()
+		n := newValue.FieldByIndex(field.Index).Interface()
+		if reflect.DeepEqual(o, n) {
+			continue
+		}
+		changes = append(changes, Change{
+			Key:     key,
+			Old:     format(redact(field.Tag.Get("redact"), o)),
+			New:     format(redact(field.Tag.Get("redact"), n)),
+			Applied: reloadable(field),
+		})
+	}
+	return changes
+}
+
+func reloadable(field reflect.StructField) bool {
+	return field.Tag.Get("reload") == "true"
+}
+
+// format renders a setting the way it would be written in the environment.
+func format(value any) string {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Slice:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(v.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	case reflect.Map:
+		pairs := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			pairs = append(pairs, fmt.Sprintf("%v:%v", key.Interface(), v.MapIndex(key).Interface()))
+		}
+		slices.Sort(pairs)
+		return strings.Join(pairs, ",")
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHolderReload(t *testing.T) {
+	tests := map[string]struct {
+		environ         []string
+		expectedErr     string
+		expectedChanges []Change
+		expectCfg       func(t *testing.T, cfg Configuration)
+	}{
+		"applies reloadable settings": {
+			environ: []string{"DATABASE_PASSWORD=secret", "LOG_LEVEL=debug", "CORS_ALLOWED_ORIGINS=https://a.example,https://b.example"},
+			expectedChanges: []Change{
+				{Key: "LOG_LEVEL", Old: "INFO", New: "DEBUG", Applied: true},
+				{Key: "CORS_ALLOWED_ORIGINS", Old: "", New: "https://a.example,https://b.example", Applied: true},
+			},
+			expectCfg: func(t *testing.T, cfg Configuration) {
+				assert.Equal(t, "DEBUG", cfg.LogLevel.String())
+				assert.Equal(t, []string{"https://a.example", "https://b.example"}, cfg.CORSAllowedOrigins)
+			},
+		},
+		"keeps settings that need a restart": {
+			environ: []string{"DATABASE_PASSWORD=rotated", "HTTP_PORT=:9000"},
+			expectedChanges: []Change{
+				{Key: "DATABASE_PASSWORD", Old: "***", New: "***", Applied: false},
+				{Key: "HTTP_PORT", Old: ":8000", New: ":9000", Applied: false},
+			},
+			expectCfg: func(t *testing.T, cfg Configuration) {
+				assert.Equal(t, ":8000", cfg.HTTPPort)
+				assert.Equal(t, "secret", cfg.DBPassword)
+			},
+		},
+		"keeps default origins of the running environment": {
+			environ: []string{"DATABASE_PASSWORD=secret", "ENV=development"},
+			expectedChanges: []Change{
+				{Key: "ENV", Old: "production", New: "development", Applied: false},
+			},
+			expectCfg: func(t *testing.T, cfg Configuration) {
+				assert.Equal(t, "production", cfg.Env)
+				assert.Empty(t, cfg.CORSAllowedOrigins)
+			},
+		},
+		"rejects invalid config": {
+			environ:     []string{"DATABASE_PASSWORD=secret", "RATE_LIMIT_REQUESTS_PER_SECOND=-1"},
+			expectedErr: "RATE_LIMIT_REQUESTS_PER_SECOND must not be negative",
+			expectCfg: func(t *testing.T, cfg Configuration) {
+				assert.Equal(t, float64(0), cfg.RateLimitRPS)
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			setEnviron(t, "DATABASE_PASSWORD=secret")
+			cfg, err := New(nil)
+			assert.NoError(t, err)
+			holder := NewHolder(cfg, nil)
+
+			setEnviron(t, test.environ...)
+			changes, err := holder.Reload()
+
+			if test.expectedErr != "" {
+				assert.ErrorContains(t, err, test.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, test.expectedChanges, changes)
+			test.expectCfg(t, holder.Load())
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Print writes the configuration to w as YAML keyed by environment variable name, so the output can
+// be used as a config file. When redacted is set, fields tagged `redact` are masked and
+// DATABASE_URL is printed without its password.
+func (c Configuration) Print(w io.Writer, redacted bool) error {
+	values := map[string]any{}
+
+	v := reflect.ValueOf(c)
+	for _, field := range reflect.VisibleFields(v.Type()) {
+		key := envKey(field)
+		if key == "" {
+			continue
+		}
+		value := v.FieldByIndex(field.Index).Interface()
+
+		if redacted {
+			value = redact(field.Tag.Get("redact"), value)
+		}
+		if stringer, ok := value.(fmt.Stringer); ok {
+			value = stringer.String()
+		}
+		values[key] = value
+	}
+
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("[in config.Print] failed to marshal config: %w", err)
+	}
+	if _, err := w.Write(out); err != nil {
+		return fmt.Errorf("[in config.Print] failed to write config: %w", err)
+	}
+	return nil
+}
+
+// redact masks a value, or each value in a list, according to its field's `redact` tag.
+func redact(mode string, value any) any {
+	if list, ok := value.([]string); ok {
+		redacted := make([]string, len(list))
+		for i, item := range list {
+			redacted[i] = redact(mode, item).(string)
+		}
+		return redacted
+	}
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return value
+	}
+
+	switch strings.ToLower(mode) {
+	case "true":
+		return redactedValue
+	case "url":
+		u, err := url.Parse(s)
+		if err != nil {
+			return redactedValue
+		}
+		return u.Redacted()
+	}
+	return value
+}
+
+const redactedValue = "***"
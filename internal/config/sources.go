@@ -0,0 +1,157 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/caarlos0/env/v11"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// environ and readDotEnv are swapped out in tests.
+var (
+	environ    = os.Environ
+	readDotEnv = func() (map[string]string, error) { return godotenv.Read() }
+)
+
+// parseFlags parses command-line flags. Every configuration key has a flag named after it in
+// lower-kebab-case, e.g. `--http-port` for HTTP_PORT. Only flags that were set are returned.
+func parseFlags(args []string) (map[string]string, string, error) {
+	flags := flag.NewFlagSet("api", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+
+	configFile := flags.String("config", "", "path to a YAML or TOML config file")
+	values := map[string]*string{}
+	for _, key := range keys() {
+		values[key] = flags.String(flagName(key), "", "overrides "+key)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, "", err
+	}
+	if flags.NArg() > 0 {
+		return nil, "", fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+
+	set := map[string]string{}
+	flags.Visit(func(f *flag.Flag) {
+		for key, value := range values {
+			if flagName(key) == f.Name {
+				set[key] = *value
+			}
+		}
+	})
+
+	return set, *configFile, nil
+}
+
+// loadFile reads a YAML or TOML config file, chosen by extension, into a map keyed like the
+// environment.
+func loadFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &doc)
+	case ".toml":
+		err = toml.Unmarshal(data, &doc)
+	default:
+		return nil, fmt.Errorf("unsupported config file type %q, want .yaml, .yml or .toml", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	mapKeys := map[string]bool{}
+	for _, field := range reflect.VisibleFields(reflect.TypeOf(Configuration{})) {
+		if field.Type.Kind() == reflect.Map {
+			mapKeys[envKey(field)] = true
+		}
+	}
+
+	values := map[string]string{}
+	flatten(values, mapKeys, "", doc)
+	return values, nil
+}
+
+// flatten converts nested sections into environment-style keys, so that `database: {host: db}`
+// becomes DATABASE_HOST=db. Maps under a key whose field is itself a map are encoded the way the
+// env parser expects, as `key:value,key:value`.
+func flatten(values map[string]string, mapKeys map[string]bool, prefix string, value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		if mapKeys[prefix] {
+			pairs := make([]string, 0, len(v))
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				pairs = append(pairs, fmt.Sprintf("%s:%v", key, v[key]))
+			}
+			values[prefix] = strings.Join(pairs, ",")
+			return
+		}
+		for key, member := range v {
+			key = strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+			if prefix != "" {
+				key = prefix + "_" + key
+			}
+			flatten(values, mapKeys, key, member)
+		}
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = fmt.Sprint(item)
+		}
+		values[prefix] = strings.Join(items, ",")
+	case nil:
+	default:
+		values[prefix] = fmt.Sprint(v)
+	}
+}
+
+// keys returns every configuration key in declaration order.
+func keys() []string {
+	params, err := env.GetFieldParams(&Configuration{})
+	if err != nil {
+		panic(fmt.Sprintf("invalid Configuration tags: %v", err))
+	}
+	names := make([]string, len(params))
+	for i, p := range params {
+		names[i] = p.Key
+	}
+	return names
+}
+
+func envKey(field reflect.StructField) string {
+	key, _, _ := strings.Cut(field.Tag.Get("env"), ",")
+	return key
+}
+
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// merge copies src into dst, overwriting existing keys. Empty values are skipped, matching the env
+// parser, which treats an empty variable as unset and falls back to the default.
+func merge(dst map[string]string, src map[string]string) {
+	for key, value := range src {
+		if value != "" {
+			dst[key] = value
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
@@ -0,0 +1,64 @@
+// Package consistency decides per request whether reads may be served by a lagging read replica or
+// must go to the primary so that clients see their own writes.
+package consistency
+
+import (
+	"go-api-tech-challenge/internal/database"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// Header lets a client ask for reads from the primary with the value "strong".
+	Header = "X-Read-Consistency"
+	// Strong is the Header value requesting reads from the primary.
+	Strong = "strong"
+	// CookieName is the cookie that pins a client's reads to the primary after it writes.
+	CookieName = "read_primary"
+)
+
+// Middleware routes a request's reads to the primary when the client sends `X-Read-Consistency:
+// strong`, or when it made a change within the last window. Every request with an unsafe method
+// sets a cookie that lasts window, so that a client reading back what it just wrote is not served
+// by a replica that has not caught up. A zero window disables the cookie; the header still works.
+func Middleware(window time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Vary", Header)
+
+			if primaryRequested(r) {
+				r = r.WithContext(database.WithPrimary(r.Context()))
+			}
+			if window > 0 && !safeMethod(r.Method) {
+				http.SetCookie(w, &http.Cookie{
+					Name:     CookieName,
+					Value:    "1",
+					Path:     "/",
+					MaxAge:   int(math.Ceil(window.Seconds())),
+					HttpOnly: true,
+					SameSite: http.SameSiteLaxMode,
+				})
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func primaryRequested(r *http.Request) bool {
+	if strings.EqualFold(strings.TrimSpace(r.Header.Get(Header)), Strong) {
+		return true
+	}
+	_, err := r.Cookie(CookieName)
+	return err == nil
+}
+
+func safeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package consistency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := map[string]struct {
+		window          time.Duration
+		method          string
+		header          string
+		cookie          bool
+		expectedPrimary bool
+		expectedCookie  string
+	}{
+		"reads use replicas by default": {
+			window: 5 * time.Second,
+			method: http.MethodGet,
+		},
+		"strong consistency header": {
+			window:          5 * time.Second,
+			method:          http.MethodGet,
+			header:          "Strong",
+			expectedPrimary: true,
+		},
+		"recent write pins reads to primary": {
+			window:          5 * time.Second,
+			method:          http.MethodGet,
+			cookie:          true,
+			expectedPrimary: true,
+		},
+		"write sets the cookie": {
+			window:         1500 * time.Millisecond,
+			method:         http.MethodPost,
+			expectedCookie: "read_primary=1; Path=/; Max-Age=2; HttpOnly; SameSite=Lax",
+		},
+		"zero window disables the cookie": {
+			method: http.MethodDelete,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var primary bool
+			handler := Middleware(tc.window)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				primary = database.PrimaryRequired(r.Context())
+			}))
+
+			req := httptest.NewRequest(tc.method, "/api/person", nil)
+			if tc.header != "" {
+				req.Header.Set(Header, tc.header)
+			}
+			if tc.cookie {
+				req.AddCookie(&http.Cookie{Name: CookieName, Value: "1"})
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedPrimary, primary)
+			assert.Equal(t, tc.expectedCookie, rr.Header().Get("Set-Cookie"))
+			assert.Equal(t, Header, rr.Header().Get("Vary"))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package corspolicy
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/go-chi/cors"
+)
+
+// Options is the cross-origin policy. AllowedOrigins entries are exact origins such as
+// `https://app.example.com`, origins with one wildcard such as `https://*.example.com`, or `*` for
+// any origin. An empty list rejects every cross-origin request.
+type Options struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how many seconds browsers may cache a preflight response.
+	MaxAge int
+}
+
+// Policy is a CORS middleware whose options can be replaced while the server is running.
+type Policy struct {
+	current atomic.Pointer[cors.Cors]
+}
+
+// New returns a Policy enforcing opts.
+func New(opts Options) *Policy {
+	p := &Policy{}
+	p.Set(opts)
+	return p
+}
+
+// Set replaces the policy. Requests already in flight finish under the old one.
+func (p *Policy) Set(opts Options) {
+	corsOpts := cors.Options{
+		AllowedOrigins:   opts.AllowedOrigins,
+		AllowedMethods:   opts.AllowedMethods,
+		AllowedHeaders:   opts.AllowedHeaders,
+		ExposedHeaders:   opts.ExposedHeaders,
+		AllowCredentials: opts.AllowCredentials,
+		MaxAge:           opts.MaxAge,
+	}
+	// The cors package treats an empty origin list as "allow all", the opposite of what an empty
+	// allow-list means here.
+	if len(opts.AllowedOrigins) == 0 {
+		corsOpts.AllowOriginFunc = func(r *http.Request, origin string) bool { return false }
+	}
+	p.current.Store(cors.New(corsOpts))
+}
+
+// Handler applies the current policy to each request.
+func (p *Policy) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.current.Load().Handler(next).ServeHTTP(w, r)
+	})
+}
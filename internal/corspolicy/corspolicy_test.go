@@ -0,0 +1,117 @@
+package corspolicy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	opts := Options{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST", "PATCH", "OPTIONS"},
+		AllowedHeaders:   []string{"Authorization", "Content-Type"},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	}
+
+	tests := map[string]struct {
+		opts            Options
+		method          string
+		origin          string
+		requestHeaders  string
+		requestMethod   string
+		expectedOrigin  string
+		expectedHeaders map[string]string
+	}{
+		"preflight with authorization and json": {
+			opts:           opts,
+			method:         http.MethodOptions,
+			origin:         "https://app.example.com",
+			requestMethod:  http.MethodPatch,
+			requestHeaders: "authorization, content-type",
+			expectedOrigin: "https://app.example.com",
+			expectedHeaders: map[string]string{
+				"Access-Control-Allow-Methods":     "PATCH",
+				"Access-Control-Allow-Headers":     "Authorization, Content-Type",
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Max-Age":           "300",
+			},
+		},
+		"wildcard subdomain": {
+			opts:           opts,
+			method:         http.MethodGet,
+			origin:         "https://reports.example.org",
+			expectedOrigin: "https://reports.example.org",
+			expectedHeaders: map[string]string{
+				"Access-Control-Expose-Headers": "X-Request-Id",
+			},
+		},
+		"origin not in allow-list": {
+			opts:   opts,
+			method: http.MethodGet,
+			origin: "https://example.org.evil.com",
+		},
+		"disallowed header": {
+			opts:           opts,
+			method:         http.MethodOptions,
+			origin:         "https://app.example.com",
+			requestMethod:  http.MethodGet,
+			requestHeaders: "x-custom",
+		},
+		"empty allow-list rejects everything": {
+			opts:   Options{AllowedMethods: []string{"GET"}},
+			method: http.MethodGet,
+			origin: "https://app.example.com",
+		},
+		"any origin": {
+			opts:           Options{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"GET"}},
+			method:         http.MethodGet,
+			origin:         "https://anything.test",
+			expectedOrigin: "*",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := New(tc.opts)
+			handler := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tc.method, "/api/course", nil)
+			req.Header.Set("Origin", tc.origin)
+			if tc.requestMethod != "" {
+				req.Header.Set("Access-Control-Request-Method", tc.requestMethod)
+			}
+			if tc.requestHeaders != "" {
+				req.Header.Set("Access-Control-Request-Headers", tc.requestHeaders)
+			}
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedOrigin, w.Header().Get("Access-Control-Allow-Origin"))
+			for header, expected := range tc.expectedHeaders {
+				assert.Equal(t, expected, w.Header().Get(header), header)
+			}
+		})
+	}
+}
+
+func TestPolicySet(t *testing.T) {
+	policy := New(Options{AllowedOrigins: []string{"https://old.example.com"}, AllowedMethods: []string{"GET"}})
+	handler := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	policy.Set(Options{AllowedOrigins: []string{"https://new.example.com"}, AllowedMethods: []string{"GET"}})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/course", nil)
+	req.Header.Set("Origin", "https://new.example.com")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	assert.Equal(t, "https://new.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+}
@@ -2,106 +2,153 @@ package database
 
 import (
 	"context"
-	"database/sql"
-	"errors"
 	"fmt"
-	"math"
-	"math/rand"
+	"go-api-tech-challenge/internal/retry"
+	"log/slog"
 	"time"
 
-	"github.com/go-chi/httplog/v2"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// New establishes a database connection, tests that connection with `ping()`, and returns the connection.
-func New(ctx context.Context, connectionString string, logger *httplog.Logger, retryDuration time.Duration) (*sql.DB, error) {
-	logger.Info("Attempting to connect to database")
-	retryCount := 0
-	db, err := retryResult(ctx, retryDuration, func() (*sql.DB, error) {
-		retryCount++
-		return sql.Open("postgres", connectionString)
-	})
+// PoolOptions tunes the connection pool. Zero values keep the pgxpool defaults: the larger of four
+// connections and the number of CPUs, no warm connections, an hour's lifetime, thirty minutes'
+// idle time and a health check every minute.
+type PoolOptions struct {
+	MaxConns          int
+	MinConns          int
+	ConnMaxLifetime   time.Duration
+	ConnMaxIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+
+	// QueryExecMode selects how queries are sent to Postgres; see ParseQueryExecMode. Empty keeps
+	// the pgx default of caching prepared statements per connection.
+	QueryExecMode string
+	// StatementCacheCapacity is the number of prepared statements cached per connection.
+	StatementCacheCapacity int
+}
+
+// New creates a connection pool, tests it with `ping()`, and returns the pool. Connection attempts
+// are retried according to policy; errors that retrying cannot fix, such as failed authentication,
+// fail at once. Each failed attempt is logged before policy's own OnAttempt hook is called.
+func New(ctx context.Context, connectionString string, logger *slog.Logger, policy retry.Policy, pool PoolOptions) (*pgxpool.Pool, error) {
+	config, err := poolConfig(connectionString, pool)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"[in database.New] Failed to connect to database with retry duration of %s and %d attempts: %w",
-			retryDuration,
-			retryCount,
-			err,
-		)
+		return nil, fmt.Errorf("[in database.New] %w", err)
 	}
-	if db == nil {
-		return nil, fmt.Errorf("[in database.New] db is nil")
-	}
-	logger.Info("Successfully connected to database", "retry count", retryCount)
 
-	logger.Info("Attempting to ping database")
-	retryCount = 0
-	err = retry(ctx, retryDuration, func() error {
-		retryCount++
-		return db.Ping()
-	})
-	if err != nil {
-		if err := db.Close(); err != nil {
-			logger.Error("[in database.New] Failed to close database connection", "err", err)
+	if policy.Retryable == nil {
+		policy.Retryable = Retryable
+	}
+	onAttempt := policy.OnAttempt
+	policy.OnAttempt = func(attempt retry.Attempt) {
+		if attempt.Final {
+			logger.Error("Database connection attempt failed, giving up",
+				"attempt", attempt.Number,
+				"elapsed", attempt.Elapsed,
+				"err", attempt.Err,
+			)
+		} else {
+			logger.Warn("Database connection attempt failed, retrying",
+				"attempt", attempt.Number,
+				"elapsed", attempt.Elapsed,
+				"retry in", attempt.Delay,
+				"err", attempt.Err,
+			)
+		}
+		if onAttempt != nil {
+			onAttempt(attempt)
 		}
-		return nil, fmt.Errorf(
-			"[in database.New] Failed to ping database with retry duration of %s and %d attempts: %w",
-			retryDuration,
-			retryCount,
-			err,
-		)
 	}
-	logger.Info("Successfully pinged database", "retry count", retryCount)
 
-	logger.Info("database connection established")
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("[in database.New] Failed to create connection pool: %w", err)
+	}
 
-	return db, nil
-}
+	logger.Info("Configured connection pool",
+		"max conns", config.MaxConns,
+		"min conns", config.MinConns,
+		"max lifetime", config.MaxConnLifetime,
+		"max idle time", config.MaxConnIdleTime,
+		"health check period", config.HealthCheckPeriod,
+		"query exec mode", config.ConnConfig.DefaultQueryExecMode.String(),
+		"statement cache capacity", config.ConnConfig.StatementCacheCapacity,
+	)
 
-// retry repeatedly calls the provided retryFunc until it succeeds or the maxDuration is exceeded.
-// It uses an exponential backoff strategy for retries.
-func retry(ctx context.Context, maxDuration time.Duration, retryFunc func() error) error {
-	_, err := retryResult(ctx, maxDuration, func() (int, error) {
-		return 1, retryFunc()
+	logger.Info("Attempting to connect to database")
+	attempts := 0
+	err = retry.Do(ctx, policy, func(ctx context.Context) error {
+		attempts++
+		return db.Ping(ctx)
 	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("[in database.New] Failed to connect to database: %w", err)
+	}
+	logger.Info("database connection established", "attempts", attempts)
 
-	return err
+	return db, nil
 }
 
-// retryResult repeatedly calls the provided retryFunc until it succeeds or the maxDuration is exceeded.
-// It uses an exponential backoff strategy for retries.
-func retryResult[T any](ctx context.Context, maxDuration time.Duration, retryFunc func() (T, error)) (T, error) {
-	var (
-		returnData T
-		err        error
-	)
-	if maxDuration <= 0 {
-		return returnData, errors.New("invalid retry duration supplied")
+// poolConfig parses connectionString, either a URL or key/value pairs, and applies pool on top of
+// it.
+func poolConfig(connectionString string, pool PoolOptions) (*pgxpool.Config, error) {
+	config, err := pgxpool.ParseConfig(connectionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse connection string: %w", err)
 	}
 
-	const maxBackoffMilliseconds = 5_000.0
-
-	ctx, cancelFunc := context.WithTimeout(ctx, maxDuration)
-	defer cancelFunc()
-
-	go func() {
-		counter := 1.0
-		for {
-			counter++
-			returnData, err = retryFunc()
-			if err != nil {
-				waitMilliseconds := math.Min(
-					math.Pow(counter, 2)+float64(rand.Intn(10)),
-					maxBackoffMilliseconds,
-				)
-				time.Sleep(time.Duration(waitMilliseconds) * time.Millisecond)
-				continue
-			}
-			cancelFunc()
-			return
+	if pool.MaxConns > 0 {
+		config.MaxConns = int32(pool.MaxConns)
+	}
+	if pool.MinConns > 0 {
+		config.MinConns = int32(min(pool.MinConns, int(config.MaxConns)))
+	}
+	if pool.ConnMaxLifetime > 0 {
+		config.MaxConnLifetime = pool.ConnMaxLifetime
+	}
+	if pool.ConnMaxIdleTime > 0 {
+		config.MaxConnIdleTime = pool.ConnMaxIdleTime
+	}
+	if pool.HealthCheckPeriod > 0 {
+		config.HealthCheckPeriod = pool.HealthCheckPeriod
+	}
+
+	if pool.QueryExecMode != "" {
+		mode, err := ParseQueryExecMode(pool.QueryExecMode)
+		if err != nil {
+			return nil, err
 		}
-	}()
+		config.ConnConfig.DefaultQueryExecMode = mode
+	}
+	if pool.StatementCacheCapacity > 0 {
+		config.ConnConfig.StatementCacheCapacity = pool.StatementCacheCapacity
+	}
+
+	return config, nil
+}
+
+// queryExecModes maps configuration names to pgx query execution modes:
+//
+//   - cache_statement prepares each statement once per connection and caches it (the default);
+//   - cache_describe caches only the statement description;
+//   - describe_exec describes every statement before executing it;
+//   - exec and simple_protocol never prepare, for use behind PgBouncer in transaction mode.
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
 
-	<-ctx.Done()
-	return returnData, err
+// ParseQueryExecMode converts a query execution mode name such as "cache_statement" to its pgx
+// value.
+func ParseQueryExecMode(name string) (pgx.QueryExecMode, error) {
+	mode, ok := queryExecModes[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown query exec mode %q", name)
+	}
+	return mode, nil
 }
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/retry"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Execer runs a statement. It is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx, so Notify can be
+// called inside a transaction, in which case the notification is only delivered on commit.
+type Execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// NotificationHandler is called for every notification received by Listen.
+type NotificationHandler func(ctx context.Context, notification *pgconn.Notification)
+
+// listenBackoff spaces out reconnection attempts after a listener loses its connection.
+var listenBackoff = retry.Policy{
+	InitialDelay: 100 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+	Jitter:       0.5,
+}
+
+// Notify sends payload to every session listening on channel.
+func Notify(ctx context.Context, db Execer, channel string, payload string) error {
+	if _, err := db.Exec(ctx, "SELECT pg_notify($1, $2)", channel, payload); err != nil {
+		return fmt.Errorf("[in database.Notify] failed to notify %s: %w", channel, err)
+	}
+	return nil
+}
+
+// Listen subscribes to channel and calls handle with each notification until ctx is done, which is
+// the only way it returns. The subscription holds a connection taken out of the pool for as long as
+// it runs. If that connection is lost, Listen reconnects with exponential backoff; notifications
+// sent while it was disconnected are not delivered.
+func Listen(ctx context.Context, pool *pgxpool.Pool, channel string, logger *slog.Logger, handle NotificationHandler) error {
+	failures := 0
+	for {
+		received, err := listen(ctx, pool, channel, handle)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if received {
+			failures = 0
+		}
+		failures++
+		backoff := listenBackoff.Delay(failures)
+
+		logger.Warn("Notification listener disconnected, reconnecting",
+			"channel", channel,
+			"err", err,
+			"backoff", backoff,
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// listen runs a single subscription until its connection fails or ctx is done, reporting whether
+// any notification was received.
+func listen(ctx context.Context, pool *pgxpool.Pool, channel string, handle NotificationHandler) (bool, error) {
+	pooled, err := pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	// A listening connection must not go back to the pool, where another caller would inherit the
+	// subscription, so it is taken over and closed when the subscription ends.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize()); err != nil {
+		return false, fmt.Errorf("failed to listen on %s: %w", channel, err)
+	}
+
+	received := false
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return received, err
+		}
+		received = true
+		handle(ctx, notification)
+	}
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Pinger is anything whose health can be checked by pinging it, such as *pgxpool.Pool.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// ReplicaSet spreads reads across read replicas in round-robin order, skipping replicas that failed
+// their last health check or a recent query. It is generic so that callers can hold the replicas
+// as whichever interface they query through.
+type ReplicaSet[T Pinger] struct {
+	replicas []*replica[T]
+	next     atomic.Uint64
+	logger   *slog.Logger
+}
+
+type replica[T Pinger] struct {
+	name    string
+	db      T
+	healthy atomic.Bool
+}
+
+// Replica names a read replica for logging.
+type Replica[T Pinger] struct {
+	Name string
+	DB   T
+}
+
+// NewReplicaSet returns a set of replicas, all initially assumed healthy.
+func NewReplicaSet[T Pinger](logger *slog.Logger, replicas ...Replica[T]) *ReplicaSet[T] {
+	set := &ReplicaSet[T]{logger: logger}
+	for _, r := range replicas {
+		member := &replica[T]{name: r.Name, db: r.DB}
+		member.healthy.Store(true)
+		set.replicas = append(set.replicas, member)
+	}
+	return set
+}
+
+// Len returns the number of replicas, healthy or not.
+func (s *ReplicaSet[T]) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.replicas)
+}
+
+// Next returns the next healthy replica in round-robin order, or false if there is none and the
+// caller should read from the primary.
+func (s *ReplicaSet[T]) Next() (T, bool) {
+	var zero T
+	if s.Len() == 0 {
+		return zero, false
+	}
+
+	start := s.next.Add(1)
+	for i := range uint64(len(s.replicas)) {
+		member := s.replicas[(start+i)%uint64(len(s.replicas))]
+		if member.healthy.Load() {
+			return member.db, true
+		}
+	}
+	return zero, false
+}
+
+// MarkDown takes a replica out of rotation after a failed query, until it next passes a health
+// check.
+func (s *ReplicaSet[T]) MarkDown(db T, err error) {
+	for _, member := range s.replicas {
+		if any(member.db) == any(db) {
+			s.setHealthy(member, false, err)
+		}
+	}
+}
+
+// Run health-checks every replica each interval until ctx is done, bounding each check by timeout.
+func (s *ReplicaSet[T]) Run(ctx context.Context, interval time.Duration, timeout time.Duration) {
+	if s.Len() == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s.check(ctx, timeout)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReplicaSet[T]) check(ctx context.Context, timeout time.Duration) {
+	for _, member := range s.replicas {
+		pingCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := member.db.Ping(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		s.setHealthy(member, err == nil, err)
+	}
+}
+
+// setHealthy records a replica's health, logging only when it changes.
+func (s *ReplicaSet[T]) setHealthy(member *replica[T], healthy bool, err error) {
+	if member.healthy.Swap(healthy) == healthy {
+		return
+	}
+	if healthy {
+		s.logger.Info("Read replica is back in rotation", "replica", member.name)
+	} else {
+		s.logger.Warn("Read replica taken out of rotation", "replica", member.name, "err", err)
+	}
+}
+
+// NewReplicaPool creates a pool for a read replica. Unlike New it does not wait for the replica to
+// answer, so that an unavailable replica cannot hold up startup; ReplicaSet.Run finds out whether
+// it is healthy.
+func NewReplicaPool(ctx context.Context, connectionString string, pool PoolOptions) (*pgxpool.Pool, error) {
+	config, err := poolConfig(connectionString, pool)
+	if err != nil {
+		return nil, fmt.Errorf("[in database.NewReplicaPool] %w", err)
+	}
+	db, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		return nil, fmt.Errorf("[in database.NewReplicaPool] Failed to create connection pool: %w", err)
+	}
+	return db, nil
+}
+
+type primaryKey struct{}
+
+// WithPrimary returns a context whose reads must go to the primary, so that a client sees its own
+// writes even if the replicas lag behind.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// PrimaryRequired reports whether ctx was marked by WithPrimary.
+func PrimaryRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(primaryKey{}).(bool)
+	return required
+}
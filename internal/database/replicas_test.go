@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReplica struct {
+	name string
+	down atomic.Bool
+}
+
+func (r *fakeReplica) Ping(ctx context.Context) error {
+	if r.down.Load() {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func TestReplicaSet(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	a, b := &fakeReplica{name: "a"}, &fakeReplica{name: "b"}
+
+	t.Run("no replicas", func(t *testing.T) {
+		var empty *ReplicaSet[*fakeReplica]
+		_, ok := empty.Next()
+		assert.False(t, ok)
+
+		_, ok = NewReplicaSet[*fakeReplica](logger).Next()
+		assert.False(t, ok)
+	})
+
+	set := NewReplicaSet(logger, Replica[*fakeReplica]{Name: "a", DB: a}, Replica[*fakeReplica]{Name: "b", DB: b})
+
+	t.Run("round robin", func(t *testing.T) {
+		seen := map[string]int{}
+		for range 4 {
+			replica, ok := set.Next()
+			assert.True(t, ok)
+			seen[replica.name]++
+		}
+		assert.Equal(t, map[string]int{"a": 2, "b": 2}, seen)
+	})
+
+	t.Run("failed replica skipped", func(t *testing.T) {
+		set.MarkDown(a, errors.New("connection reset"))
+		for range 3 {
+			replica, ok := set.Next()
+			assert.True(t, ok)
+			assert.Equal(t, "b", replica.name)
+		}
+	})
+
+	t.Run("health checks restore and remove replicas", func(t *testing.T) {
+		b.down.Store(true)
+		set.check(context.Background(), time.Second)
+
+		replica, ok := set.Next()
+		assert.True(t, ok)
+		assert.Equal(t, "a", replica.name)
+
+		a.down.Store(true)
+		set.check(context.Background(), time.Second)
+
+		_, ok = set.Next()
+		assert.False(t, ok)
+	})
+
+	t.Run("run stops with context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			set.Run(ctx, time.Millisecond, time.Second)
+			close(done)
+		}()
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Run did not return after the context was cancelled")
+		}
+	})
+}
+
+func TestPrimaryRequired(t *testing.T) {
+	assert.False(t, PrimaryRequired(context.Background()))
+	assert.True(t, PrimaryRequired(WithPrimary(context.Background())))
+}
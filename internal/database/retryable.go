@@ -0,0 +1,46 @@
+package database
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Retryable reports whether a failed attempt to reach Postgres might succeed if tried again. Refused
+// or dropped connections, timeouts, and a server that is starting up, shutting down or full are
+// retryable. Failed authentication, a missing database, an untrusted server certificate and a
+// malformed connection string are not: they need someone to change the configuration.
+func Retryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case strings.HasPrefix(pgErr.Code, "08"): // connection_exception
+			return true
+		case pgErr.Code == "57P01", // admin_shutdown
+			pgErr.Code == "57P03", // cannot_connect_now, e.g. still starting up
+			pgErr.Code == "53300": // too_many_connections
+			return true
+		}
+		// Everything else, notably invalid_authorization_specification (28000),
+		// invalid_password (28P01) and invalid_catalog_name (3D000), will fail the same way again.
+		return false
+	}
+
+	var parseErr *pgconn.ParseConfigError
+	if errors.As(err, &parseErr) {
+		return false
+	}
+	var verifyErr *tls.CertificateVerificationError
+	if errors.As(err, &verifyErr) {
+		return false
+	}
+
+	// Network errors, including connection refused and timeouts.
+	return true
+}
@@ -0,0 +1,70 @@
+package database
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryable(t *testing.T) {
+	_, parseErr := pgconn.ParseConfig("postgres://localhost:notaport")
+
+	tests := map[string]struct {
+		err      error
+		expected bool
+	}{
+		"connection refused": {
+			err:      fmt.Errorf("failed to connect: %w", &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}),
+			expected: true,
+		},
+		"server starting up": {
+			err:      &pgconn.PgError{Code: "57P03", Message: "the database system is starting up"},
+			expected: true,
+		},
+		"too many connections": {
+			err:      &pgconn.PgError{Code: "53300", Message: "sorry, too many clients already"},
+			expected: true,
+		},
+		"connection failure": {
+			err:      &pgconn.PgError{Code: "08006"},
+			expected: true,
+		},
+		"wrong password": {
+			err:      fmt.Errorf("failed to connect: %w", &pgconn.PgError{Code: "28P01", Message: "password authentication failed"}),
+			expected: false,
+		},
+		"missing database": {
+			err:      &pgconn.PgError{Code: "3D000", Message: `database "school" does not exist`},
+			expected: false,
+		},
+		"malformed connection string": {
+			err:      parseErr,
+			expected: false,
+		},
+		"untrusted certificate": {
+			err:      &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}},
+			expected: false,
+		},
+		"unknown error": {
+			err:      errors.New("unexpected EOF"),
+			expected: true,
+		},
+		"nil": {
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, Retryable(tc.err))
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Beginner starts transactions. It is satisfied by *pgxpool.Pool, *pgx.Conn and pgx.Tx.
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// SharedTx is a transaction joined by every service call made with the context BeginShared
+// returns, so that several calls commit or roll back together. Calls that begin their own
+// transaction get a savepoint inside it. A SharedTx must not be used by more than one call at a
+// time.
+type SharedTx struct {
+	tx pgx.Tx
+
+	mu          sync.Mutex
+	afterCommit []func()
+}
+
+type sharedTxKey struct{}
+
+// BeginShared starts a transaction on db and returns a context carrying it. Reads made with the
+// context go to the primary, through the transaction, so that they see its uncommitted writes.
+func BeginShared(ctx context.Context, db Beginner) (context.Context, *SharedTx, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return ctx, nil, fmt.Errorf("[in database.BeginShared] failed to begin transaction: %w", err)
+	}
+	shared := &SharedTx{tx: tx}
+	return context.WithValue(WithPrimary(ctx), sharedTxKey{}, shared), shared, nil
+}
+
+// SharedTxFrom returns the transaction carried by ctx, if any.
+func SharedTxFrom(ctx context.Context) (pgx.Tx, bool) {
+	shared, ok := ctx.Value(sharedTxKey{}).(*SharedTx)
+	if !ok {
+		return nil, false
+	}
+	return shared.tx, true
+}
+
+// AfterCommit calls fn once the transaction carried by ctx commits, or straight away if ctx carries
+// none. If the transaction rolls back fn is never called. It suits side effects, such as cache
+// invalidation, that must not happen before the change they follow is visible.
+func AfterCommit(ctx context.Context, fn func()) {
+	shared, ok := ctx.Value(sharedTxKey{}).(*SharedTx)
+	if !ok {
+		fn()
+		return
+	}
+	shared.mu.Lock()
+	defer shared.mu.Unlock()
+	shared.afterCommit = append(shared.afterCommit, fn)
+}
+
+// Commit commits the transaction, then runs the functions registered with AfterCommit.
+func (s *SharedTx) Commit(ctx context.Context) error {
+	if err := s.tx.Commit(ctx); err != nil {
+		return fmt.Errorf("[in database.SharedTx.Commit] failed to commit transaction: %w", err)
+	}
+	s.mu.Lock()
+	afterCommit := s.afterCommit
+	s.afterCommit = nil
+	s.mu.Unlock()
+	for _, fn := range afterCommit {
+		fn()
+	}
+	return nil
+}
+
+// Rollback rolls the transaction back, discarding the functions registered with AfterCommit. It
+// is safe to call after Commit, when it does nothing.
+func (s *SharedTx) Rollback(ctx context.Context) error {
+	s.mu.Lock()
+	s.afterCommit = nil
+	s.mu.Unlock()
+	if err := s.tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		return fmt.Errorf("[in database.SharedTx.Rollback] failed to roll back transaction: %w", err)
+	}
+	return nil
+}
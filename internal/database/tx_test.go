@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSharedTx(t *testing.T) {
+	tests := map[string]struct {
+		commit        bool
+		expectedCalls int
+	}{
+		"after commit functions run on commit":           {commit: true, expectedCalls: 1},
+		"after commit functions are dropped on rollback": {commit: false, expectedCalls: 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			mockDB, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			mockDB.ExpectBegin()
+			if tc.commit {
+				mockDB.ExpectCommit()
+			} else {
+				mockDB.ExpectRollback()
+			}
+
+			txCtx, shared, err := BeginShared(ctx, mockDB)
+			assert.NoError(t, err)
+			_, ok := SharedTxFrom(txCtx)
+			assert.True(t, ok)
+			assert.True(t, PrimaryRequired(txCtx), "reads in a shared transaction must go to the primary")
+
+			calls := 0
+			AfterCommit(txCtx, func() { calls++ })
+			assert.Zero(t, calls)
+
+			if tc.commit {
+				assert.NoError(t, shared.Commit(ctx))
+			} else {
+				assert.NoError(t, shared.Rollback(ctx))
+			}
+			assert.Equal(t, tc.expectedCalls, calls)
+			assert.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestAfterCommitWithoutTx(t *testing.T) {
+	ctx := context.Background()
+	_, ok := SharedTxFrom(ctx)
+	assert.False(t, ok)
+
+	calls := 0
+	AfterCommit(ctx, func() { calls++ })
+	assert.Equal(t, 1, calls, "without a shared transaction the function should run straight away")
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type TransactionRunner interface {
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+const batchPath = "/api/batch"
+
+// Batch modes. An atomic batch runs in one transaction and stops at the first operation that
+// fails, rolling back those before it. A best-effort batch runs every operation on its own.
+const (
+	batchModeAtomic     = "atomic"
+	batchModeBestEffort = "best_effort"
+)
+
+// batchMethods are the methods an operation of a batch may use.
+var batchMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodPatch:  true,
+	http.MethodDelete: true,
+}
+
+var errBatchOperationFailed = errors.New("batch operation failed")
+
+// HandleBatch is a Handler that runs several API requests in one round trip. Each operation is
+// served by api, the router for the /api routes, as if it had been sent on its own.
+//
+//	@Summary		Run a batch of operations
+//	@Description	Runs several create, update, delete or get requests in one round trip, returning each one's status and body in order. In atomic mode, the default, the operations share one database transaction: the batch stops at the first operation that fails, everything before it is rolled back, and the operations after it are answered with 424. In best_effort mode every operation runs, and commits, on its own.
+//	@Tags			batch
+//	@Accept			json
+//	@Produce		json
+//	@Param			operations	body		[]handlers.inputBatchOperation	true	"Operations to run, in order"
+//	@Param			mode		query		string							false	"atomic (default) or best_effort"	Enums(atomic, best_effort)
+//	@Param			Idempotency-Key	header		string							false	"key making the request safe to retry; a retry gets the first response"
+//	@Success		200			{object}	handlers.responseBatch
+//	@Failure		400			{object}	handlers.responseErr
+//	@Failure		413			{object}	handlers.responseErr
+//	@Failure		500			{object}	handlers.responseErr
+//	@Router			/api/batch	[POST]
+func HandleBatch(api http.Handler, service TransactionRunner, maxOperations int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleBatch")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		mode := r.URL.Query().Get("mode")
+		switch mode {
+		case "":
+			mode = batchModeAtomic
+		case batchModeAtomic, batchModeBestEffort:
+		default:
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: fmt.Sprintf("mode must be atomic or best_effort, got %q", mode),
+			})
+			return
+		}
+
+		var operations []inputBatchOperation
+		if err := json.NewDecoder(r.Body).Decode(&operations); err != nil {
+			logger.Error("BodyParser error", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "missing values or malformed body",
+			})
+			return
+		}
+		switch {
+		case len(operations) == 0:
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "batch has no operations",
+			})
+			return
+		case len(operations) > maxOperations:
+			encodeResponse(ctx, w, http.StatusRequestEntityTooLarge, responseErr{
+				Error: fmt.Sprintf("batch must not have more than %d operations", maxOperations),
+			})
+			return
+		}
+		var problems []problem
+		for i, operation := range operations {
+			for _, p := range operation.Valid() {
+				p.Name = fmt.Sprintf("operations[%d].%s", i, p.Name)
+				problems = append(problems, p)
+			}
+		}
+		if len(problems) > 0 {
+			logger.Error("Problems validating input", "problems", problems)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				ValidationErrors: problems,
+			})
+			return
+		}
+		span.SetAttributes(attribute.String("batch.mode", mode), attribute.Int("batch.operations", len(operations)))
+
+		response := responseBatch{Mode: mode, Results: make([]outputBatchResult, len(operations))}
+		if mode == batchModeBestEffort {
+			for i, operation := range operations {
+				response.Results[i] = runBatchOperation(ctx, api, r, operation)
+			}
+			encodeResponse(ctx, w, http.StatusOK, response)
+			return
+		}
+
+		err := service.InTransaction(ctx, func(ctx context.Context) error {
+			for i, operation := range operations {
+				response.Results[i] = runBatchOperation(ctx, api, r, operation)
+				if response.Results[i].Status < http.StatusBadRequest {
+					continue
+				}
+				for j := i + 1; j < len(operations); j++ {
+					response.Results[j] = outputBatchResult{
+						Status: http.StatusFailedDependency,
+						Body:   errorBody(fmt.Sprintf("not run because operation %d failed", i)),
+					}
+				}
+				return errBatchOperationFailed
+			}
+			return nil
+		})
+		response.RolledBack = errors.Is(err, errBatchOperationFailed)
+		if err != nil && !response.RolledBack {
+			logger.Error("error running batch", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error running batch",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, response)
+	}
+}
+
+// runBatchOperation serves one operation of a batch through api and records its response. The
+// operation's request carries ctx, so that it joins the batch's transaction if there is one.
+func runBatchOperation(ctx context.Context, api http.Handler, parent *http.Request, operation inputBatchOperation) (result outputBatchResult) {
+	logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+	method := strings.ToUpper(operation.Method)
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logger.Error("batch operation panicked", "method", method, "path", operation.Path, "panic", recovered)
+			result = outputBatchResult{
+				Status: http.StatusInternalServerError,
+				Body:   errorBody("Error running operation"),
+			}
+		}
+	}()
+
+	// The operation is routed from the /api router with a route context of its own, since the
+	// batch request's is already spent.
+	target, _ := url.Parse(operation.Path)
+	routeContext := chi.NewRouteContext()
+	routeContext.RoutePath = strings.TrimPrefix(target.Path, "/api")
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, routeContext)
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), bytes.NewReader(operation.Body))
+	if err != nil {
+		return outputBatchResult{Status: http.StatusBadRequest, Body: errorBody(err.Error())}
+	}
+	req.RemoteAddr = parent.RemoteAddr
+	req.Header.Set("Accept", "application/json")
+	if len(operation.Body) > 0 {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	recorder := &batchRecorder{header: http.Header{}}
+	api.ServeHTTP(recorder, req)
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	logger.Debug("Ran batch operation", "method", method, "path", operation.Path, "status", recorder.status)
+
+	result = outputBatchResult{Status: recorder.status}
+	body := bytes.TrimSpace(recorder.body.Bytes())
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		result.Body = body
+	default:
+		result.Body, _ = json.Marshal(string(body))
+	}
+	return result
+}
+
+// errorBody encodes message the way handlers report errors.
+func errorBody(message string) json.RawMessage {
+	body, _ := json.Marshal(responseErr{Error: message})
+	return body
+}
+
+// batchRecorder holds the response to one operation of a batch.
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *batchRecorder) Header() http.Header {
+	return w.header
+}
+
+func (w *batchRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *batchRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
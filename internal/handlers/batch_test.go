@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type txKey struct{}
+
+// batchTestAPI stands in for the /api router. Creating a person fails when the first name is
+// blank, and every operation reports whether it ran inside the batch's transaction.
+func batchTestAPI() chi.Router {
+	api := chi.NewRouter()
+	api.Post("/person", func(w http.ResponseWriter, r *http.Request) {
+		var person inputPerson
+		_ = json.NewDecoder(r.Body).Decode(&person)
+		if person.FirstName == "" {
+			encodeResponse(r.Context(), w, http.StatusBadRequest, responseErr{Error: "first_name must not be blank"})
+			return
+		}
+		encodeResponse(r.Context(), w, http.StatusCreated, responseMsg{
+			Message: fmt.Sprintf("created %s, in transaction: %v", person.FirstName, r.Context().Value(txKey{}) != nil),
+		})
+	})
+	api.Delete("/person/{name}", func(w http.ResponseWriter, r *http.Request) {
+		encodeResponse(r.Context(), w, http.StatusOK, responseMsg{Message: "deleted " + chi.URLParam(r, "name")})
+	})
+	api.Get("/course", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("format=" + r.URL.Query().Get("format")))
+	})
+	api.Put("/course/{ID}", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	return api
+}
+
+func TestHandleBatch(t *testing.T) {
+	created := func(name string, inTx bool) outputBatchResult {
+		return outputBatchResult{
+			Status: http.StatusCreated,
+			Body:   json.RawMessage(testutil.ToJSONString(responseMsg{Message: fmt.Sprintf("created %s, in transaction: %v", name, inTx)})),
+		}
+	}
+
+	tests := map[string]struct {
+		query        string
+		body         string
+		mockCalled   bool
+		mockErr      error
+		expectedCode int
+		expectedBody string
+	}{
+		"atomic batch committed": {
+			body:         `[{"method":"POST","path":"/api/person","body":{"first_name":"John"}},{"method":"delete","path":"/api/person/Doe"}]`,
+			mockCalled:   true,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseBatch{Mode: batchModeAtomic, Results: []outputBatchResult{
+				created("John", true),
+				{Status: http.StatusOK, Body: json.RawMessage(`{"message":"deleted Doe"}`)},
+			}}),
+		},
+		"atomic batch rolled back": {
+			body:         `[{"method":"POST","path":"/api/person","body":{"first_name":"John"}},{"method":"POST","path":"/api/person","body":{}},{"method":"DELETE","path":"/api/person/Doe"}]`,
+			mockCalled:   true,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseBatch{Mode: batchModeAtomic, RolledBack: true, Results: []outputBatchResult{
+				created("John", true),
+				{Status: http.StatusBadRequest, Body: json.RawMessage(`{"error":"first_name must not be blank"}`)},
+				{Status: http.StatusFailedDependency, Body: errorBody("not run because operation 1 failed")},
+			}}),
+		},
+		"best effort runs every operation": {
+			query:        "?mode=best_effort",
+			body:         `[{"method":"POST","path":"/api/person","body":{}},{"method":"POST","path":"/api/person","body":{"first_name":"Jane"}},{"method":"GET","path":"/api/course?format=csv"}]`,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseBatch{Mode: batchModeBestEffort, Results: []outputBatchResult{
+				{Status: http.StatusBadRequest, Body: json.RawMessage(`{"error":"first_name must not be blank"}`)},
+				created("Jane", false),
+				{Status: http.StatusOK, Body: json.RawMessage(`"format=csv"`)},
+			}}),
+		},
+		"panicking operation": {
+			query:        "?mode=best_effort",
+			body:         `[{"method":"PUT","path":"/api/course/1","body":{"name":"Art"}}]`,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseBatch{Mode: batchModeBestEffort, Results: []outputBatchResult{
+				{Status: http.StatusInternalServerError, Body: errorBody("Error running operation")},
+			}}),
+		},
+		"unknown route": {
+			query:        "?mode=best_effort",
+			body:         `[{"method":"GET","path":"/api/nothing"}]`,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseBatch{Mode: batchModeBestEffort, Results: []outputBatchResult{
+				{Status: http.StatusNotFound, Body: json.RawMessage(`"404 page not found"`)},
+			}}),
+		},
+		"transaction error": {
+			body:         `[{"method":"DELETE","path":"/api/person/Doe"}]`,
+			mockCalled:   true,
+			mockErr:      errors.New("connection refused"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error running batch"}),
+		},
+		"invalid operations": {
+			body:         `[{"method":"OPTIONS","path":"/api/person"},{"method":"GET","path":"https://example.com/api/person"},{"method":"POST","path":"/api/batch"}]`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "operations[0].method", Description: "must be one of GET, POST, PUT, PATCH or DELETE"},
+				{Name: "operations[1].path", Description: "must be an API path starting with /api/"},
+				{Name: "operations[2].path", Description: "must not be a batch"},
+			}}),
+		},
+		"too many operations": {
+			body:         `[{"method":"GET","path":"/api/course"},{"method":"GET","path":"/api/course"},{"method":"GET","path":"/api/course"},{"method":"GET","path":"/api/course"}]`,
+			expectedCode: http.StatusRequestEntityTooLarge,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "batch must not have more than 3 operations"}),
+		},
+		"empty batch": {
+			body:         `[]`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "batch has no operations"}),
+		},
+		"malformed body": {
+			body:         `{"method":"GET"}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "missing values or malformed body"}),
+		},
+		"unknown mode": {
+			query:        "?mode=eventually",
+			body:         `[]`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: `mode must be atomic or best_effort, got "eventually"`}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.TransactionRunner)
+			handler := HandleBatch(batchTestAPI(), mockService, 3)
+
+			if tc.mockCalled {
+				mockService.
+					On("InTransaction", mock.Anything, mock.Anything).
+					Return(func(ctx context.Context, fn func(context.Context) error) error {
+						if tc.mockErr != nil {
+							return tc.mockErr
+						}
+						return fn(context.WithValue(ctx, txKey{}, true))
+					}).
+					Once()
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/batch"+tc.query, strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "InTransaction")
+			}
+		})
+	}
+}
@@ -2,14 +2,15 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
-
-	"github.com/go-chi/httplog/v2"
 )
 
 type CourseCreator interface {
-	CreateCourse(ctx context.Context, courseName string) (models.Course, error)
+	CreateCourse(ctx context.Context, course models.Course) (models.Course, error)
 }
 
 // CreateCourse is a Handler that creates a new course
@@ -20,13 +21,19 @@ type CourseCreator interface {
 //	@Accept			json
 //	@Produce		json
 //	@Param			course		body		handlers.inputCourse	true	"Course Object"
+//	@Param			Idempotency-Key	header		string					false	"key making the request safe to retry; a retry gets the first response"
 //	@Success		200			{object}	handlers.responseCourse
+//	@Failure		400			{object}	handlers.responseErr
+//	@Failure		409			{object}	handlers.responseErr	"the code is already in use"
+//	@Failure		422			{object}	handlers.responseErr	"the department does not exist"
 //	@Failure		500			{object}	handlers.responseErr
 //	@Router			/api/course	[POST]
-func HandleCreateCourse(logger *httplog.Logger, service CourseCreator) http.HandlerFunc {
+func HandleCreateCourse(service CourseCreator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleCreateCourse")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 
 		// get and validate body as object
 		courseIn, problems, err := decodeValidateBody[inputCourse](r)
@@ -34,28 +41,41 @@ func HandleCreateCourse(logger *httplog.Logger, service CourseCreator) http.Hand
 			switch {
 			case len(problems) > 0:
 				logger.Error("Problems validating input", "error", err, "problems", problems)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					ValidationErrors: problems,
 				})
 			default:
 				logger.Error("BodyParser error", "error", err)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					Error: "missing values or malformed body",
 				})
 			}
 			return
 		}
-		course, err := service.CreateCourse(ctx, courseIn.Name)
+		course, err := service.CreateCourse(ctx, courseIn)
+		if errors.Is(err, models.ErrDepartmentNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, missingDepartment)
+			return
+		}
+		if errors.Is(err, models.ErrCourseCodeTaken) {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "code is already in use",
+			})
+			return
+		}
 		if err != nil {
 			logger.Error("error getting all courses", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
 		coursesOut := mapOutputCourse(course)
-		encodeResponse(w, logger, http.StatusOK, responseCourse{
+		encodeResponse(ctx, w, http.StatusOK, responseCourse{
 			Course: coursesOut,
 		})
 	}
@@ -1,8 +1,8 @@
 package handlers
 
 import (
-	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -13,17 +13,19 @@ import (
 
 	serviceMock "go-api-tech-challenge/internal/handlers/mock"
 
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleCreateCourse(t *testing.T) {
 	mockService := new(serviceMock.CourseCreator)
-	logger := httplog.NewLogger("test")
-	handler := HandleCreateCourse(logger, mockService)
+	handler := HandleCreateCourse(mockService)
 
-	course := models.Course{ID: 1, Name: "Databases"}
+	courseIn := models.Course{Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true}
+	course := courseIn
+	course.ID = 1
 	courseOut := mapOutputCourse(course)
+	body := `{"code": "cs220", "name": "Databases", "credits": 3}`
 
 	tests := map[string]struct {
 		body         string
@@ -33,7 +35,7 @@ func TestHandleCreateCourse(t *testing.T) {
 		expectedBody string
 	}{
 		"course created successfully": {
-			body:         `{"name": "Databases"}`,
+			body:         body,
 			mockCalled:   true,
 			mockOutput:   []any{course, nil},
 			expectedCode: http.StatusOK,
@@ -51,12 +53,58 @@ func TestHandleCreateCourse(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			expectedBody: testutil.ToJSONString(responseErr{
 				ValidationErrors: []problem{
+					{Name: "code", Description: "must be 2 to 4 letters and 3 digits, optionally followed by a letter, such as CS101"},
 					{Name: "name", Description: "must not be blank"},
+					{Name: "credits", Description: "must be between 1 and 12"},
+				},
+			}),
+		},
+		"invalid level": {
+			body:         `{"code": "CS220", "name": "Databases", "credits": 3, "level": "postdoc"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "level", Description: "must be either 'undergraduate' or 'graduate'"},
+				},
+			}),
+		},
+		"code taken": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Course{}, fmt.Errorf("wrapped: %w", models.ErrCourseCodeTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "code is already in use"}),
+		},
+		"department missing": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Course{}, fmt.Errorf("wrapped: %w", models.ErrDepartmentNotFound)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(missingDepartment),
+		},
+		"invalid department": {
+			body:         `{"code": "CS220", "name": "Databases", "credits": 3, "department_id": 0}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "department_id", Description: "must be a positive integer"},
+				},
+			}),
+		},
+		"invalid capacity": {
+			body:         `{"code": "CS220", "name": "Databases", "credits": 3, "capacity": 0}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "capacity", Description: "must be a positive integer"},
 				},
 			}),
 		},
 		"internal server error": {
-			body:         `{"name": "Databases"}`,
+			body:         body,
 			mockCalled:   true,
 			mockOutput:   []any{models.Course{}, errors.New("test error")},
 			expectedCode: http.StatusInternalServerError,
@@ -71,7 +119,7 @@ func TestHandleCreateCourse(t *testing.T) {
 
 			if tc.mockCalled {
 				mockService.
-					On("CreateCourse", context.Background(), "Databases").
+					On("CreateCourse", mock.Anything, courseIn).
 					Return(tc.mockOutput...).
 					Once()
 			}
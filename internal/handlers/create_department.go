@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+)
+
+type DepartmentCreator interface {
+	CreateDepartment(ctx context.Context, department models.Department) (models.Department, error)
+}
+
+// HandleCreateDepartment is a Handler that creates a new department.
+//
+//	@Summary		Create Department
+//	@Description	Creates new department
+//	@Tags			departments
+//	@Accept			json
+//	@Produce		json
+//	@Param			department		body		handlers.inputDepartment	true	"Department Object"
+//	@Param			Idempotency-Key	header		string						false	"key making the request safe to retry; a retry gets the first response"
+//	@Success		201				{object}	handlers.responseDepartment
+//	@Failure		400				{object}	handlers.responseErr
+//	@Failure		409				{object}	handlers.responseErr	"the name is already in use"
+//	@Failure		500				{object}	handlers.responseErr
+//	@Router			/api/department	[POST]
+func HandleCreateDepartment(service DepartmentCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleCreateDepartment")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		// get and validate body as object
+		departmentIn, problems, err := decodeValidateBody[inputDepartment](r)
+		if err != nil {
+			switch {
+			case len(problems) > 0:
+				logger.Error("Problems validating input", "error", err, "problems", problems)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					ValidationErrors: problems,
+				})
+			default:
+				logger.Error("BodyParser error", "error", err)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: "missing values or malformed body",
+				})
+			}
+			return
+		}
+
+		department, err := service.CreateDepartment(ctx, departmentIn)
+		if errors.Is(err, models.ErrDepartmentNameTaken) {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "name is already in use",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error creating department", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error creating department",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusCreated, responseDepartment{
+			Department: mapOutputDepartment(department),
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreateDepartment(t *testing.T) {
+	mockService := new(serviceMock.DepartmentCreator)
+	handler := HandleCreateDepartment(mockService)
+
+	departmentIn := models.Department{Name: "Mathematics"}
+	department := models.Department{ID: 3, Name: "Mathematics"}
+	body := `{"name": " Mathematics "}`
+
+	tests := map[string]struct {
+		body         string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"department created successfully": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{department, nil},
+			expectedCode: http.StatusCreated,
+			expectedBody: testutil.ToJSONString(responseDepartment{Department: mapOutputDepartment(department)}),
+		},
+		"invalid body": {
+			body:         `invalid body`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "missing values or malformed body"}),
+		},
+		"blank name": {
+			body:         `{"name": "  "}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "name", Description: "must not be blank"}},
+			}),
+		},
+		"name too long": {
+			body:         `{"name": "` + strings.Repeat("a", maxDepartmentNameLen+1) + `"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "name", Description: "must not be longer than 100 characters"}},
+			}),
+		},
+		"name taken": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Department{}, fmt.Errorf("wrapped: %w", models.ErrDepartmentNameTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "name is already in use"}),
+		},
+		"internal server error": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Department{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error creating department"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/api/department", strings.NewReader(tc.body))
+			assert.NoError(t, err)
+
+			if tc.mockCalled {
+				mockService.
+					On("CreateDepartment", mock.Anything, departmentIn).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "CreateDepartment")
+			}
+		})
+	}
+}
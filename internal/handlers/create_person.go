@@ -2,14 +2,17 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
-
-	"github.com/go-chi/httplog/v2"
+	"strconv"
 )
 
 type PersonCreator interface {
-	CreatePerson(ctx context.Context, newPerson models.Person) (models.Person, error)
+	CreatePerson(ctx context.Context, newPerson models.Person, allowDuplicate bool) (models.Person, error)
 }
 
 // HandleCreatePerson is a Handler that creates a new person
@@ -20,13 +23,31 @@ type PersonCreator interface {
 //	@Accept			json
 //	@Produce		json
 //	@Param			person		body		handlers.inputPerson	true	"Person Object"
+//	@Param			allow_duplicate	query		bool					false	"create the person even if they look like one who already exists"
+//	@Param			Idempotency-Key	header		string					false	"key making the request safe to retry; a retry gets the first response"
 //	@Success		200			{object}	handlers.responsePerson
+//	@Failure		400			{object}	handlers.responseErr
+//	@Failure		409			{object}	handlers.responseDuplicates	"the person may already exist, or the external ID is in use"
+//	@Failure		422			{object}	handlers.responseErr	"the department or a section does not exist"
 //	@Failure		500			{object}	handlers.responseErr
 //	@Router			/api/person	[POST]
-func HandleCreatePerson(logger *httplog.Logger, service PersonCreator) http.HandlerFunc {
+func HandleCreatePerson(service PersonCreator) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// setup
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleCreatePerson")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		allowDuplicate := false
+		if value := r.URL.Query().Get("allow_duplicate"); value != "" {
+			var err error
+			if allowDuplicate, err = strconv.ParseBool(value); err != nil {
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: fmt.Sprintf("allow_duplicate must be true or false, got %q", value),
+				})
+				return
+			}
+		}
 
 		// get values from request body
 		personIn, problems, err := decodeValidateBody[inputPerson, models.Person](r)
@@ -34,29 +55,56 @@ func HandleCreatePerson(logger *httplog.Logger, service PersonCreator) http.Hand
 			switch {
 			case len(problems) > 0:
 				logger.Error("Problems validating input", "error", err, "problems", problems)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					ValidationErrors: problems,
 				})
 			default:
 				logger.Error("BodyParser error", "error", err)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					Error: "missing values or malformed body",
 				})
 			}
 			return
 		}
 
-		person, err := service.CreatePerson(ctx, personIn)
+		person, err := service.CreatePerson(ctx, personIn, allowDuplicate)
+		var duplicate *models.DuplicatePersonError
+		switch {
+		case errors.As(err, &duplicate):
+			logger.Info("Refused to create possible duplicate person", "candidates", len(duplicate.Candidates))
+			encodeResponse(ctx, w, http.StatusConflict, responseDuplicates{
+				Error:      "person may already exist; repeat with allow_duplicate=true to create them anyway",
+				Candidates: mapMultipleOutputPerson(duplicate.Candidates),
+			})
+			return
+		}
+		if errors.Is(err, models.ErrDepartmentNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, missingDepartment)
+			return
+		}
+		if errors.Is(err, models.ErrSectionNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, missingSection)
+			return
+		}
+		if message, ok := personConflict(err); ok {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: message,
+			})
+			return
+		}
 		if err != nil {
 			logger.Error("error creating person", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error creating person",
 			})
 			return
 		}
 
 		personOut := mapOutputPerson(person)
-		encodeResponse(w, logger, http.StatusCreated, responsePerson{
+		encodeResponse(ctx, w, http.StatusCreated, responsePerson{
 			Person: personOut,
 		})
 	}
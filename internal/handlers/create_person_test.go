@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,22 +12,20 @@ import (
 	"go-api-tech-challenge/internal/models"
 	"go-api-tech-challenge/internal/testutil"
 
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleCreatePerson(t *testing.T) {
 	mockService := new(serviceMock.PersonCreator)
-	logger := httplog.NewLogger("test")
-	handler := HandleCreatePerson(logger, mockService)
+	handler := HandleCreatePerson(mockService)
 
 	personIn := models.Person{
 		FirstName: "John",
 		LastName:  "Doe",
 		Type:      "student",
 		Age:       25,
-		Courses:   []int{1, 2},
+		Sections:  []int{1, 2},
 	}
 
 	personOut := models.Person{
@@ -35,18 +34,23 @@ func TestHandleCreatePerson(t *testing.T) {
 		LastName:  "Doe",
 		Type:      "student",
 		Age:       25,
+		Sections:  []int{1, 2},
 		Courses:   []int{1, 2},
 	}
 
+	candidate := models.Person{ID: 7, FirstName: "john", LastName: "doe", Type: "student", Age: 26, ExternalID: "S-7"}
+
 	tests := map[string]struct {
-		body         string
-		mockCalled   bool
-		mockOutput   []any
-		expectedCode int
-		expectedBody string
+		query          string
+		body           string
+		allowDuplicate bool
+		mockCalled     bool
+		mockOutput     []any
+		expectedCode   int
+		expectedBody   string
 	}{
 		"person created successfully": {
-			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "courses": [1, 2]}`,
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
 			mockCalled:   true,
 			mockOutput:   []any{personOut, nil},
 			expectedCode: http.StatusCreated,
@@ -71,17 +75,103 @@ func TestHandleCreatePerson(t *testing.T) {
 			}),
 		},
 		"internal server error": {
-			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "courses": [1, 2]}`,
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
 			mockCalled:   true,
 			mockOutput:   []any{models.Person{}, errors.New("test error")},
 			expectedCode: http.StatusInternalServerError,
 			expectedBody: testutil.ToJSONString(responseErr{Error: "Error creating person"}),
 		},
+		"possible duplicate": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", &models.DuplicatePersonError{Candidates: []models.Person{candidate}})},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseDuplicates{
+				Error:      "person may already exist; repeat with allow_duplicate=true to create them anyway",
+				Candidates: []outputPerson{mapOutputPerson(candidate)},
+			}),
+		},
+		"duplicate allowed": {
+			query:          "?allow_duplicate=true",
+			body:           `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			allowDuplicate: true,
+			mockCalled:     true,
+			mockOutput:     []any{personOut, nil},
+			expectedCode:   http.StatusCreated,
+			expectedBody:   testutil.ToJSONString(responsePerson{Person: mapOutputPerson(personOut)}),
+		},
+		"invalid allow_duplicate": {
+			query:        "?allow_duplicate=maybe",
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: `allow_duplicate must be true or false, got "maybe"`}),
+		},
+		"external id taken": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrExternalIDTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "external_id is already in use"}),
+		},
+		"email taken": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrEmailTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "email is already in use"}),
+		},
+		"invalid contact details": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "email": "john", "phone": "12", "date_of_birth": "10/12/2001"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "email", Description: "must be an email address such as name@example.com"},
+					{Name: "phone", Description: "must be 7 to 15 digits, optionally starting with '+' and grouped by spaces, '-', '.' or parentheses"},
+					{Name: "date_of_birth", Description: "must be a date in the form YYYY-MM-DD"},
+				},
+			}),
+		},
+		"department missing": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrDepartmentNotFound)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(missingDepartment),
+		},
+		"section missing": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrSectionNotFound)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(missingSection),
+		},
+		"department for a student": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "department_id": 1}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "department_id", Description: "must only be given for professors"},
+				},
+			}),
+		},
+		"invalid external id": {
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "external_id": "S 1"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "external_id", Description: "must be 1 to 64 letters, digits, '-' or '_'"},
+				},
+			}),
+		},
 	}
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodPost, "/api/person", strings.NewReader(tc.body))
+			req, err := http.NewRequest(http.MethodPost, "/api/person"+tc.query, strings.NewReader(tc.body))
 			assert.NoError(t, err)
 
 			if tc.mockCalled {
@@ -91,10 +181,10 @@ func TestHandleCreatePerson(t *testing.T) {
 							p.LastName == personIn.LastName &&
 							p.Type == personIn.Type &&
 							p.Age == personIn.Age &&
-							len(p.Courses) == len(personIn.Courses) &&
-							p.Courses[0] == personIn.Courses[0] &&
-							p.Courses[1] == personIn.Courses[1]
-					})).
+							len(p.Sections) == len(personIn.Sections) &&
+							p.Sections[0] == personIn.Sections[0] &&
+							p.Sections[1] == personIn.Sections[1]
+					}), tc.allowDuplicate).
 					Return(tc.mockOutput...).
 					Once()
 			}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type SectionCreator interface {
+	CreateSection(ctx context.Context, section models.Section) (models.Section, error)
+}
+
+// HandleCreateSection is a Handler that adds a section to the course associated with the given ID.
+//
+//	@Summary		Create Section
+//	@Description	Adds a section of a course in a term, optionally taught by a professor
+//	@Tags			courses
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID							path		int						true	"ID of course"
+//	@Param			section						body		handlers.inputSection	true	"Section Object"
+//	@Param			Idempotency-Key				header		string					false	"key making the request safe to retry; a retry gets the first response"
+//	@Success		201							{object}	handlers.responseSection
+//	@Failure		400							{object}	handlers.responseErr
+//	@Failure		404							{object}	handlers.responseErr
+//	@Failure		409							{object}	handlers.responseErr	"the course already has a section with this number in the term"
+//	@Failure		422							{object}	handlers.responseErr	"the term does not exist or the instructor is not a professor"
+//	@Failure		500							{object}	handlers.responseErr
+//	@Router			/api/course/{ID}/sections	[POST]
+func HandleCreateSection(service SectionCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleCreateSection")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		courseID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		// get and validate body as object
+		sectionIn, problems, err := decodeValidateBody[inputSection](r)
+		if err != nil {
+			switch {
+			case len(problems) > 0:
+				logger.Error("Problems validating input", "error", err, "problems", problems)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					ValidationErrors: problems,
+				})
+			default:
+				logger.Error("BodyParser error", "error", err)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: "missing values or malformed body",
+				})
+			}
+			return
+		}
+		sectionIn.CourseID = courseID
+
+		section, err := service.CreateSection(ctx, sectionIn)
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "course not found",
+			})
+			return
+		case errors.Is(err, models.ErrTermNotFound):
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, responseErr{
+				ValidationErrors: []problem{{Name: "term_id", Description: "term does not exist"}},
+			})
+			return
+		case errors.Is(err, models.ErrInstructorNotProfessor):
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, responseErr{
+				ValidationErrors: []problem{{Name: "instructor_id", Description: "must be a professor"}},
+			})
+			return
+		case errors.Is(err, models.ErrSectionTaken):
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "number is already in use for this course and term",
+			})
+			return
+		case err != nil:
+			logger.Error("error creating section", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error creating section",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusCreated, responseSection{
+			Section: mapOutputSection(section),
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreateSection(t *testing.T) {
+	instructorID := 2
+	sectionIn := models.Section{CourseID: 1, TermID: 2, Number: 3, InstructorID: &instructorID}
+	section := models.Section{ID: 5, CourseID: 1, TermID: 2, Number: 3, InstructorID: &instructorID}
+	body := `{"term_id": 2, "number": 3, "instructor_id": 2}`
+
+	tests := map[string]struct {
+		courseID     string
+		body         string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"section created successfully": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{section, nil},
+			expectedCode: http.StatusCreated,
+			expectedBody: testutil.ToJSONString(responseSection{Section: mapOutputSection(section)}),
+		},
+		"invalid course ID": {
+			courseID:     "cs",
+			body:         body,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"invalid body": {
+			courseID:     "1",
+			body:         `invalid body`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "missing values or malformed body"}),
+		},
+		"validation errors in body": {
+			courseID:     "1",
+			body:         `{"number": -1, "instructor_id": 0}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "term_id", Description: "must be a positive integer"},
+					{Name: "number", Description: "must be a positive integer"},
+					{Name: "instructor_id", Description: "must be a positive integer"},
+				},
+			}),
+		},
+		"course not found": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Section{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "course not found"}),
+		},
+		"term missing": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Section{}, fmt.Errorf("wrapped: %w", models.ErrTermNotFound)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "term_id", Description: "term does not exist"}},
+			}),
+		},
+		"instructor not a professor": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Section{}, fmt.Errorf("wrapped: %w", models.ErrInstructorNotProfessor)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "instructor_id", Description: "must be a professor"}},
+			}),
+		},
+		"number taken": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Section{}, fmt.Errorf("wrapped: %w", models.ErrSectionTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "number is already in use for this course and term"}),
+		},
+		"internal server error": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Section{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error creating section"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.SectionCreator)
+			handler := HandleCreateSection(mockService)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/course/"+tc.courseID+"/sections", strings.NewReader(tc.body))
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.courseID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				mockService.
+					On("CreateSection", mock.Anything, sectionIn).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "CreateSection")
+			}
+		})
+	}
+}
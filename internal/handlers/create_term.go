@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+)
+
+type TermCreator interface {
+	CreateTerm(ctx context.Context, term models.Term) (models.Term, error)
+}
+
+// HandleCreateTerm is a Handler that creates a new term.
+//
+//	@Summary		Create Term
+//	@Description	Creates new term
+//	@Tags			terms
+//	@Accept			json
+//	@Produce		json
+//	@Param			term			body		handlers.inputTerm	true	"Term Object"
+//	@Param			Idempotency-Key	header		string				false	"key making the request safe to retry; a retry gets the first response"
+//	@Success		201				{object}	handlers.responseTerm
+//	@Failure		400				{object}	handlers.responseErr
+//	@Failure		409				{object}	handlers.responseErr	"the name is already in use"
+//	@Failure		500				{object}	handlers.responseErr
+//	@Router			/api/term					[POST]
+func HandleCreateTerm(service TermCreator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleCreateTerm")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		// get and validate body as object
+		termIn, problems, err := decodeValidateBody[inputTerm](r)
+		if err != nil {
+			switch {
+			case len(problems) > 0:
+				logger.Error("Problems validating input", "error", err, "problems", problems)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					ValidationErrors: problems,
+				})
+			default:
+				logger.Error("BodyParser error", "error", err)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: "missing values or malformed body",
+				})
+			}
+			return
+		}
+
+		term, err := service.CreateTerm(ctx, termIn)
+		if errors.Is(err, models.ErrTermNameTaken) {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "name is already in use",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error creating term", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error creating term",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusCreated, responseTerm{
+			Term: mapOutputTerm(term),
+		})
+	}
+}
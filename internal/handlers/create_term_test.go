@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleCreateTerm(t *testing.T) {
+	mockService := new(serviceMock.TermCreator)
+	handler := HandleCreateTerm(mockService)
+
+	startsOn := time.Date(2027, time.June, 1, 0, 0, 0, 0, time.UTC)
+	endsOn := time.Date(2027, time.August, 13, 0, 0, 0, 0, time.UTC)
+	termIn := models.Term{Name: "Summer 2027", StartsOn: startsOn, EndsOn: endsOn}
+	term := models.Term{ID: 3, Name: "Summer 2027", StartsOn: startsOn, EndsOn: endsOn}
+	body := `{"name": " Summer 2027 ", "starts_on": "2027-06-01", "ends_on": "2027-08-13"}`
+
+	tests := map[string]struct {
+		body         string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"term created successfully": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{term, nil},
+			expectedCode: http.StatusCreated,
+			expectedBody: testutil.ToJSONString(responseTerm{Term: mapOutputTerm(term)}),
+		},
+		"invalid body": {
+			body:         `invalid body`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "missing values or malformed body"}),
+		},
+		"blank name": {
+			body:         `{"name": "  ", "starts_on": "2027-06-01", "ends_on": "2027-08-13"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "name", Description: "must not be blank"}},
+			}),
+		},
+		"name too long": {
+			body:         `{"name": "` + strings.Repeat("a", maxTermNameLen+1) + `", "starts_on": "2027-06-01", "ends_on": "2027-08-13"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "name", Description: "must not be longer than 100 characters"}},
+			}),
+		},
+		"malformed dates": {
+			body:         `{"name": "Summer 2027", "starts_on": "June 1st", "ends_on": "2027-13-01"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "starts_on", Description: "must be a date in the form YYYY-MM-DD"},
+					{Name: "ends_on", Description: "must be a date in the form YYYY-MM-DD"},
+				},
+			}),
+		},
+		"ends before it starts": {
+			body:         `{"name": "Summer 2027", "starts_on": "2027-08-13", "ends_on": "2027-06-01"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "ends_on", Description: "must be after starts_on"}},
+			}),
+		},
+		"name taken": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, fmt.Errorf("wrapped: %w", models.ErrTermNameTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "name is already in use"}),
+		},
+		"internal server error": {
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error creating term"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/api/term", strings.NewReader(tc.body))
+			assert.NoError(t, err)
+
+			if tc.mockCalled {
+				mockService.
+					On("CreateTerm", mock.Anything, termIn).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "CreateTerm")
+			}
+		})
+	}
+}
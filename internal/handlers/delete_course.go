@@ -2,12 +2,12 @@ package handlers
 
 import (
 	"context"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-
-	"github.com/go-chi/httplog/v2"
 )
 
 type CourseDeleter interface {
@@ -25,16 +25,18 @@ type CourseDeleter interface {
 //	@Success		200					{object}	handlers.responseMsg
 //	@Failure		500					{object}	handlers.responseErr
 //	@Router			/api/course/{ID}	[DELETE]
-func HandleDeleteCourse(logger *httplog.Logger, service CourseDeleter) http.HandlerFunc {
+func HandleDeleteCourse(service CourseDeleter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleDeleteCourse")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 		// setup
 		idString := chi.URLParam(r, "ID")
 		courseID, err := strconv.Atoi(idString)
 		if err != nil {
 			logger.Error("error getting ID", "error", err)
-			encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 				Error: "Not a valid ID",
 			})
 			return
@@ -44,20 +46,21 @@ func HandleDeleteCourse(logger *httplog.Logger, service CourseDeleter) http.Hand
 		if err != nil {
 
 			logger.Error("error deleting course", "error", err)
+			tracing.RecordError(span, err)
 
 			if err.Error() == "course not found" {
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					Error: "course ID not found",
 				})
 				return
 			}
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
-		encodeResponse(w, logger, http.StatusOK, responseMsg{
+		encodeResponse(ctx, w, http.StatusOK, responseMsg{
 			Message: "Course deleted successfully",
 		})
 	}
@@ -12,14 +12,13 @@ import (
 	"go-api-tech-challenge/internal/testutil"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleDeleteCourse(t *testing.T) {
 	mockService := new(serviceMock.CourseDeleter)
-	logger := httplog.NewLogger("test")
-	handler := HandleDeleteCourse(logger, mockService)
+	handler := HandleDeleteCourse(mockService)
 
 	tests := map[string]struct {
 		courseID     string
@@ -72,7 +71,7 @@ func TestHandleDeleteCourse(t *testing.T) {
 			if tc.mockCalled {
 				id, _ := strconv.Atoi(tc.courseID) // Convert courseID to integer
 				mockService.
-					On("DeleteCourse", ctx, id).
+					On("DeleteCourse", mock.Anything, id).
 					Return(tc.mockReturn).
 					Once()
 			}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DepartmentDeleter interface {
+	DeleteDepartment(ctx context.Context, id int) error
+}
+
+// HandleDeleteDepartment is a Handler that deletes the department associated with the given ID.
+// Its courses and professors are kept, without a department.
+//
+//	@Summary		Deletes Department
+//	@Description	Deletes department associated with given ID. Its courses and professors are kept, without a department
+//	@Tags			departments
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID						path		int	true "ID of department to delete"
+//	@Success		200						{object}	handlers.responseMsg
+//	@Failure		400						{object}	handlers.responseErr
+//	@Failure		404						{object}	handlers.responseErr
+//	@Failure		500						{object}	handlers.responseErr
+//	@Router			/api/department/{ID}	[DELETE]
+func HandleDeleteDepartment(service DepartmentDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleDeleteDepartment")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		// setup
+		departmentID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		err = service.DeleteDepartment(ctx, departmentID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "department not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error deleting department", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error deleting department",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseMsg{
+			Message: "Department deleted successfully",
+		})
+	}
+}
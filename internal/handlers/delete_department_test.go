@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleDeleteDepartment(t *testing.T) {
+	mockService := new(serviceMock.DepartmentDeleter)
+	handler := HandleDeleteDepartment(mockService)
+
+	tests := map[string]struct {
+		departmentID string
+		mockCalled   bool
+		mockReturn   error
+		expectedCode int
+		expectedBody string
+	}{
+		"department deleted successfully": {
+			departmentID: "2",
+			mockCalled:   true,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseMsg{Message: "Department deleted successfully"}),
+		},
+		"invalid department ID": {
+			departmentID: "abc",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"department not found": {
+			departmentID: "9",
+			mockCalled:   true,
+			mockReturn:   fmt.Errorf("wrapped: %w", models.ErrNotFound),
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "department not found"}),
+		},
+		"internal server error": {
+			departmentID: "2",
+			mockCalled:   true,
+			mockReturn:   errors.New("test error"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error deleting department"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodDelete, "/api/department/"+tc.departmentID, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.departmentID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.departmentID)
+				mockService.
+					On("DeleteDepartment", mock.Anything, id).
+					Return(tc.mockReturn).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "DeleteDepartment")
+			}
+		})
+	}
+}
@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"context"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 )
 
 type PersonDeleter interface {
@@ -23,22 +24,25 @@ type PersonDeleter interface {
 //	@Success		200					{object}	handlers.responseMsg
 //	@Failure		500					{object}	handlers.responseErr
 //	@Router			/api/person/{name}	[DELETE]
-func HandleDeletePerson(logger *httplog.Logger, service PersonDeleter) http.HandlerFunc {
+func HandleDeletePerson(service PersonDeleter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// setup
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleDeletePerson")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 		lastName := chi.URLParam(r, "name")
 
 		err := service.DeletePerson(ctx, lastName)
 		if err != nil {
 			logger.Error("error deleting person", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error deleting person",
 			})
 			return
 		}
 
-		encodeResponse(w, logger, http.StatusOK, responseMsg{
+		encodeResponse(ctx, w, http.StatusOK, responseMsg{
 			Message: "Person deleted successfully",
 		})
 	}
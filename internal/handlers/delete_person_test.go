@@ -11,15 +11,13 @@ import (
 	"go-api-tech-challenge/internal/testutil"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleDeletePerson(t *testing.T) {
 	mockService := new(serviceMock.PersonDeleter)
-	logger := httplog.NewLogger("test")
-	handler := HandleDeletePerson(logger, mockService)
+	handler := HandleDeletePerson(mockService)
 
 	tests := map[string]struct {
 		lastName     string
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TermDeleter interface {
+	DeleteTerm(ctx context.Context, id int) error
+}
+
+// HandleDeleteTerm is a Handler that deletes the term associated with the given ID. A term that
+// still has sections cannot be deleted.
+//
+//	@Summary		Deletes Term
+//	@Description	Deletes term associated with given ID, unless it still has sections
+//	@Tags			terms
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID				path		int	true	"ID of term to delete"
+//	@Success		200				{object}	handlers.responseMsg
+//	@Failure		400				{object}	handlers.responseErr
+//	@Failure		404				{object}	handlers.responseErr
+//	@Failure		409				{object}	handlers.responseErr	"the term has sections"
+//	@Failure		500				{object}	handlers.responseErr
+//	@Router			/api/term/{ID}	[DELETE]
+func HandleDeleteTerm(service TermDeleter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleDeleteTerm")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		// setup
+		termID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		err = service.DeleteTerm(ctx, termID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "term not found",
+			})
+			return
+		}
+		if errors.Is(err, models.ErrTermInUse) {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "term has sections",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error deleting term", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error deleting term",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseMsg{
+			Message: "Term deleted successfully",
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleDeleteTerm(t *testing.T) {
+	mockService := new(serviceMock.TermDeleter)
+	handler := HandleDeleteTerm(mockService)
+
+	tests := map[string]struct {
+		termID       string
+		mockCalled   bool
+		mockReturn   error
+		expectedCode int
+		expectedBody string
+	}{
+		"term deleted successfully": {
+			termID:       "2",
+			mockCalled:   true,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseMsg{Message: "Term deleted successfully"}),
+		},
+		"invalid term ID": {
+			termID:       "abc",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"term not found": {
+			termID:       "9",
+			mockCalled:   true,
+			mockReturn:   fmt.Errorf("wrapped: %w", models.ErrNotFound),
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "term not found"}),
+		},
+		"term has sections": {
+			termID:       "1",
+			mockCalled:   true,
+			mockReturn:   fmt.Errorf("wrapped: %w", models.ErrTermInUse),
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "term has sections"}),
+		},
+		"internal server error": {
+			termID:       "2",
+			mockCalled:   true,
+			mockReturn:   errors.New("test error"),
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error deleting term"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodDelete, "/api/term/"+tc.termID, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.termID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.termID)
+				mockService.
+					On("DeleteTerm", mock.Anything, id).
+					Return(tc.mockReturn).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "DeleteTerm")
+			}
+		})
+	}
+}
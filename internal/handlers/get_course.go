@@ -2,58 +2,75 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
-	"log"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
-
-	"github.com/go-chi/httplog/v2"
 )
 
 type CourseGetter interface {
 	GetCourseByID(ctx context.Context, ID int) (models.Course, error)
+	GetCourseByCode(ctx context.Context, code string) (models.Course, error)
 }
 
-// GetCourseByID is a Handler that returns the course associated with the given ID.
+// GetCourseByID is a Handler that returns the course associated with the given ID, or with the
+// given code when the path is not a number.
 //
 //	@Summary		Get Course
-//	@Description	Gets course associated with given ID
+//	@Description	Gets course associated with given ID, or with given code such as CS101
 //	@Tags			courses
 //	@Accept			json
 //	@Produce		json
-//	@Param			ID					path		int	true "ID of course to retrieve"
+//	@Param			ID					path		string	true "ID or code of course to retrieve"
 //	@Success		200					{object}	handlers.responseCourse
+//	@Failure		400					{object}	handlers.responseErr
+//	@Failure		404					{object}	handlers.responseErr
 //	@Failure		500					{object}	handlers.responseErr
 //	@Router			/api/course/{ID}	[GET]
-func HandleGetCourseByID(logger *httplog.Logger, service CourseGetter) http.HandlerFunc {
+func HandleGetCourseByID(service CourseGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleGetCourseByID")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 		// setup
 		idString := chi.URLParam(r, "ID")
+		var course models.Course
 		ID, err := strconv.Atoi(idString)
-		if err != nil {
+		switch {
+		case err == nil:
+			course, err = service.GetCourseByID(ctx, ID)
+		case courseCodePattern.MatchString(strings.ToUpper(idString)):
+			course, err = service.GetCourseByCode(ctx, idString)
+		default:
 			logger.Error("error getting ID", "error", err)
-			encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 				Error: "Error retrieving course",
 			})
 			return
 		}
-		// get values from database
-		course, err := service.GetCourseByID(ctx, ID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "course not found",
+			})
+			return
+		}
 		if err != nil {
-			log.Println(err)
 			logger.Error("error getting all courses", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
 		coursesOut := mapOutputCourse(course)
-		encodeResponse(w, logger, http.StatusOK, responseCourse{
+		encodeResponse(ctx, w, http.StatusOK, responseCourse{
 			Course: coursesOut,
 		})
 	}
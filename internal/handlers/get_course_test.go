@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -14,16 +15,15 @@ import (
 	serviceMock "go-api-tech-challenge/internal/handlers/mock"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleGetCourseByID(t *testing.T) {
 	mockService := new(serviceMock.CourseGetter)
-	logger := httplog.NewLogger("test")
-	handler := HandleGetCourseByID(logger, mockService)
+	handler := HandleGetCourseByID(mockService)
 
-	course := models.Course{ID: 1, Name: "Databases"}
+	course := models.Course{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true}
 	courseOut := mapOutputCourse(course)
 
 	tests := map[string]struct {
@@ -47,6 +47,20 @@ func TestHandleGetCourseByID(t *testing.T) {
 			expectedCode: http.StatusInternalServerError, // Changed to match the handler
 			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
 		},
+		"course found by code": {
+			courseID:     "cs220",
+			mockCalled:   true,
+			mockOutput:   []any{course, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseCourse{Course: courseOut}),
+		},
+		"code not found": {
+			courseID:     "CS999",
+			mockCalled:   true,
+			mockOutput:   []any{models.Course{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "course not found"}),
+		},
 		"invalid ID": {
 			courseID:     "abc",
 			mockCalled:   false,
@@ -74,11 +88,17 @@ func TestHandleGetCourseByID(t *testing.T) {
 			req = req.WithContext(ctx)
 
 			if tc.mockCalled {
-				id, _ := strconv.Atoi(tc.courseID) // Convert courseID to integer
-				mockService.
-					On("GetCourseByID", ctx, id).
-					Return(tc.mockOutput...).
-					Once()
+				if id, err := strconv.Atoi(tc.courseID); err == nil {
+					mockService.
+						On("GetCourseByID", mock.Anything, id).
+						Return(tc.mockOutput...).
+						Once()
+				} else {
+					mockService.
+						On("GetCourseByCode", mock.Anything, tc.courseID).
+						Return(tc.mockOutput...).
+						Once()
+				}
 			}
 
 			rr := httptest.NewRecorder()
@@ -91,6 +111,7 @@ func TestHandleGetCourseByID(t *testing.T) {
 				mockService.AssertExpectations(t)
 			} else {
 				mockService.AssertNotCalled(t, "GetCourseByID")
+				mockService.AssertNotCalled(t, "GetCourseByCode")
 			}
 		})
 	}
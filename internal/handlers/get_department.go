@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DepartmentGetter interface {
+	GetDepartmentByID(ctx context.Context, id int) (models.Department, error)
+}
+
+// HandleGetDepartmentByID is a Handler that returns the department associated with the given ID.
+//
+//	@Summary		Get Department
+//	@Description	Gets department associated with given ID
+//	@Tags			departments
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID						path		int	true "ID of department to retrieve"
+//	@Success		200						{object}	handlers.responseDepartment
+//	@Failure		400						{object}	handlers.responseErr
+//	@Failure		404						{object}	handlers.responseErr
+//	@Failure		500						{object}	handlers.responseErr
+//	@Router			/api/department/{ID}	[GET]
+func HandleGetDepartmentByID(service DepartmentGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleGetDepartmentByID")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		// setup
+		departmentID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		department, err := service.GetDepartmentByID(ctx, departmentID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "department not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error getting department", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseDepartment{
+			Department: mapOutputDepartment(department),
+		})
+	}
+}
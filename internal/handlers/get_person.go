@@ -2,11 +2,12 @@ package handlers
 
 import (
 	"context"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 )
 
 type PersonGetter interface {
@@ -24,24 +25,27 @@ type PersonGetter interface {
 //	@Success		200					{object}	handlers.responsePerson
 //	@Failure		500					{object}	handlers.responseErr
 //	@Router			/api/person/{name}	[GET]
-func HandleGetPersonByName(logger *httplog.Logger, service PersonGetter) http.HandlerFunc {
+func HandleGetPersonByName(service PersonGetter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// setup
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleGetPersonByName")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 		name := chi.URLParam(r, "name")
 
 		// get values from database
 		persons, err := service.GetPersonByName(ctx, name)
 		if err != nil {
 			logger.Error("error getting all courses", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
 		personOut := mapOutputPerson(persons)
-		encodeResponse(w, logger, http.StatusOK, responsePerson{
+		encodeResponse(ctx, w, http.StatusOK, responsePerson{
 			Person: personOut,
 		})
 	}
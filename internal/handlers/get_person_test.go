@@ -12,14 +12,13 @@ import (
 	"go-api-tech-challenge/internal/testutil"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleGetPersonByName(t *testing.T) {
 	mockService := new(serviceMock.PersonGetter)
-	logger := httplog.NewLogger("test")
-	handler := HandleGetPersonByName(logger, mockService)
+	handler := HandleGetPersonByName(mockService)
 
 	person := models.Person{
 		ID:        1,
@@ -74,7 +73,7 @@ func TestHandleGetPersonByName(t *testing.T) {
 
 			if tc.mockCalled {
 				mockService.
-					On("GetPersonByName", ctx, tc.name).
+					On("GetPersonByName", mock.Anything, tc.name).
 					Return(tc.mockOutput...).
 					Once()
 			}
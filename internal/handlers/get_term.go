@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TermGetter interface {
+	GetTermByID(ctx context.Context, id int) (models.Term, error)
+}
+
+// HandleGetTermByID is a Handler that returns the term associated with the given ID.
+//
+//	@Summary		Get Term
+//	@Description	Gets term associated with given ID
+//	@Tags			terms
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID				path		int	true	"ID of term to retrieve"
+//	@Success		200				{object}	handlers.responseTerm
+//	@Failure		400				{object}	handlers.responseErr
+//	@Failure		404				{object}	handlers.responseErr
+//	@Failure		500				{object}	handlers.responseErr
+//	@Router			/api/term/{ID}	[GET]
+func HandleGetTermByID(service TermGetter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleGetTermByID")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		// setup
+		termID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		term, err := service.GetTermByID(ctx, termID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "term not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error getting term", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseTerm{
+			Term: mapOutputTerm(term),
+		})
+	}
+}
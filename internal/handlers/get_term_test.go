@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleGetTermByID(t *testing.T) {
+	mockService := new(serviceMock.TermGetter)
+	handler := HandleGetTermByID(mockService)
+
+	term := models.Term{
+		ID:       1,
+		Name:     "Fall 2026",
+		StartsOn: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		EndsOn:   time.Date(2026, time.December, 18, 0, 0, 0, 0, time.UTC),
+	}
+
+	tests := map[string]struct {
+		termID       string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"term found": {
+			termID:       "1",
+			mockCalled:   true,
+			mockOutput:   []any{term, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseTerm{Term: mapOutputTerm(term)}),
+		},
+		"invalid term ID": {
+			termID:       "cs",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"term not found": {
+			termID:       "9",
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "term not found"}),
+		},
+		"internal server error": {
+			termID:       "1",
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/term/"+tc.termID, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.termID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.termID)
+				mockService.
+					On("GetTermByID", mock.Anything, id).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "GetTermByID")
+			}
+		})
+	}
+}
@@ -1,25 +1,105 @@
 package handlers
 
 import (
+	"context"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
 	"net/http"
-
-	"github.com/go-chi/httplog/v2"
 )
 
-// HandleHealth is a health check handler
+type ReadinessChecker interface {
+	CheckReadiness(ctx context.Context) models.Readiness
+}
+
+type PoolStatsReporter interface {
+	PoolStats(ctx context.Context) models.PoolStats
+}
+
+type CacheStatsReporter interface {
+	CacheStats(ctx context.Context) models.CacheStats
+}
+
+// HandleLiveness is a liveness probe handler. It only reports that the process is up and able to
+// serve requests; it never checks dependencies.
 //
-//	@Summary		Health check response
-//	@Description	Health check response
+//	@Summary		Liveness probe
+//	@Description	Reports that the process is up
 //	@Tags			health-check
 //	@Accept			json
 //	@Produce		json
-//	@Success		200				{object}	handlers.responseMsg
-//	@Router			/api/health-check	[GET]
-func HandleHealth(logger *httplog.Logger) http.HandlerFunc {
+//	@Success		200					{object}	handlers.responseMsg
+//	@Router			/api/health/live	[GET]
+func HandleLiveness() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		logger.Info("Health check called")
-		encodeResponse(w, logger, http.StatusOK, responseMsg{
-			Message: "service healthy",
+		encodeResponse(r.Context(), w, http.StatusOK, responseMsg{
+			Message: "service alive",
 		})
 	}
 }
+
+// HandleReadiness is a readiness probe handler. It checks every dependency and returns 503 when any
+// of them are down or the service is draining for shutdown.
+//
+//	@Summary		Readiness probe
+//	@Description	Reports per-component status and latency of service dependencies
+//	@Tags			health-check
+//	@Accept			json
+//	@Produce		json
+//	@Success		200					{object}	handlers.responseReadiness
+//	@Failure		503					{object}	handlers.responseReadiness
+//	@Router			/api/health/ready	[GET]
+func HandleReadiness(service ReadinessChecker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "HandleReadiness")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		readiness := service.CheckReadiness(ctx)
+
+		status := http.StatusOK
+		if !readiness.Ready {
+			logger.Warn("Readiness check failed", "components", readiness.Components)
+			status = http.StatusServiceUnavailable
+		}
+
+		encodeResponse(ctx, w, status, mapOutputReadiness(readiness))
+	}
+}
+
+// HandlePoolStats reports database connection pool statistics, for spotting pool exhaustion before
+// it shows up as request latency.
+//
+//	@Summary		Database pool statistics
+//	@Description	Reports current and cumulative database connection pool statistics
+//	@Tags			health-check
+//	@Accept			json
+//	@Produce		json
+//	@Success		200					{object}	handlers.responsePoolStats
+//	@Router			/api/health/pool	[GET]
+func HandlePoolStats(service PoolStatsReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "HandlePoolStats")
+		defer span.End()
+
+		encodeResponse(ctx, w, http.StatusOK, mapOutputPoolStats(service.PoolStats(ctx)))
+	}
+}
+
+// HandleCacheStats reports read cache hits and misses, for judging whether the cache size and TTL
+// suit the traffic.
+//
+//	@Summary		Read cache statistics
+//	@Description	Reports cumulative read cache hits, misses, errors and invalidations
+//	@Tags			health-check
+//	@Accept			json
+//	@Produce		json
+//	@Success		200					{object}	handlers.responseCacheStats
+//	@Router			/api/health/cache	[GET]
+func HandleCacheStats(service CacheStatsReporter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "HandleCacheStats")
+		defer span.End()
+
+		encodeResponse(ctx, w, http.StatusOK, mapOutputCacheStats(service.CacheStats(ctx)))
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleLiveness(t *testing.T) {
+	handler := HandleLiveness()
+
+	req, err := http.NewRequest(http.MethodGet, "/api/health/live", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Wrong code received")
+	assert.JSONEq(t, testutil.ToJSONString(responseMsg{Message: "service alive"}), rr.Body.String(), "Wrong response body")
+}
+
+func TestHandleReadiness(t *testing.T) {
+	mockService := new(serviceMock.ReadinessChecker)
+	handler := HandleReadiness(mockService)
+
+	ready := models.Readiness{
+		Ready: true,
+		Components: []models.ComponentHealth{
+			{Name: "shutdown", Status: models.HealthStatusUp},
+			{Name: "database", Status: models.HealthStatusUp, Latency: 1500 * time.Microsecond},
+		},
+	}
+	notReady := models.Readiness{
+		Ready: false,
+		Components: []models.ComponentHealth{
+			{Name: "shutdown", Status: models.HealthStatusDraining},
+			{Name: "database", Status: models.HealthStatusDown, Latency: 2 * time.Second, Error: "timeout"},
+		},
+	}
+
+	tests := map[string]struct {
+		mockOutput   models.Readiness
+		expectedCode int
+		expectedBody string
+	}{
+		"all components up": {
+			mockOutput:   ready,
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseReadiness{
+				Status: "ready",
+				Components: []outputComponent{
+					{Name: "shutdown", Status: "up"},
+					{Name: "database", Status: "up", LatencyMS: 1.5},
+				},
+			}),
+		},
+		"component down": {
+			mockOutput:   notReady,
+			expectedCode: http.StatusServiceUnavailable,
+			expectedBody: testutil.ToJSONString(responseReadiness{
+				Status: "not ready",
+				Components: []outputComponent{
+					{Name: "shutdown", Status: "draining"},
+					{Name: "database", Status: "down", LatencyMS: 2000, Error: "timeout"},
+				},
+			}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/api/health/ready", nil)
+			assert.NoError(t, err)
+
+			mockService.
+				On("CheckReadiness", mock.Anything).
+				Return(tc.mockOutput).
+				Once()
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandlePoolStats(t *testing.T) {
+	mockService := new(serviceMock.PoolStatsReporter)
+	handler := HandlePoolStats(mockService)
+
+	mockService.
+		On("PoolStats", mock.Anything).
+		Return(models.PoolStats{
+			TotalConns:      4,
+			AcquiredConns:   1,
+			IdleConns:       3,
+			MaxConns:        25,
+			AcquireCount:    120,
+			AcquireDuration: 2500 * time.Microsecond,
+			NewConnsCount:   4,
+		}).
+		Once()
+
+	req, err := http.NewRequest(http.MethodGet, "/api/health/pool", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Wrong code received")
+	assert.JSONEq(t, testutil.ToJSONString(responsePoolStats{
+		TotalConns:        4,
+		AcquiredConns:     1,
+		IdleConns:         3,
+		MaxConns:          25,
+		AcquireCount:      120,
+		AcquireDurationMS: 2.5,
+		NewConnsCount:     4,
+	}), rr.Body.String(), "Wrong response body")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestHandleCacheStats(t *testing.T) {
+	mockService := new(serviceMock.CacheStatsReporter)
+	handler := HandleCacheStats(mockService)
+
+	mockService.
+		On("CacheStats", mock.Anything).
+		Return(models.CacheStats{
+			Hits:          30,
+			Misses:        10,
+			HitRatio:      0.75,
+			Invalidations: 2,
+			Entries:       8,
+		}).
+		Once()
+
+	req, err := http.NewRequest(http.MethodGet, "/api/health/cache", nil)
+	assert.NoError(t, err)
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusOK, rr.Code, "Wrong code received")
+	assert.JSONEq(t, testutil.ToJSONString(responseCacheStats{
+		Hits:          30,
+		Misses:        10,
+		HitRatio:      0.75,
+		Invalidations: 2,
+		Entries:       8,
+	}), rr.Body.String(), "Wrong response body")
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,424 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type PersonImporter interface {
+	ImportPersons(ctx context.Context, persons []models.Person, dryRun bool) (models.ImportResult, error)
+}
+
+var (
+	errImportFormat   = errors.New("import must be CSV (text/csv) or NDJSON (application/x-ndjson)")
+	errImportTooLarge = errors.New("import has too many rows")
+)
+
+// importMaxLineBytes bounds a single NDJSON line.
+const importMaxLineBytes = 1 << 20
+
+// importRow is one person read from an import, with the line it started on and anything wrong
+// with it.
+type importRow struct {
+	line     int
+	person   inputPerson
+	problems []problem
+}
+
+// HandleImportPersons is a Handler that creates persons in bulk from a CSV or NDJSON file. Either
+// every row is created, in one transaction, or none is and the response lists the problems found
+// on each row.
+//
+//	@Summary		Import Persons
+//	@Description	Creates persons in bulk from CSV or NDJSON, sent as the body or as the `file` part of a multipart form. CSV needs a header row naming first_name, last_name, type and age, and optionally sections (course section IDs separated by semicolons) and department_id; id and courses columns are ignored, so an export can be imported again. Every row is validated and its sections checked before anything is written, and nothing is written unless every row is valid.
+//	@Tags			person
+//	@Accept			text/csv
+//	@Accept			application/x-ndjson
+//	@Accept			mpfd
+//	@Produce		json
+//	@Param			file				formData	file	false	"CSV or NDJSON file, when sending a multipart form"
+//	@Param			dry_run				query		bool	false	"validate the import without writing it"
+//	@Param			Idempotency-Key		header		string	false	"key making the request safe to retry; a retry gets the first response"
+//	@Success		200					{object}	handlers.responseImport	"dry run found no problems"
+//	@Success		201					{object}	handlers.responseImport
+//	@Failure		400					{object}	handlers.responseErr
+//	@Failure		413					{object}	handlers.responseErr
+//	@Failure		409					{object}	handlers.responseErr	"an external ID is already in use"
+//	@Failure		415					{object}	handlers.responseErr
+//	@Failure		422					{object}	handlers.responseImport	"rows with problems, or a department that does not exist; nothing was written"
+//	@Failure		500					{object}	handlers.responseErr
+//	@Router			/api/person/import	[POST]
+func HandleImportPersons(service PersonImporter, maxRows int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleImportPersons")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		dryRun := false
+		if value := r.URL.Query().Get("dry_run"); value != "" {
+			var err error
+			if dryRun, err = strconv.ParseBool(value); err != nil {
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: fmt.Sprintf("dry_run must be true or false, got %q", value),
+				})
+				return
+			}
+		}
+
+		body, format, err := importBody(r)
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, errImportFormat) {
+				status = http.StatusUnsupportedMediaType
+			}
+			encodeResponse(ctx, w, status, responseErr{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		var rows []importRow
+		if format == formatCSV {
+			rows, err = parseCSVImport(body, maxRows)
+		} else {
+			rows, err = parseNDJSONImport(body, maxRows)
+		}
+		switch {
+		case errors.Is(err, errImportTooLarge):
+			encodeResponse(ctx, w, http.StatusRequestEntityTooLarge, responseErr{
+				Error: fmt.Sprintf("import must not have more than %d rows", maxRows),
+			})
+			return
+		case err != nil:
+			logger.Error("error reading import", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: err.Error(),
+			})
+			return
+		case len(rows) == 0:
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "import has no rows",
+			})
+			return
+		}
+		span.SetAttributes(attribute.String("import.format", format), attribute.Int("import.rows", len(rows)))
+		checkImportUniques(rows)
+
+		// Only valid rows are sent on, so that their courses can be checked too. If any row is
+		// invalid nothing is written.
+		var persons []models.Person
+		var sent []int
+		invalid := false
+		for i, row := range rows {
+			if len(row.problems) > 0 {
+				invalid = true
+				continue
+			}
+			person, _ := row.person.MapTo()
+			persons = append(persons, person)
+			sent = append(sent, i)
+		}
+
+		result, err := service.ImportPersons(ctx, persons, dryRun || invalid)
+		if errors.Is(err, models.ErrExternalIDTaken) || errors.Is(err, models.ErrEmailTaken) {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "an external ID or email in the import is already in use",
+			})
+			return
+		}
+		if errors.Is(err, models.ErrDepartmentNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, responseErr{
+				Error: "a department_id in the import does not exist; nothing was written",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error importing persons", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error importing persons",
+			})
+			return
+		}
+		for i, sectionIDs := range result.MissingSections {
+			row := &rows[sent[i]]
+			for _, sectionID := range sectionIDs {
+				row.problems = append(row.problems, problem{
+					Name:        "sections",
+					Description: fmt.Sprintf("section %d does not exist", sectionID),
+				})
+			}
+		}
+
+		response := responseImport{DryRun: dryRun, Rows: len(rows)}
+		for _, row := range rows {
+			if len(row.problems) > 0 {
+				response.Errors = append(response.Errors, outputRowError{Line: row.line, Problems: row.problems})
+			}
+		}
+		switch {
+		case len(response.Errors) > 0:
+			logger.Info("Rejected import", "rows", len(rows), "rows_with_problems", len(response.Errors))
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, response)
+		case dryRun:
+			encodeResponse(ctx, w, http.StatusOK, response)
+		default:
+			logger.Info("Imported persons", "rows", len(rows))
+			response.Imported = len(result.Persons)
+			response.Persons = mapMultipleOutputPerson(result.Persons)
+			encodeResponse(ctx, w, http.StatusCreated, response)
+		}
+	}
+}
+
+// importBody finds the file in an import request and its format. The file is either the whole
+// body, typed by the Content-Type header, or the `file` part of a multipart form, typed by the
+// part's Content-Type or else by its file extension.
+func importBody(r *http.Request) (io.Reader, string, error) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		format, ok := importFormat(mediaType, "")
+		if !ok {
+			return nil, "", errImportFormat
+		}
+		return r.Body, format, nil
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", fmt.Errorf("malformed multipart form: %w", err)
+	}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return nil, "", errors.New("multipart form has no file part")
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("malformed multipart form: %w", err)
+		}
+		if part.FormName() != "file" {
+			continue
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		format, ok := importFormat(partType, part.FileName())
+		if !ok {
+			return nil, "", errImportFormat
+		}
+		return part, format, nil
+	}
+}
+
+// importFormat maps a media type, or failing that a file name, to an import format.
+func importFormat(mediaType string, fileName string) (string, bool) {
+	switch mediaType {
+	case "text/csv":
+		return formatCSV, true
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return formatNDJSON, true
+	}
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return formatCSV, true
+	case ".ndjson", ".jsonl":
+		return formatNDJSON, true
+	}
+	return "", false
+}
+
+// parseCSVImport reads persons from CSV with a header row. Columns are matched by name, in any
+// order; the id, created_at and updated_at columns of an export are ignored, and age may be left
+// blank when date_of_birth is given. A row that cannot be read as CSV at all fails the
+// whole import, while a bad cell is reported as a problem with its row.
+func parseCSVImport(r io.Reader, maxRows int) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("malformed CSV: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "id", "first_name", "last_name", "type", "age", "courses", "external_id", "email", "phone",
+			"date_of_birth", "created_at", "updated_at", "department_id", "sections":
+		default:
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, fmt.Errorf("CSV column %q appears more than once", name)
+		}
+		columns[name] = i
+	}
+	for _, name := range []string{"first_name", "last_name", "type", "age"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV is missing the %q column", name)
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if len(rows) == maxRows {
+			return nil, errImportTooLarge
+		}
+		line, _ := reader.FieldPos(0)
+		row := importRow{line: line}
+		if err != nil {
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return nil, fmt.Errorf("malformed CSV: %w", err)
+			}
+			row.problems = append(row.problems, problem{
+				Name:        "row",
+				Description: fmt.Sprintf("must have %d fields, has %d", len(header), len(record)),
+			})
+			rows = append(rows, row)
+			continue
+		}
+
+		cell := func(name string) string {
+			return csvPlain(strings.TrimSpace(record[columns[name]]))
+		}
+		row.person.FirstName = cell("first_name")
+		row.person.LastName = cell("last_name")
+		row.person.Type = cell("type")
+		if _, ok := columns["date_of_birth"]; ok {
+			row.person.DateOfBirth = cell("date_of_birth")
+		}
+		if age, err := strconv.Atoi(cell("age")); err == nil {
+			row.person.Age = age
+		} else if cell("age") != "" || row.person.DateOfBirth == "" {
+			row.problems = append(row.problems, problem{
+				Name:        "age",
+				Description: "must be a whole number",
+			})
+		}
+		if _, ok := columns["external_id"]; ok {
+			row.person.ExternalID = cell("external_id")
+		}
+		if _, ok := columns["email"]; ok {
+			row.person.Email = cell("email")
+		}
+		if _, ok := columns["phone"]; ok {
+			row.person.Phone = cell("phone")
+		}
+		if _, ok := columns["department_id"]; ok && cell("department_id") != "" {
+			if id, err := strconv.Atoi(cell("department_id")); err == nil {
+				row.person.DepartmentID = &id
+			} else {
+				row.problems = append(row.problems, problem{
+					Name:        "department_id",
+					Description: "must be a positive integer",
+				})
+			}
+		}
+		// The courses of a person follow from their sections, so a courses column is not read.
+		if _, ok := columns["sections"]; ok && cell("sections") != "" {
+			for i, value := range strings.Split(cell("sections"), ";") {
+				id, err := strconv.Atoi(strings.TrimSpace(value))
+				if err != nil {
+					row.problems = append(row.problems, problem{
+						Name:        fmt.Sprintf("sections[%d]", i),
+						Description: "section ID must be a positive integer",
+					})
+					continue
+				}
+				row.person.Sections = append(row.person.Sections, id)
+			}
+		}
+		row.problems = append(row.problems, row.person.Valid()...)
+		rows = append(rows, row)
+	}
+}
+
+// checkImportUniques reports rows giving an external ID or email an earlier row already has, since
+// both are unique. Emails are compared without regard to case.
+func checkImportUniques(rows []importRow) {
+	externalIDs := map[string]int{}
+	emails := map[string]int{}
+	for i, row := range rows {
+		checkImportUnique(&rows[i], "external_id", row.person.ExternalID, externalIDs)
+		checkImportUnique(&rows[i], "email", strings.ToLower(row.person.Email), emails)
+	}
+}
+
+// checkImportUnique reports row if it repeats a value of the named field given on an earlier line.
+func checkImportUnique(row *importRow, name string, value string, seen map[string]int) {
+	if value == "" {
+		return
+	}
+	if line, ok := seen[value]; ok {
+		row.problems = append(row.problems, problem{
+			Name:        name,
+			Description: fmt.Sprintf("is also given on line %d", line),
+		})
+		return
+	}
+	seen[value] = row.line
+}
+
+// parseNDJSONImport reads persons from NDJSON, one JSON object per line. Blank lines are skipped,
+// and a line that is not a JSON object is reported as a problem with that row.
+func parseNDJSONImport(r io.Reader, maxRows int) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), importMaxLineBytes)
+
+	var rows []importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		if len(rows) == maxRows {
+			return nil, errImportTooLarge
+		}
+
+		row := importRow{line: line}
+		if err := json.Unmarshal(text, &row.person); err != nil {
+			row.problems = append(row.problems, problem{
+				Name:        "row",
+				Description: "must be a JSON object describing a person",
+			})
+		} else {
+			row.problems = row.person.Valid()
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("NDJSON line %d is longer than %d bytes", line+1, importMaxLineBytes)
+		}
+		return nil, fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleImportPersons(t *testing.T) {
+	john := models.Person{FirstName: "John", LastName: "Doe", Type: "student", Age: 25, Sections: []int{1, 2}}
+	jane := models.Person{FirstName: "Jane", LastName: "Roe", Type: "professor", Age: 40}
+	imported := []models.Person{john, jane}
+	imported[0].ID, imported[1].ID = 7, 8
+	numbered := john
+	numbered.ExternalID = "S-1"
+	contactable := john
+	contactable.Email = "john@example.com"
+	dateOfBirth := time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC)
+	born := jane
+	born.DateOfBirth = &dateOfBirth
+	born.Age = ageOn(dateOfBirth, time.Now())
+	departmentID := 2
+	placed := jane
+	placed.DepartmentID = &departmentID
+
+	tests := map[string]struct {
+		query        string
+		contentType  string
+		body         string
+		mockCalled   bool
+		mockPersons  []models.Person
+		mockDryRun   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"csv imported": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,sections\nJohn,Doe,student,25,1;2\nJane,Roe,professor,40,\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{john, jane},
+			mockOutput:   []any{models.ImportResult{Persons: imported}, nil},
+			expectedCode: http.StatusCreated,
+			expectedBody: testutil.ToJSONString(responseImport{
+				Rows: 2, Imported: 2, Persons: mapMultipleOutputPerson(imported),
+			}),
+		},
+		"csv export imported again": {
+			contentType:  "text/csv; charset=utf-8",
+			body:         "id,first_name,last_name,type,age,courses,sections\n3,John,Doe,student,25,9,1;2\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{john},
+			mockOutput:   []any{models.ImportResult{Persons: imported[:1]}, nil},
+			expectedCode: http.StatusCreated,
+			expectedBody: testutil.ToJSONString(responseImport{
+				Rows: 1, Imported: 1, Persons: mapMultipleOutputPerson(imported[:1]),
+			}),
+		},
+		"ndjson dry run": {
+			query:        "?dry_run=true",
+			contentType:  "application/x-ndjson",
+			body:         `{"first_name":"John","last_name":"Doe","type":"student","age":25,"sections":[1,2]}` + "\n\n" + `{"first_name":"Jane","last_name":"Roe","type":"professor","age":40}`,
+			mockCalled:   true,
+			mockPersons:  []models.Person{john, jane},
+			mockDryRun:   true,
+			mockOutput:   []any{models.ImportResult{Persons: []models.Person{john, jane}}, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseImport{DryRun: true, Rows: 2}),
+		},
+		"invalid rows reject the import": {
+			contentType:  "application/x-ndjson",
+			body:         `{"first_name":"John","last_name":"Doe","type":"student","age":25,"sections":[1,2]}` + "\n" + `{"first_name":"","last_name":"Roe","type":"janitor","age":40}` + "\nnot json\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{john},
+			mockDryRun:   true,
+			mockOutput:   []any{models.ImportResult{Persons: []models.Person{john}}, nil},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseImport{Rows: 3, Errors: []outputRowError{
+				{Line: 2, Problems: []problem{
+					{Name: "first_name", Description: "must not be blank"},
+					{Name: "type", Description: "must be either 'student' or 'professor'"},
+				}},
+				{Line: 3, Problems: []problem{{Name: "row", Description: "must be a JSON object describing a person"}}},
+			}}),
+		},
+		"bad csv cells are reported": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,sections\nJohn,Doe,student,old,1;x\nJane,Roe\n",
+			mockCalled:   true,
+			mockDryRun:   true,
+			mockOutput:   []any{models.ImportResult{}, nil},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseImport{Rows: 2, Errors: []outputRowError{
+				{Line: 2, Problems: []problem{
+					{Name: "age", Description: "must be a whole number"},
+					{Name: "sections[1]", Description: "section ID must be a positive integer"},
+				}},
+				{Line: 3, Problems: []problem{{Name: "row", Description: "must have 5 fields, has 2"}}},
+			}}),
+		},
+		"missing sections reject the import": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,sections\nJohn,Doe,student,25,1;2\nJane,Roe,professor,40,\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{john, jane},
+			mockOutput:   []any{models.ImportResult{MissingSections: map[int][]int{0: {2}}}, nil},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseImport{Rows: 2, Errors: []outputRowError{
+				{Line: 2, Problems: []problem{{Name: "sections", Description: "section 2 does not exist"}}},
+			}}),
+		},
+		"repeated external ids are reported": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,sections,external_id\nJohn,Doe,student,25,1;2,S-1\nJane,Roe,professor,40,,S-1\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{numbered},
+			mockDryRun:   true,
+			mockOutput:   []any{models.ImportResult{Persons: []models.Person{numbered}}, nil},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseImport{Rows: 2, Errors: []outputRowError{
+				{Line: 3, Problems: []problem{{Name: "external_id", Description: "is also given on line 2"}}},
+			}}),
+		},
+		"repeated emails are reported": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,sections,email\nJohn,Doe,student,25,1;2,john@example.com\nJane,Roe,professor,40,,JOHN@example.com\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{contactable},
+			mockDryRun:   true,
+			mockOutput:   []any{models.ImportResult{Persons: []models.Person{contactable}}, nil},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseImport{Rows: 2, Errors: []outputRowError{
+				{Line: 3, Problems: []problem{{Name: "email", Description: "is also given on line 2"}}},
+			}}),
+		},
+		"blank age derived from date of birth": {
+			query:        "?dry_run=true",
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,date_of_birth\nJane,Roe,professor,,2001-12-10\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{born},
+			mockDryRun:   true,
+			mockOutput:   []any{models.ImportResult{Persons: []models.Person{born}}, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseImport{DryRun: true, Rows: 1}),
+		},
+		"external id taken": {
+			contentType:  "application/x-ndjson",
+			body:         `{"first_name":"John","last_name":"Doe","type":"student","age":25,"external_id":"S-1","sections":[1,2]}`,
+			mockCalled:   true,
+			mockPersons:  []models.Person{numbered},
+			mockOutput:   []any{models.ImportResult{}, fmt.Errorf("wrapped: %w", models.ErrExternalIDTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "an external ID or email in the import is already in use"}),
+		},
+		"department missing": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,department_id\nJane,Roe,professor,40,2\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{placed},
+			mockOutput:   []any{models.ImportResult{}, fmt.Errorf("wrapped: %w", models.ErrDepartmentNotFound)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "a department_id in the import does not exist; nothing was written"}),
+		},
+		"unknown csv column": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,nickname\n",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: `unknown CSV column "nickname"`}),
+		},
+		"missing csv column": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type\n",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: `CSV is missing the "age" column`}),
+		},
+		"empty import": {
+			contentType:  "application/x-ndjson",
+			body:         "\n",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "import has no rows"}),
+		},
+		"too many rows": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age\nA,B,student,1\nC,D,student,2\nE,F,student,3\nG,H,student,4\n",
+			expectedCode: http.StatusRequestEntityTooLarge,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "import must not have more than 3 rows"}),
+		},
+		"unsupported content type": {
+			contentType:  "application/json",
+			body:         `[]`,
+			expectedCode: http.StatusUnsupportedMediaType,
+			expectedBody: testutil.ToJSONString(responseErr{Error: errImportFormat.Error()}),
+		},
+		"invalid dry run": {
+			query:        "?dry_run=maybe",
+			contentType:  "text/csv",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: `dry_run must be true or false, got "maybe"`}),
+		},
+		"internal server error": {
+			contentType:  "text/csv",
+			body:         "first_name,last_name,type,age,sections\nJohn,Doe,student,25,1;2\n",
+			mockCalled:   true,
+			mockPersons:  []models.Person{john},
+			mockOutput:   []any{models.ImportResult{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error importing persons"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.PersonImporter)
+			handler := HandleImportPersons(mockService, 3)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/person/import"+tc.query, strings.NewReader(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set("Content-Type", tc.contentType)
+
+			if tc.mockCalled {
+				mockService.
+					On("ImportPersons", mock.Anything, tc.mockPersons, tc.mockDryRun).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ImportPersons")
+			}
+		})
+	}
+}
+
+func TestHandleImportPersonsMultipart(t *testing.T) {
+	person := models.Person{FirstName: "John", LastName: "Doe", Type: "student", Age: 25}
+
+	tests := map[string]struct {
+		fileName     string
+		expectedCode int
+	}{
+		"csv by extension":    {fileName: "persons.csv", expectedCode: http.StatusOK},
+		"unknown file format": {fileName: "persons.xlsx", expectedCode: http.StatusUnsupportedMediaType},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.PersonImporter)
+			mockService.
+				On("ImportPersons", mock.Anything, []models.Person{person}, true).
+				Return(models.ImportResult{Persons: []models.Person{person}}, nil).
+				Maybe()
+			handler := HandleImportPersons(mockService, 10)
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			assert.NoError(t, form.WriteField("note", "spring intake"))
+			file, err := form.CreateFormFile("file", tc.fileName)
+			assert.NoError(t, err)
+			_, err = file.Write([]byte("first_name,last_name,type,age\nJohn,Doe,student,25\n"))
+			assert.NoError(t, err)
+			assert.NoError(t, form.Close())
+
+			req := httptest.NewRequest(http.MethodPost, "/api/person/import?dry_run=1", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, rr.Body.String())
+		})
+	}
+}
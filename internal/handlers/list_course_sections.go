@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type CourseSectionLister interface {
+	ListCourseSections(ctx context.Context, courseID int, termID int) ([]models.Section, error)
+}
+
+// HandleListCourseSections is a Handler that returns the sections of the course associated with
+// the given ID, optionally only those of one term.
+//
+//	@Summary		List course sections
+//	@Description	List the sections of a course, ordered by term and number
+//	@Tags			courses
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID							path		int	true	"ID of course"
+//	@Param			term						query		int	false	"only list the sections of this term"
+//	@Success		200							{object}	handlers.responseSections
+//	@Failure		400							{object}	handlers.responseErr
+//	@Failure		404							{object}	handlers.responseErr
+//	@Failure		500							{object}	handlers.responseErr
+//	@Router			/api/course/{ID}/sections	[GET]
+func HandleListCourseSections(service CourseSectionLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleListCourseSections")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		courseID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+		termID, problems := parseTermFilter(r.URL.Query())
+		if len(problems) > 0 {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				ValidationErrors: problems,
+			})
+			return
+		}
+
+		// get values from database
+		sections, err := service.ListCourseSections(ctx, courseID, termID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "course not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error getting course sections", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseSections{
+			Sections: mapMultipleOutputSection(sections),
+		})
+	}
+}
+
+// parseTermFilter reads the term ID to filter by from the query, where 0 means every term.
+func parseTermFilter(query url.Values) (int, []problem) {
+	value := query.Get("term")
+	if value == "" {
+		return 0, nil
+	}
+	termID, err := strconv.Atoi(value)
+	if err != nil || termID <= 0 {
+		return 0, []problem{{Name: "term", Description: "must be a positive integer"}}
+	}
+	return termID, nil
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListCourseSections(t *testing.T) {
+	instructorID := 2
+	sections := []models.Section{
+		{ID: 1, CourseID: 1, TermID: 1, Number: 1, InstructorID: &instructorID},
+		{ID: 4, CourseID: 1, TermID: 2, Number: 1},
+	}
+
+	tests := map[string]struct {
+		courseID     string
+		query        string
+		mockCalled   bool
+		mockTermID   int
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"sections listed": {
+			courseID:     "1",
+			mockCalled:   true,
+			mockOutput:   []any{sections, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseSections{Sections: mapMultipleOutputSection(sections)}),
+		},
+		"sections of a term listed": {
+			courseID:     "1",
+			query:        "?term=2",
+			mockCalled:   true,
+			mockTermID:   2,
+			mockOutput:   []any{sections[1:], nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseSections{Sections: mapMultipleOutputSection(sections[1:])}),
+		},
+		"invalid course ID": {
+			courseID:     "cs",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"invalid term": {
+			courseID:     "1",
+			query:        "?term=fall",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "term", Description: "must be a positive integer"}},
+			}),
+		},
+		"course not found": {
+			courseID:     "9",
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Section{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "course not found"}),
+		},
+		"internal server error": {
+			courseID:     "1",
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Section{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.CourseSectionLister)
+			handler := HandleListCourseSections(mockService)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/course/"+tc.courseID+"/sections"+tc.query, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.courseID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.courseID)
+				mockService.
+					On("ListCourseSections", mock.Anything, id, tc.mockTermID).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ListCourseSections")
+			}
+		})
+	}
+}
@@ -2,44 +2,140 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
-
-	"github.com/go-chi/httplog/v2"
+	"net/url"
+	"strconv"
 )
 
 type CourseLister interface {
-	ListCourses(ctx context.Context) ([]models.Course, error)
+	ListCourses(ctx context.Context, filter models.CourseFilter) ([]models.Course, error)
 }
 
-// HandleListCourses is a Handler that returns a list of all courses.
+// HandleListCourses is a Handler that returns a list of courses as JSON, NDJSON or CSV, optionally
+// filtered.
 //
-//	@Summary		List all courses
-//	@Description	List all courses
+//	@Summary		List courses
+//	@Description	List courses as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter. Filters combine, and a course must match all of them
 //	@Tags			courses
 //	@Accept			json
 //	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		text/csv
+//	@Param			format		query		string	false	"response format, overriding the Accept header"	Enums(json, ndjson, csv)
+//	@Param			level		query		string	false	"only courses at this level"	Enums(undergraduate, graduate)
+//	@Param			active		query		bool	false	"only active courses, or only inactive ones"
+//	@Param			min_credits	query		int		false	"only courses worth at least this many credits"
+//	@Param			max_credits	query		int		false	"only courses worth at most this many credits"
+//	@Param			code_prefix	query		string	false	"only courses whose code starts with this, such as CS"
 //	@Success		200			{object}	handlers.responseCourses
+//	@Failure		400			{object}	handlers.responseErr
 //	@Failure		500			{object}	handlers.responseErr
 //	@Router			/api/course	[GET]
-func HandleListCourses(logger *httplog.Logger, service CourseLister) http.HandlerFunc {
+func HandleListCourses(service CourseLister) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// setup
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleListCourses")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		format, err := negotiateFormat(r)
+		if err != nil {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		filter, problems := parseCourseFilter(r.URL.Query())
+		if len(problems) > 0 {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				ValidationErrors: problems,
+			})
+			return
+		}
 
 		// get values from database
-		courses, err := service.ListCourses(ctx)
+		courses, err := service.ListCourses(ctx, filter)
 		if err != nil {
 			logger.Error("error getting all courses", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
-		coursesOut := mapMultipleOutputCourse(courses)
-		encodeResponse(w, logger, http.StatusOK, responseCourses{
-			Courses: coursesOut,
+		stream := newListStream(w, format, "courses", courseCSVColumns)
+		for _, course := range courses {
+			if err := stream.Write(mapOutputCourse(course)); err != nil {
+				logger.Error("error writing courses", "error", err)
+				tracing.RecordError(span, err)
+				return
+			}
+		}
+		if err := stream.Close(); err != nil {
+			logger.Error("error writing courses", "error", err)
+			tracing.RecordError(span, err)
+		}
+	}
+}
+
+// parseCourseFilter reads the filters of a course list from its query parameters.
+func parseCourseFilter(query url.Values) (models.CourseFilter, []problem) {
+	var filter models.CourseFilter
+	var problems []problem
+
+	if level := query.Get("level"); level != "" {
+		if !validCourseLevels[level] {
+			problems = append(problems, problem{
+				Name:        "level",
+				Description: "must be either 'undergraduate' or 'graduate'",
+			})
+		}
+		filter.Level = level
+	}
+	if value := query.Get("active"); value != "" {
+		active, err := strconv.ParseBool(value)
+		if err != nil {
+			problems = append(problems, problem{
+				Name:        "active",
+				Description: "must be true or false",
+			})
+		}
+		filter.Active = &active
+	}
+	bounds := []struct {
+		name    string
+		credits *int
+	}{
+		{name: "min_credits", credits: &filter.MinCredits},
+		{name: "max_credits", credits: &filter.MaxCredits},
+	}
+	for _, bound := range bounds {
+		value := query.Get(bound.name)
+		if value == "" {
+			continue
+		}
+		var err error
+		*bound.credits, err = strconv.Atoi(value)
+		if err != nil || *bound.credits < minCourseCredits || *bound.credits > maxCourseCredits {
+			problems = append(problems, problem{
+				Name:        bound.name,
+				Description: fmt.Sprintf("must be a whole number from %d to %d", minCourseCredits, maxCourseCredits),
+			})
+		}
+	}
+	if filter.MinCredits != 0 && filter.MaxCredits != 0 && filter.MinCredits > filter.MaxCredits {
+		problems = append(problems, problem{
+			Name:        "max_credits",
+			Description: "must not be less than min_credits",
 		})
 	}
+	filter.CodePrefix = query.Get("code_prefix")
+
+	return filter, problems
 }
@@ -13,27 +13,29 @@ import (
 	serviceMock "go-api-tech-challenge/internal/handlers/mock"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleListUsers(t *testing.T) {
 	mockService := new(serviceMock.CourseLister)
-	logger := httplog.NewLogger("test")
-	handler := HandleListCourses(logger, mockService)
+	handler := HandleListCourses(mockService)
 
 	courses := []models.Course{
-		{ID: 1, Name: "Databases"},
-		{ID: 2, Name: "Operating Systems"},
+		{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true},
+		{ID: 2, Code: "CS340", Name: "Operating Systems", Credits: 4, Level: "undergraduate", Active: true},
 	}
 
 	coursesOut := mapMultipleOutputCourse(courses)
+	active := true
 
 	tests := map[string]struct {
-		mockCalled   bool
-		mockOutput   []any
-		expectedCode int
-		expectedBody string
+		query          string
+		mockCalled     bool
+		expectedFilter models.CourseFilter
+		mockOutput     []any
+		expectedCode   int
+		expectedBody   string
 	}{
 		"courses returned": {
 			mockCalled:   true,
@@ -41,6 +43,34 @@ func TestHandleListUsers(t *testing.T) {
 			expectedCode: http.StatusOK,
 			expectedBody: testutil.ToJSONString(responseCourses{Courses: coursesOut}),
 		},
+		"filtered": {
+			query:      "?level=undergraduate&active=true&min_credits=3&max_credits=4&code_prefix=cs",
+			mockCalled: true,
+			expectedFilter: models.CourseFilter{
+				Level: "undergraduate", Active: &active, MinCredits: 3, MaxCredits: 4, CodePrefix: "cs",
+			},
+			mockOutput:   []any{courses, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseCourses{Courses: coursesOut}),
+		},
+		"invalid filters": {
+			query:        "?level=postdoc&active=yes&min_credits=5&max_credits=two",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "level", Description: "must be either 'undergraduate' or 'graduate'"},
+				{Name: "active", Description: "must be true or false"},
+				{Name: "max_credits", Description: "must be a whole number from 1 to 12"},
+			}}),
+		},
+		"credit range reversed": {
+			query:        "?min_credits=5&max_credits=2",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "max_credits", Description: "must not be less than min_credits"},
+			}}),
+		},
 		"no users found": {
 			mockCalled:   true,
 			mockOutput:   []any{[]models.Course{}, nil},
@@ -57,7 +87,7 @@ func TestHandleListUsers(t *testing.T) {
 
 	for name, tc := range tests {
 		t.Run(name, func(t *testing.T) {
-			req, err := http.NewRequest(http.MethodGet, "/api/course", nil)
+			req, err := http.NewRequest(http.MethodGet, "/api/course"+tc.query, nil)
 			assert.NoError(t, err)
 
 			// Add chi URLParam
@@ -67,7 +97,7 @@ func TestHandleListUsers(t *testing.T) {
 
 			if tc.mockCalled {
 				mockService.
-					On("ListCourses", ctx).
+					On("ListCourses", mock.Anything, tc.expectedFilter).
 					Return(tc.mockOutput...).
 					Once()
 			}
@@ -86,3 +116,68 @@ func TestHandleListUsers(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleListCoursesExport(t *testing.T) {
+	departmentID, capacity := 1, 30
+	courses := []models.Course{
+		{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Description: "SQL, indexes", Level: "undergraduate", Active: true,
+			DepartmentID: &departmentID, Capacity: &capacity},
+		{ID: 2, Code: "CS999", Name: "=cmd()", Credits: 1, Level: "graduate"},
+	}
+
+	tests := map[string]struct {
+		url                 string
+		accept              string
+		mockCalled          bool
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		"csv from accept header": {
+			url:                 "/api/course",
+			accept:              "text/csv",
+			mockCalled:          true,
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,name,code,credits,description,level,active,department_id,capacity\n" +
+				"1,Databases,CS220,3,\"SQL, indexes\",undergraduate,true,1,30\n" +
+				"2,'=cmd(),CS999,1,,graduate,false,,\n",
+		},
+		"ndjson from query parameter": {
+			url:                 "/api/course?format=ndjson",
+			mockCalled:          true,
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"id":1,"code":"CS220","name":"Databases","credits":3,"description":"SQL, indexes","level":"undergraduate","active":true,"department_id":1,"capacity":30}` + "\n" +
+				`{"id":2,"code":"CS999","name":"=cmd()","credits":1,"level":"graduate","active":false}` + "\n",
+		},
+		"unknown format": {
+			url:                 "/api/course?format=xml",
+			expectedCode:        http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        testutil.ToJSONString(responseErr{Error: `format must be one of json, ndjson or csv, got "xml"`}) + "\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.CourseLister)
+			handler := HandleListCourses(mockService)
+			if tc.mockCalled {
+				mockService.On("ListCourses", mock.Anything, models.CourseFilter{}).Return(courses, nil).Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListDepartmentCourses(t *testing.T) {
+	departmentID := 1
+	courses := []models.Course{
+		{ID: 1, Code: "CS101", Name: "Programming", Credits: 4, Level: "undergraduate", Active: true, DepartmentID: &departmentID},
+		{ID: 2, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true, DepartmentID: &departmentID},
+	}
+
+	tests := map[string]struct {
+		departmentID        string
+		query               string
+		mockCalled          bool
+		mockOutput          []any
+		expectedCode        int
+		expectedContentType string
+		expectedBody        string
+	}{
+		"courses listed": {
+			departmentID:        "1",
+			mockCalled:          true,
+			mockOutput:          []any{courses, nil},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody:        testutil.ToJSONString(responseCourses{Courses: mapMultipleOutputCourse(courses)}),
+		},
+		"courses listed as csv": {
+			departmentID:        "1",
+			query:               "?format=csv",
+			mockCalled:          true,
+			mockOutput:          []any{courses[:1], nil},
+			expectedCode:        http.StatusOK,
+			expectedContentType: "text/csv; charset=utf-8",
+			expectedBody: "id,name,code,credits,description,level,active,department_id,capacity\n" +
+				"1,Programming,CS101,4,,undergraduate,true,1,\n",
+		},
+		"invalid department ID": {
+			departmentID:        "cs",
+			mockCalled:          false,
+			expectedCode:        http.StatusBadRequest,
+			expectedContentType: "application/json",
+			expectedBody:        testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"department not found": {
+			departmentID:        "9",
+			mockCalled:          true,
+			mockOutput:          []any{[]models.Course{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode:        http.StatusNotFound,
+			expectedContentType: "application/json",
+			expectedBody:        testutil.ToJSONString(responseErr{Error: "department not found"}),
+		},
+		"internal server error": {
+			departmentID:        "1",
+			mockCalled:          true,
+			mockOutput:          []any{[]models.Course{}, errors.New("test error")},
+			expectedCode:        http.StatusInternalServerError,
+			expectedContentType: "application/json",
+			expectedBody:        testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.DepartmentCourseLister)
+			handler := HandleListDepartmentCourses(mockService)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/department/"+tc.departmentID+"/courses"+tc.query, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.departmentID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.departmentID)
+				mockService.
+					On("ListDepartmentCourses", mock.Anything, id).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"))
+			if tc.expectedContentType == "application/json" {
+				assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+			} else {
+				assert.Equal(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+			}
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ListDepartmentCourses")
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DepartmentProfessorLister interface {
+	ListDepartmentProfessors(ctx context.Context, id int) ([]models.Person, error)
+}
+
+// HandleListDepartmentProfessors is a Handler that returns the professors of the department associated
+// with the given ID, as JSON, NDJSON or CSV.
+//
+//	@Summary		List department professors
+//	@Description	List the professors of a department as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter
+//	@Tags			departments
+//	@Accept			json
+//	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		text/csv
+//	@Param			ID								path		int		true	"ID of department"
+//	@Param			format							query		string	false	"response format, overriding the Accept header"	Enums(json, ndjson, csv)
+//	@Success		200								{object}	handlers.responsePersons
+//	@Failure		400								{object}	handlers.responseErr
+//	@Failure		404								{object}	handlers.responseErr
+//	@Failure		500								{object}	handlers.responseErr
+//	@Router			/api/department/{ID}/professors	[GET]
+func HandleListDepartmentProfessors(service DepartmentProfessorLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleListDepartmentProfessors")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		departmentID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+		format, err := negotiateFormat(r)
+		if err != nil {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		// get values from database
+		professors, err := service.ListDepartmentProfessors(ctx, departmentID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "department not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error getting department professors", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		stream := newListStream(w, format, "persons", personCSVColumns)
+		for _, professor := range professors {
+			if err := stream.Write(mapOutputPerson(professor)); err != nil {
+				logger.Error("error writing professors", "error", err)
+				tracing.RecordError(span, err)
+				return
+			}
+		}
+		if err := stream.Close(); err != nil {
+			logger.Error("error writing professors", "error", err)
+			tracing.RecordError(span, err)
+		}
+	}
+}
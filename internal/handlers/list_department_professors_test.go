@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListDepartmentProfessors(t *testing.T) {
+	departmentID := 1
+	professors := []models.Person{
+		{ID: 2, FirstName: "Jeff", LastName: "Bezos", Type: "professor", Age: 60, DepartmentID: &departmentID, Courses: []int{1, 2}},
+	}
+
+	tests := map[string]struct {
+		departmentID string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"professors listed": {
+			departmentID: "1",
+			mockCalled:   true,
+			mockOutput:   []any{professors, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responsePersons{Persons: mapMultipleOutputPerson(professors)}),
+		},
+		"no professors": {
+			departmentID: "2",
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Person{}, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"persons": []}`,
+		},
+		"invalid department ID": {
+			departmentID: "cs",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"department not found": {
+			departmentID: "9",
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Person{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "department not found"}),
+		},
+		"internal server error": {
+			departmentID: "1",
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Person{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.DepartmentProfessorLister)
+			handler := HandleListDepartmentProfessors(mockService)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/department/"+tc.departmentID+"/professors", nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.departmentID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.departmentID)
+				mockService.
+					On("ListDepartmentProfessors", mock.Anything, id).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ListDepartmentProfessors")
+			}
+		})
+	}
+}
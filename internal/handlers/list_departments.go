@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+)
+
+type DepartmentLister interface {
+	ListDepartments(ctx context.Context) ([]models.Department, error)
+}
+
+// HandleListDepartments is a Handler that returns a list of all departments.
+//
+//	@Summary		List departments
+//	@Description	List all departments
+//	@Tags			departments
+//	@Accept			json
+//	@Produce		json
+//	@Success		200				{object}	handlers.responseDepartments
+//	@Failure		500				{object}	handlers.responseErr
+//	@Router			/api/department	[GET]
+func HandleListDepartments(service DepartmentLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleListDepartments")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		// get values from database
+		departments, err := service.ListDepartments(ctx)
+		if err != nil {
+			logger.Error("error getting all departments", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseDepartments{
+			Departments: mapMultipleOutputDepartment(departments),
+		})
+	}
+}
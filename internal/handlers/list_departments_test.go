@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListDepartments(t *testing.T) {
+	departments := []models.Department{{ID: 1, Name: "Computer Science"}, {ID: 2, Name: "Design"}}
+
+	tests := map[string]struct {
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"departments listed": {
+			mockOutput:   []any{departments, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseDepartments{Departments: mapMultipleOutputDepartment(departments)}),
+		},
+		"no departments": {
+			mockOutput:   []any{[]models.Department{}, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"departments": []}`,
+		},
+		"internal server error": {
+			mockOutput:   []any{[]models.Department{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.DepartmentLister)
+			handler := HandleListDepartments(mockService)
+			mockService.
+				On("ListDepartments", mock.Anything).
+				Return(tc.mockOutput...).
+				Once()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/department", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
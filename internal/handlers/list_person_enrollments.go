@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PersonEnrollmentLister interface {
+	ListPersonEnrollments(ctx context.Context, name string, termID int) ([]models.Enrollment, error)
+}
+
+// HandleListPersonEnrollments is a Handler that returns the sections a person is enrolled in,
+// optionally only those of one term.
+//
+//	@Summary		List person enrollments
+//	@Description	List the sections a person is enrolled in, with their course and term, ordered by term and course
+//	@Tags			person
+//	@Accept			json
+//	@Produce		json
+//	@Param			name							path		string	true	"last name of person"
+//	@Param			term							query		int		false	"only list the enrollments of this term"
+//	@Success		200								{object}	handlers.responseEnrollments
+//	@Failure		400								{object}	handlers.responseErr
+//	@Failure		404								{object}	handlers.responseErr
+//	@Failure		500								{object}	handlers.responseErr
+//	@Router			/api/person/{name}/enrollments	[GET]
+func HandleListPersonEnrollments(service PersonEnrollmentLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleListPersonEnrollments")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		name := chi.URLParam(r, "name")
+
+		termID, problems := parseTermFilter(r.URL.Query())
+		if len(problems) > 0 {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				ValidationErrors: problems,
+			})
+			return
+		}
+
+		// get values from database
+		enrollments, err := service.ListPersonEnrollments(ctx, name, termID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "person not found",
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error getting person enrollments", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseEnrollments{
+			Enrollments: mapMultipleOutputEnrollment(enrollments),
+		})
+	}
+}
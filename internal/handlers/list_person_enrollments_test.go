@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListPersonEnrollments(t *testing.T) {
+	instructorID := 2
+	enrollments := []models.Enrollment{
+		{
+			Section:    models.Section{ID: 1, CourseID: 1, TermID: 1, Number: 1, InstructorID: &instructorID},
+			CourseCode: "CS101",
+			CourseName: "Programming",
+			TermName:   "Fall 2026",
+		},
+		{
+			Section:    models.Section{ID: 4, CourseID: 2, TermID: 2, Number: 1},
+			CourseCode: "DES110",
+			CourseName: "Design",
+			TermName:   "Spring 2027",
+		},
+	}
+
+	tests := map[string]struct {
+		query        string
+		mockCalled   bool
+		mockTermID   int
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"enrollments listed": {
+			mockCalled:   true,
+			mockOutput:   []any{enrollments, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseEnrollments{Enrollments: mapMultipleOutputEnrollment(enrollments)}),
+		},
+		"enrollments of a term listed": {
+			query:        "?term=2",
+			mockCalled:   true,
+			mockTermID:   2,
+			mockOutput:   []any{enrollments[1:], nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseEnrollments{Enrollments: mapMultipleOutputEnrollment(enrollments[1:])}),
+		},
+		"invalid term": {
+			query:        "?term=0",
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "term", Description: "must be a positive integer"}},
+			}),
+		},
+		"person not found": {
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Enrollment{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "person not found"}),
+		},
+		"internal server error": {
+			mockCalled:   true,
+			mockOutput:   []any{[]models.Enrollment{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.PersonEnrollmentLister)
+			handler := HandleListPersonEnrollments(mockService)
+
+			req, err := http.NewRequest(http.MethodGet, "/api/person/Doe/enrollments"+tc.query, nil)
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("name", "Doe")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				mockService.
+					On("ListPersonEnrollments", mock.Anything, "Doe", tc.mockTermID).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "ListPersonEnrollments")
+			}
+		})
+	}
+}
@@ -2,44 +2,65 @@ package handlers
 
 import (
 	"context"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
-
-	"github.com/go-chi/httplog/v2"
 )
 
 type PersonLister interface {
-	ListPersons(ctx context.Context) ([]models.Person, error)
+	StreamPersons(ctx context.Context, fn func(models.Person) error) error
 }
 
-// HandleListPersons is a Handler that returns a list of all persons.
+// HandleListPersons is a Handler that returns a list of all persons as JSON, NDJSON or CSV. Persons
+// are written out as they are read from the database, so the list is never held in memory.
 //
 //	@Summary		List all Persons
-//	@Description	List all persons
+//	@Description	List all persons as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter
 //	@Tags			person
 //	@Accept			json
 //	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		text/csv
+//	@Param			format		query		string	false	"response format, overriding the Accept header"	Enums(json, ndjson, csv)
 //	@Success		200			{object}	handlers.responsePersons
+//	@Failure		400			{object}	handlers.responseErr
 //	@Failure		500			{object}	handlers.responseErr
 //	@Router			/api/person	[GET]
-func HandleListPersons(logger *httplog.Logger, service PersonLister) http.HandlerFunc {
+func HandleListPersons(service PersonLister) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// setup
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleListPersons")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 
-		// get values from database
-		persons, err := service.ListPersons(ctx)
+		format, err := negotiateFormat(r)
 		if err != nil {
-			logger.Error("error getting all courses", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
-				Error: "Error retrieving data",
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: err.Error(),
 			})
 			return
 		}
 
-		personsOut := mapMultipleOutputPerson(persons)
-		encodeResponse(w, logger, http.StatusOK, responsePersons{
-			Persons: personsOut,
+		// stream values from database
+		stream := newListStream(w, format, "persons", personCSVColumns)
+		err = service.StreamPersons(ctx, func(person models.Person) error {
+			return stream.Write(mapOutputPerson(person))
 		})
+		if err != nil {
+			logger.Error("error getting all persons", "error", err)
+			tracing.RecordError(span, err)
+			if stream.Started() {
+				abortStream()
+			}
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+		if err := stream.Close(); err != nil {
+			logger.Error("error writing persons", "error", err)
+			tracing.RecordError(span, err)
+		}
 	}
 }
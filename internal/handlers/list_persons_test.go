@@ -6,19 +6,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	serviceMock "go-api-tech-challenge/internal/handlers/mock"
 	"go-api-tech-challenge/internal/models"
 	"go-api-tech-challenge/internal/testutil"
 
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleListPersons(t *testing.T) {
 	mockService := new(serviceMock.PersonLister)
-	logger := httplog.NewLogger("test")
-	handler := HandleListPersons(logger, mockService)
+	handler := HandleListPersons(mockService)
 
 	persons := []models.Person{
 		{
@@ -42,29 +42,33 @@ func TestHandleListPersons(t *testing.T) {
 	personsOut := mapMultipleOutputPerson(persons)
 
 	tests := map[string]struct {
-		mockCalled   bool
-		mockOutput   []any
-		expectedCode int
-		expectedBody string
+		streamed      []models.Person
+		streamErr     error
+		expectedCode  int
+		expectedBody  string
+		expectedAbort bool
 	}{
 		"persons found": {
-			mockCalled:   true,
-			mockOutput:   []any{persons, nil},
+			streamed:     persons,
 			expectedCode: http.StatusOK,
 			expectedBody: testutil.ToJSONString(responsePersons{Persons: personsOut}),
 		},
 		"no persons found": {
-			mockCalled:   true,
-			mockOutput:   []any{[]models.Person{}, nil},
+			streamed:     []models.Person{},
 			expectedCode: http.StatusOK,
 			expectedBody: testutil.ToJSONString(responsePersons{Persons: []outputPerson{}}),
 		},
 		"internal server error": {
-			mockCalled:   true,
-			mockOutput:   []any{[]models.Person{}, errors.New("test error")},
+			streamed:     []models.Person{},
+			streamErr:    errors.New("test error"),
 			expectedCode: http.StatusInternalServerError,
 			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
 		},
+		"error after streaming started": {
+			streamed:      persons[:1],
+			streamErr:     errors.New("connection reset"),
+			expectedAbort: true,
+		},
 	}
 
 	for name, tc := range tests {
@@ -72,24 +76,96 @@ func TestHandleListPersons(t *testing.T) {
 			req, err := http.NewRequest(http.MethodGet, "/api/persons", nil)
 			assert.NoError(t, err)
 
-			if tc.mockCalled {
-				mockService.
-					On("ListPersons", context.Background()).
-					Return(tc.mockOutput...).
-					Once()
-			}
+			mockService.
+				On("StreamPersons", mock.Anything, mock.Anything).
+				Return(func(ctx context.Context, fn func(models.Person) error) error {
+					for _, person := range tc.streamed {
+						if err := fn(person); err != nil {
+							return err
+						}
+					}
+					return tc.streamErr
+				}).
+				Once()
 
 			rr := httptest.NewRecorder()
+			if tc.expectedAbort {
+				assert.PanicsWithValue(t, http.ErrAbortHandler, func() { handler.ServeHTTP(rr, req) })
+				mockService.AssertExpectations(t)
+				return
+			}
 			handler.ServeHTTP(rr, req)
 
 			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
 			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
 
-			if tc.mockCalled {
-				mockService.AssertExpectations(t)
-			} else {
-				mockService.AssertNotCalled(t, "ListPersons")
-			}
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestHandleListPersonsExport(t *testing.T) {
+	created := time.Date(2026, time.September, 1, 9, 30, 0, 0, time.UTC)
+	dateOfBirth := time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC)
+	departmentID := 1
+	persons := []models.Person{
+		{ID: 1, FirstName: "John", LastName: "Doe", Type: "student", Age: 25, ExternalID: "S-1", Email: "john@example.com",
+			Phone: "+1 555 0100", DateOfBirth: &dateOfBirth, Sections: []int{1, 4}, Courses: []int{1, 2}, CreatedAt: created, UpdatedAt: created},
+		{ID: 2, FirstName: "Jane", LastName: "Smith, Jr.", Type: "professor", Age: 35, DepartmentID: &departmentID,
+			CreatedAt: created, UpdatedAt: created},
+	}
+
+	tests := map[string]struct {
+		format                     string
+		expectedContentType        string
+		expectedContentDisposition string
+		expectedBody               string
+	}{
+		"csv": {
+			format:                     "csv",
+			expectedContentType:        "text/csv; charset=utf-8",
+			expectedContentDisposition: `attachment; filename="persons.csv"`,
+			expectedBody: "id,first_name,last_name,type,age,courses,external_id,email,phone,date_of_birth,created_at,updated_at,department_id,sections\n" +
+				"1,John,Doe,student,25,1;2,S-1,john@example.com,'+1 555 0100,2001-12-10,2026-09-01T09:30:00Z,2026-09-01T09:30:00Z,,1;4\n" +
+				"2,Jane,\"Smith, Jr.\",professor,35,,,,,,2026-09-01T09:30:00Z,2026-09-01T09:30:00Z,1,\n",
+		},
+		"ndjson": {
+			format:              "ndjson",
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"id":1,"first_name":"John","last_name":"Doe","type":"student","age":25,"external_id":"S-1",` +
+				`"email":"john@example.com","phone":"+1 555 0100","date_of_birth":"2001-12-10","sections":[1,4],"courses":[1,2],` +
+				`"created_at":"2026-09-01T09:30:00Z","updated_at":"2026-09-01T09:30:00Z"}` + "\n" +
+				`{"id":2,"first_name":"Jane","last_name":"Smith, Jr.","type":"professor","age":35,"department_id":1,` +
+				`"created_at":"2026-09-01T09:30:00Z","updated_at":"2026-09-01T09:30:00Z"}` + "\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.PersonLister)
+			handler := HandleListPersons(mockService)
+			mockService.
+				On("StreamPersons", mock.Anything, mock.Anything).
+				Return(func(ctx context.Context, fn func(models.Person) error) error {
+					for _, person := range persons {
+						if err := fn(person); err != nil {
+							return err
+						}
+					}
+					return nil
+				}).
+				Once()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/person?format="+tc.format, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, http.StatusOK, rr.Code, "Wrong code received")
+			assert.Equal(t, tc.expectedContentType, rr.Header().Get("Content-Type"))
+			assert.Equal(t, tc.expectedContentDisposition, rr.Header().Get("Content-Disposition"))
+			assert.Equal(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+			mockService.AssertExpectations(t)
 		})
 	}
 }
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+)
+
+type TermLister interface {
+	ListTerms(ctx context.Context) ([]models.Term, error)
+}
+
+// HandleListTerms is a Handler that returns a list of all terms.
+//
+//	@Summary		List terms
+//	@Description	List all terms
+//	@Tags			terms
+//	@Accept			json
+//	@Produce		json
+//	@Success		200			{object}	handlers.responseTerms
+//	@Failure		500			{object}	handlers.responseErr
+//	@Router			/api/term	[GET]
+func HandleListTerms(service TermLister) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleListTerms")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		// get values from database
+		terms, err := service.ListTerms(ctx)
+		if err != nil {
+			logger.Error("error getting all terms", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error retrieving data",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseTerms{
+			Terms: mapMultipleOutputTerm(terms),
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleListTerms(t *testing.T) {
+	terms := []models.Term{
+		{
+			ID:       1,
+			Name:     "Fall 2026",
+			StartsOn: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+			EndsOn:   time.Date(2026, time.December, 18, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:       2,
+			Name:     "Spring 2027",
+			StartsOn: time.Date(2027, time.January, 18, 0, 0, 0, 0, time.UTC),
+			EndsOn:   time.Date(2027, time.May, 14, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	tests := map[string]struct {
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"terms listed": {
+			mockOutput:   []any{terms, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseTerms{Terms: mapMultipleOutputTerm(terms)}),
+		},
+		"no terms": {
+			mockOutput:   []any{[]models.Term{}, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"terms": []}`,
+		},
+		"internal server error": {
+			mockOutput:   []any{[]models.Term{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.TermLister)
+			handler := HandleListTerms(mockService)
+			mockService.
+				On("ListTerms", mock.Anything).
+				Return(tc.mockOutput...).
+				Once()
+
+			req, err := http.NewRequest(http.MethodGet, "/api/term", nil)
+			assert.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
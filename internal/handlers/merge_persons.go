@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type PersonMerger interface {
+	MergePersons(ctx context.Context, survivorID int, duplicateID int) (models.Person, error)
+}
+
+// HandleMergePersons is a Handler that merges a duplicate person into the person in the path. The
+// survivor is enrolled in the duplicate's courses and the duplicate is deleted. Both must be of the
+// same type.
+//
+//	@Summary		Merge Persons
+//	@Description	Merges a duplicate person of the same type into this one, moving their enrollments and deleting the duplicate
+//	@Tags			person
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		int					true	"ID of the person to keep"
+//	@Param			merge					body		handlers.inputMerge	true	"Person to merge in"
+//	@Success		200						{object}	handlers.responsePerson
+//	@Failure		400						{object}	handlers.responseErr
+//	@Failure		404						{object}	handlers.responseErr
+//	@Failure		422						{object}	handlers.responseErr
+//	@Failure		500						{object}	handlers.responseErr
+//	@Router			/api/person/{id}/merge	[POST]
+func HandleMergePersons(service PersonMerger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleMergePersons")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		survivorID, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil || survivorID <= 0 {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "id must be a positive integer",
+			})
+			return
+		}
+
+		var input inputMerge
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			logger.Error("BodyParser error", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "missing values or malformed body",
+			})
+			return
+		}
+		if problems := input.Valid(survivorID); len(problems) > 0 {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				ValidationErrors: problems,
+			})
+			return
+		}
+
+		person, err := service.MergePersons(ctx, survivorID, input.DuplicateID)
+		if errors.Is(err, models.ErrNotFound) {
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "both persons must exist",
+			})
+			return
+		}
+		if errors.Is(err, models.ErrPersonTypesDiffer) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, responseErr{
+				ValidationErrors: []problem{{Name: "duplicate_id", Description: "must be of the same type as the person being merged into"}},
+			})
+			return
+		}
+		if err != nil {
+			logger.Error("error merging persons", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error merging persons",
+			})
+			return
+		}
+
+		logger.Info("Merged persons", "survivor_id", survivorID, "duplicate_id", input.DuplicateID)
+		encodeResponse(ctx, w, http.StatusOK, responsePerson{
+			Person: mapOutputPerson(person),
+		})
+	}
+}
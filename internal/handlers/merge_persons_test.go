@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleMergePersons(t *testing.T) {
+	survivor := models.Person{ID: 1, FirstName: "Bill", LastName: "Gates", Type: "student", Age: 67, ExternalID: "S-1", Courses: []int{1, 2}}
+
+	tests := map[string]struct {
+		id           string
+		body         string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"persons merged": {
+			id:           "1",
+			body:         `{"duplicate_id": 2}`,
+			mockCalled:   true,
+			mockOutput:   []any{survivor, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responsePerson{Person: mapOutputPerson(survivor)}),
+		},
+		"invalid id": {
+			id:           "abc",
+			body:         `{"duplicate_id": 2}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "id must be a positive integer"}),
+		},
+		"invalid body": {
+			id:           "1",
+			body:         `invalid body`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "missing values or malformed body"}),
+		},
+		"missing duplicate": {
+			id:           "1",
+			body:         `{}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "duplicate_id", Description: "must be a positive integer"},
+			}}),
+		},
+		"merge into itself": {
+			id:           "1",
+			body:         `{"duplicate_id": 1}`,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "duplicate_id", Description: "must not be the person being merged into"},
+			}}),
+		},
+		"person not found": {
+			id:           "1",
+			body:         `{"duplicate_id": 2}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "both persons must exist"}),
+		},
+		"persons of different types": {
+			id:           "1",
+			body:         `{"duplicate_id": 2}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrPersonTypesDiffer)},
+			expectedCode: http.StatusUnprocessableEntity,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "duplicate_id", Description: "must be of the same type as the person being merged into"},
+			}}),
+		},
+		"internal server error": {
+			id:           "1",
+			body:         `{"duplicate_id": 2}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error merging persons"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.PersonMerger)
+			handler := HandleMergePersons(mockService)
+
+			req, err := http.NewRequest(http.MethodPost, "/api/person/"+tc.id+"/merge", strings.NewReader(tc.body))
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tc.id)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				mockService.
+					On("MergePersons", mock.Anything, 1, 2).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "MergePersons")
+			}
+		})
+	}
+}
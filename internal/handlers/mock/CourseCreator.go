@@ -15,9 +15,9 @@ type CourseCreator struct {
 	mock.Mock
 }
 
-// CreateCourse provides a mock function with given fields: ctx, courseName
-func (_m *CourseCreator) CreateCourse(ctx context.Context, courseName string) (models.Course, error) {
-	ret := _m.Called(ctx, courseName)
+// CreateCourse provides a mock function with given fields: ctx, course
+func (_m *CourseCreator) CreateCourse(ctx context.Context, course models.Course) (models.Course, error) {
+	ret := _m.Called(ctx, course)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateCourse")
@@ -25,17 +25,17 @@ func (_m *CourseCreator) CreateCourse(ctx context.Context, courseName string) (m
 
 	var r0 models.Course
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (models.Course, error)); ok {
-		return rf(ctx, courseName)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Course) (models.Course, error)); ok {
+		return rf(ctx, course)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) models.Course); ok {
-		r0 = rf(ctx, courseName)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Course) models.Course); ok {
+		r0 = rf(ctx, course)
 	} else {
 		r0 = ret.Get(0).(models.Course)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, courseName)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Course) error); ok {
+		r1 = rf(ctx, course)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -43,6 +43,34 @@ func (_m *CourseGetter) GetCourseByID(ctx context.Context, ID int) (models.Cours
 	return r0, r1
 }
 
+// GetCourseByCode provides a mock function with given fields: ctx, code
+func (_m *CourseGetter) GetCourseByCode(ctx context.Context, code string) (models.Course, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCourseByCode")
+	}
+
+	var r0 models.Course
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.Course, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.Course); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(models.Course)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewCourseGetter creates a new instance of CourseGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCourseGetter(t interface {
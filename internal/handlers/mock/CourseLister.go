@@ -15,9 +15,9 @@ type CourseLister struct {
 	mock.Mock
 }
 
-// ListCourses provides a mock function with given fields: ctx
-func (_m *CourseLister) ListCourses(ctx context.Context) ([]models.Course, error) {
-	ret := _m.Called(ctx)
+// ListCourses provides a mock function with given fields: ctx, filter
+func (_m *CourseLister) ListCourses(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	ret := _m.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ListCourses")
@@ -25,19 +25,19 @@ func (_m *CourseLister) ListCourses(ctx context.Context) ([]models.Course, error
 
 	var r0 []models.Course
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Course, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, models.CourseFilter) ([]models.Course, error)); ok {
+		return rf(ctx, filter)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []models.Course); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, models.CourseFilter) []models.Course); ok {
+		r0 = rf(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]models.Course)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, models.CourseFilter) error); ok {
+		r1 = rf(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -15,9 +15,9 @@ type CourseUpdater struct {
 	mock.Mock
 }
 
-// UpdateCourse provides a mock function with given fields: ctx, courseID, courseName
-func (_m *CourseUpdater) UpdateCourse(ctx context.Context, courseID int, courseName string) (models.Course, error) {
-	ret := _m.Called(ctx, courseID, courseName)
+// UpdateCourse provides a mock function with given fields: ctx, courseID, course
+func (_m *CourseUpdater) UpdateCourse(ctx context.Context, courseID int, course models.Course) (models.Course, error) {
+	ret := _m.Called(ctx, courseID, course)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateCourse")
@@ -25,17 +25,17 @@ func (_m *CourseUpdater) UpdateCourse(ctx context.Context, courseID int, courseN
 
 	var r0 models.Course
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) (models.Course, error)); ok {
-		return rf(ctx, courseID, courseName)
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.Course) (models.Course, error)); ok {
+		return rf(ctx, courseID, course)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int, string) models.Course); ok {
-		r0 = rf(ctx, courseID, courseName)
+	if rf, ok := ret.Get(0).(func(context.Context, int, models.Course) models.Course); ok {
+		r0 = rf(ctx, courseID, course)
 	} else {
 		r0 = ret.Get(0).(models.Course)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int, string) error); ok {
-		r1 = rf(ctx, courseID, courseName)
+	if rf, ok := ret.Get(1).(func(context.Context, int, models.Course) error); ok {
+		r1 = rf(ctx, courseID, course)
 	} else {
 		r1 = ret.Error(1)
 	}
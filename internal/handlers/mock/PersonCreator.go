@@ -15,9 +15,9 @@ type PersonCreator struct {
 	mock.Mock
 }
 
-// CreatePerson provides a mock function with given fields: ctx, newPerson
-func (_m *PersonCreator) CreatePerson(ctx context.Context, newPerson models.Person) (models.Person, error) {
-	ret := _m.Called(ctx, newPerson)
+// CreatePerson provides a mock function with given fields: ctx, newPerson, allowDuplicate
+func (_m *PersonCreator) CreatePerson(ctx context.Context, newPerson models.Person, allowDuplicate bool) (models.Person, error) {
+	ret := _m.Called(ctx, newPerson, allowDuplicate)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreatePerson")
@@ -25,17 +25,17 @@ func (_m *PersonCreator) CreatePerson(ctx context.Context, newPerson models.Pers
 
 	var r0 models.Person
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, models.Person) (models.Person, error)); ok {
-		return rf(ctx, newPerson)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Person, bool) (models.Person, error)); ok {
+		return rf(ctx, newPerson, allowDuplicate)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, models.Person) models.Person); ok {
-		r0 = rf(ctx, newPerson)
+	if rf, ok := ret.Get(0).(func(context.Context, models.Person, bool) models.Person); ok {
+		r0 = rf(ctx, newPerson, allowDuplicate)
 	} else {
 		r0 = ret.Get(0).(models.Person)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, models.Person) error); ok {
-		r1 = rf(ctx, newPerson)
+	if rf, ok := ret.Get(1).(func(context.Context, models.Person, bool) error); ok {
+		r1 = rf(ctx, newPerson, allowDuplicate)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -15,34 +15,22 @@ type PersonLister struct {
 	mock.Mock
 }
 
-// ListPersons provides a mock function with given fields: ctx
-func (_m *PersonLister) ListPersons(ctx context.Context) ([]models.Person, error) {
-	ret := _m.Called(ctx)
+// StreamPersons provides a mock function with given fields: ctx, fn
+func (_m *PersonLister) StreamPersons(ctx context.Context, fn func(models.Person) error) error {
+	ret := _m.Called(ctx, fn)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListPersons")
+		panic("no return value specified for StreamPersons")
 	}
 
-	var r0 []models.Person
-	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]models.Person, error)); ok {
-		return rf(ctx)
-	}
-	if rf, ok := ret.Get(0).(func(context.Context) []models.Person); ok {
-		r0 = rf(ctx)
-	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]models.Person)
-		}
-	}
-
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(models.Person) error) error); ok {
+		r0 = rf(ctx, fn)
 	} else {
-		r1 = ret.Error(1)
+		r0 = ret.Error(0)
 	}
 
-	return r0, r1
+	return r0
 }
 
 // NewPersonLister creates a new instance of PersonLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
@@ -5,36 +5,186 @@ import (
 	"fmt"
 	"go-api-tech-challenge/internal/models"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
 )
 
+// externalIDPattern is the form of a student or staff number.
+var externalIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// phonePattern is the form of a phone number: digits, optionally after a '+', grouped by spaces,
+// dashes, dots or parentheses. validPhone also counts the digits.
+var phonePattern = regexp.MustCompile(`^\+?[0-9(][0-9 ().-]*[0-9]$`)
+
+// minBirthYear is the earliest year a date of birth may be in.
+const minBirthYear = 1900
+
+// courseCodePattern is the form of a course code, such as CS101: a subject of 2 to 4 letters, a
+// 3 digit number and an optional letter for a variant. Codes are matched in upper case.
+var courseCodePattern = regexp.MustCompile(`^[A-Z]{2,4}[0-9]{3}[A-Z]?$`)
+
+// The credits a course may be worth, and the longest description it may have.
+const (
+	minCourseCredits        = 1
+	maxCourseCredits        = 12
+	maxCourseDescriptionLen = 2000
+)
+
+// maxDepartmentNameLen is the longest name a department may have.
+const maxDepartmentNameLen = 100
+
+// maxTermNameLen is the longest name a term may have.
+const maxTermNameLen = 100
+
+// validCourseLevels are the levels a course can be taught at.
+var validCourseLevels = map[string]bool{
+	models.CourseLevelUndergraduate: true,
+	models.CourseLevelGraduate:      true,
+}
+
+// validEmail reports whether address is a bare email address, without a display name, of at most
+// 254 characters.
+func validEmail(address string) bool {
+	if len(address) > 254 {
+		return false
+	}
+	parsed, err := mail.ParseAddress(address)
+	return err == nil && parsed.Address == address && strings.Contains(address[strings.LastIndex(address, "@"):], ".")
+}
+
+// validPhone reports whether number looks like a phone number with 7 to 15 digits, the range E.164
+// allows.
+func validPhone(number string) bool {
+	if !phonePattern.MatchString(number) {
+		return false
+	}
+	digits := 0
+	for _, r := range number {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+// ageOn returns how many whole years old someone born on dateOfBirth is on the date of now.
+func ageOn(dateOfBirth time.Time, now time.Time) int {
+	age := now.Year() - dateOfBirth.Year()
+	if now.Month() < dateOfBirth.Month() || (now.Month() == dateOfBirth.Month() && now.Day() < dateOfBirth.Day()) {
+		age--
+	}
+	return age
+}
+
+// inputCourse is a course as sent to be created or updated. The code may be sent in any case and
+// is stored in upper case. Level defaults to undergraduate and Active to true.
 type inputCourse struct {
-	Name string `json:"name"`
+	Code        string `json:"code" example:"CS101"`
+	Name        string `json:"name" example:"Programming"`
+	Credits     int    `json:"credits" example:"4"`
+	Description string `json:"description,omitempty" example:"Writing, testing and debugging programs."`
+	Level       string `json:"level,omitempty" example:"undergraduate" enums:"undergraduate,graduate"`
+	Active      *bool  `json:"active,omitempty"`
+	// DepartmentID is the department teaching the course, if any.
+	DepartmentID *int `json:"department_id,omitempty" example:"1"`
+	// Capacity is how many students each section of the course holds; without one, there is no limit.
+	Capacity *int `json:"capacity,omitempty" example:"30"`
 }
 
+// inputPerson is a person as sent to be created or updated. When DateOfBirth is given, Age is
+// derived from it. Only professors may be given a department.
 type inputPerson struct {
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Type      string `json:"type"`
-	Age       int    `json:"age"`
-	Courses   []int  `json:"courses,omitempty"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Type         string `json:"type"`
+	Age          int    `json:"age"`
+	ExternalID   string `json:"external_id,omitempty" example:"S-000123"`
+	Email        string `json:"email,omitempty" example:"ada@example.com"`
+	Phone        string `json:"phone,omitempty" example:"+44 20 7946 0000"`
+	DateOfBirth  string `json:"date_of_birth,omitempty" example:"2001-12-10"`
+	DepartmentID *int   `json:"department_id,omitempty" example:"1"`
+	// Sections are the course sections to enroll the person in.
+	Sections []int `json:"sections,omitempty"`
+}
+
+// inputDepartment is a department as sent to be created or renamed.
+type inputDepartment struct {
+	Name string `json:"name" example:"Computer Science"`
+}
+
+// inputTerm is a term as sent to be created or updated.
+type inputTerm struct {
+	Name     string `json:"name" example:"Fall 2026"`
+	StartsOn string `json:"starts_on" example:"2026-09-01"`
+	EndsOn   string `json:"ends_on" example:"2026-12-18"`
 }
 
-// MapTo maps a inputUser to a models.User object.
+// inputSection is a section as sent to be added to the course in the path.
+type inputSection struct {
+	TermID int `json:"term_id" example:"1"`
+	Number int `json:"number" example:"1"`
+	// InstructorID is the professor teaching the section, if one has been assigned.
+	InstructorID *int `json:"instructor_id,omitempty" example:"2"`
+}
+
+// inputMerge names the person to merge into the one in the path.
+type inputMerge struct {
+	DuplicateID int `json:"duplicate_id"`
+}
+
+// inputBatchOperation is one request of a batch, such as a POST to /api/person.
+type inputBatchOperation struct {
+	Method string          `json:"method" example:"POST"`
+	Path   string          `json:"path" example:"/api/person"`
+	Body   json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+// MapTo maps a inputCourse to a models.Course object, filling in the defaults.
 func (course inputCourse) MapTo() (models.Course, error) {
-	return models.Course{
-		ID:   0,
-		Name: course.Name,
-	}, nil
+	mapped := models.Course{
+		ID:           0,
+		Code:         strings.ToUpper(course.Code),
+		Name:         course.Name,
+		Credits:      course.Credits,
+		Description:  course.Description,
+		Level:        course.Level,
+		Active:       true,
+		DepartmentID: course.DepartmentID,
+		Capacity:     course.Capacity,
+	}
+	if mapped.Level == "" {
+		mapped.Level = models.CourseLevelUndergraduate
+	}
+	if course.Active != nil {
+		mapped.Active = *course.Active
+	}
+	return mapped, nil
 }
 func (person inputPerson) MapTo() (models.Person, error) {
-	return models.Person{
-		ID:        0,
-		FirstName: person.FirstName,
-		LastName:  person.LastName,
-		Type:      person.Type,
-		Age:       person.Age,
-		Courses:   person.Courses,
-	}, nil
+	mapped := models.Person{
+		ID:           0,
+		FirstName:    person.FirstName,
+		LastName:     person.LastName,
+		Type:         person.Type,
+		Age:          person.Age,
+		ExternalID:   person.ExternalID,
+		Email:        person.Email,
+		Phone:        person.Phone,
+		DepartmentID: person.DepartmentID,
+		Sections:     person.Sections,
+	}
+	if person.DateOfBirth != "" {
+		dateOfBirth, err := time.Parse(time.DateOnly, person.DateOfBirth)
+		if err != nil {
+			return models.Person{}, fmt.Errorf("[in inputPerson.MapTo] invalid date_of_birth: %w", err)
+		}
+		mapped.DateOfBirth = &dateOfBirth
+		mapped.Age = ageOn(dateOfBirth, time.Now())
+	}
+	return mapped, nil
 }
 
 func (person inputPerson) Valid() []problem {
@@ -70,12 +220,64 @@ func (person inputPerson) Valid() []problem {
 			Description: "must not be negative",
 		})
 	}
-	if len(person.Courses) > 0 {
-		for i, id := range person.Courses {
+	if person.ExternalID != "" && !externalIDPattern.MatchString(person.ExternalID) {
+		problems = append(problems, problem{
+			Name:        "external_id",
+			Description: "must be 1 to 64 letters, digits, '-' or '_'",
+		})
+	}
+	if person.Email != "" && !validEmail(person.Email) {
+		problems = append(problems, problem{
+			Name:        "email",
+			Description: "must be an email address such as name@example.com",
+		})
+	}
+	if person.Phone != "" && !validPhone(person.Phone) {
+		problems = append(problems, problem{
+			Name:        "phone",
+			Description: "must be 7 to 15 digits, optionally starting with '+' and grouped by spaces, '-', '.' or parentheses",
+		})
+	}
+	if person.DateOfBirth != "" {
+		dateOfBirth, err := time.Parse(time.DateOnly, person.DateOfBirth)
+		switch {
+		case err != nil:
+			problems = append(problems, problem{
+				Name:        "date_of_birth",
+				Description: "must be a date in the form YYYY-MM-DD",
+			})
+		case dateOfBirth.After(time.Now()) || dateOfBirth.Year() < minBirthYear:
+			problems = append(problems, problem{
+				Name:        "date_of_birth",
+				Description: fmt.Sprintf("must be between %d-01-01 and today", minBirthYear),
+			})
+		case person.Age != 0 && person.Age != ageOn(dateOfBirth, time.Now()):
+			problems = append(problems, problem{
+				Name:        "age",
+				Description: "must match date_of_birth, or be left out to derive it",
+			})
+		}
+	}
+	if person.DepartmentID != nil {
+		switch {
+		case *person.DepartmentID <= 0:
+			problems = append(problems, problem{
+				Name:        "department_id",
+				Description: "must be a positive integer",
+			})
+		case person.Type != "professor":
+			problems = append(problems, problem{
+				Name:        "department_id",
+				Description: "must only be given for professors",
+			})
+		}
+	}
+	if len(person.Sections) > 0 {
+		for i, id := range person.Sections {
 			if id <= 0 {
 				problems = append(problems, problem{
-					Name:        fmt.Sprintf("courses[%d]", i),
-					Description: "course ID must be a positive integer",
+					Name:        fmt.Sprintf("sections[%d]", i),
+					Description: "section ID must be a positive integer",
 				})
 			}
 		}
@@ -88,6 +290,12 @@ func (person inputPerson) Valid() []problem {
 func (course inputCourse) Valid() []problem {
 	var problems []problem
 
+	if !courseCodePattern.MatchString(strings.ToUpper(course.Code)) {
+		problems = append(problems, problem{
+			Name:        "code",
+			Description: "must be 2 to 4 letters and 3 digits, optionally followed by a letter, such as CS101",
+		})
+	}
 	// validate FirstName is not blank
 	if course.Name == "" {
 		problems = append(problems, problem{
@@ -95,6 +303,192 @@ func (course inputCourse) Valid() []problem {
 			Description: "must not be blank",
 		})
 	}
+	if course.Credits < minCourseCredits || course.Credits > maxCourseCredits {
+		problems = append(problems, problem{
+			Name:        "credits",
+			Description: fmt.Sprintf("must be between %d and %d", minCourseCredits, maxCourseCredits),
+		})
+	}
+	if len([]rune(course.Description)) > maxCourseDescriptionLen {
+		problems = append(problems, problem{
+			Name:        "description",
+			Description: fmt.Sprintf("must not be longer than %d characters", maxCourseDescriptionLen),
+		})
+	}
+	if course.Level != "" && !validCourseLevels[course.Level] {
+		problems = append(problems, problem{
+			Name:        "level",
+			Description: "must be either 'undergraduate' or 'graduate'",
+		})
+	}
+	if course.DepartmentID != nil && *course.DepartmentID <= 0 {
+		problems = append(problems, problem{
+			Name:        "department_id",
+			Description: "must be a positive integer",
+		})
+	}
+	if course.Capacity != nil && *course.Capacity <= 0 {
+		problems = append(problems, problem{
+			Name:        "capacity",
+			Description: "must be a positive integer",
+		})
+	}
+
+	return problems
+}
+
+// MapTo maps an inputDepartment to a models.Department.
+func (department inputDepartment) MapTo() (models.Department, error) {
+	return models.Department{Name: strings.TrimSpace(department.Name)}, nil
+}
+
+// Valid checks that the department has a name.
+func (department inputDepartment) Valid() []problem {
+	var problems []problem
+
+	switch name := strings.TrimSpace(department.Name); {
+	case name == "":
+		problems = append(problems, problem{
+			Name:        "name",
+			Description: "must not be blank",
+		})
+	case len([]rune(name)) > maxDepartmentNameLen:
+		problems = append(problems, problem{
+			Name:        "name",
+			Description: fmt.Sprintf("must not be longer than %d characters", maxDepartmentNameLen),
+		})
+	}
+
+	return problems
+}
+
+// MapTo maps an inputTerm to a models.Term.
+func (term inputTerm) MapTo() (models.Term, error) {
+	startsOn, err := time.Parse(time.DateOnly, term.StartsOn)
+	if err != nil {
+		return models.Term{}, fmt.Errorf("[in inputTerm.MapTo] invalid starts_on: %w", err)
+	}
+	endsOn, err := time.Parse(time.DateOnly, term.EndsOn)
+	if err != nil {
+		return models.Term{}, fmt.Errorf("[in inputTerm.MapTo] invalid ends_on: %w", err)
+	}
+	return models.Term{Name: strings.TrimSpace(term.Name), StartsOn: startsOn, EndsOn: endsOn}, nil
+}
+
+// Valid checks that the term has a name and ends after it starts.
+func (term inputTerm) Valid() []problem {
+	var problems []problem
+
+	switch name := strings.TrimSpace(term.Name); {
+	case name == "":
+		problems = append(problems, problem{
+			Name:        "name",
+			Description: "must not be blank",
+		})
+	case len([]rune(name)) > maxTermNameLen:
+		problems = append(problems, problem{
+			Name:        "name",
+			Description: fmt.Sprintf("must not be longer than %d characters", maxTermNameLen),
+		})
+	}
+	startsOn, startsErr := time.Parse(time.DateOnly, term.StartsOn)
+	if startsErr != nil {
+		problems = append(problems, problem{
+			Name:        "starts_on",
+			Description: "must be a date in the form YYYY-MM-DD",
+		})
+	}
+	endsOn, endsErr := time.Parse(time.DateOnly, term.EndsOn)
+	switch {
+	case endsErr != nil:
+		problems = append(problems, problem{
+			Name:        "ends_on",
+			Description: "must be a date in the form YYYY-MM-DD",
+		})
+	case startsErr == nil && !endsOn.After(startsOn):
+		problems = append(problems, problem{
+			Name:        "ends_on",
+			Description: "must be after starts_on",
+		})
+	}
+
+	return problems
+}
+
+// MapTo maps an inputSection to a models.Section, without its course.
+func (section inputSection) MapTo() (models.Section, error) {
+	return models.Section{TermID: section.TermID, Number: section.Number, InstructorID: section.InstructorID}, nil
+}
+
+// Valid checks that the section is in a term and numbered.
+func (section inputSection) Valid() []problem {
+	var problems []problem
+
+	if section.TermID <= 0 {
+		problems = append(problems, problem{
+			Name:        "term_id",
+			Description: "must be a positive integer",
+		})
+	}
+	if section.Number <= 0 {
+		problems = append(problems, problem{
+			Name:        "number",
+			Description: "must be a positive integer",
+		})
+	}
+	if section.InstructorID != nil && *section.InstructorID <= 0 {
+		problems = append(problems, problem{
+			Name:        "instructor_id",
+			Description: "must be a positive integer",
+		})
+	}
+
+	return problems
+}
+
+// Valid checks that the merge names a person other than the survivor.
+func (merge inputMerge) Valid(survivorID int) []problem {
+	var problems []problem
+
+	switch {
+	case merge.DuplicateID <= 0:
+		problems = append(problems, problem{
+			Name:        "duplicate_id",
+			Description: "must be a positive integer",
+		})
+	case merge.DuplicateID == survivorID:
+		problems = append(problems, problem{
+			Name:        "duplicate_id",
+			Description: "must not be the person being merged into",
+		})
+	}
+
+	return problems
+}
+
+// Valid checks that the operation is a request to the API that a batch can run.
+func (operation inputBatchOperation) Valid() []problem {
+	var problems []problem
+
+	if !batchMethods[strings.ToUpper(operation.Method)] {
+		problems = append(problems, problem{
+			Name:        "method",
+			Description: "must be one of GET, POST, PUT, PATCH or DELETE",
+		})
+	}
+	target, err := url.Parse(operation.Path)
+	switch {
+	case err != nil || target.Scheme != "" || target.Host != "" || !strings.HasPrefix(target.Path, "/api/"):
+		problems = append(problems, problem{
+			Name:        "path",
+			Description: "must be an API path starting with /api/",
+		})
+	case target.Path == batchPath || strings.HasPrefix(target.Path, batchPath+"/"):
+		problems = append(problems, problem{
+			Name:        "path",
+			Description: "must not be a batch",
+		})
+	}
 
 	return problems
 }
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidEmail(t *testing.T) {
+	tests := map[string]struct {
+		address  string
+		expected bool
+	}{
+		"plain":             {address: "ada@example.com", expected: true},
+		"subdomain":         {address: "ada.lovelace@mail.example.co.uk", expected: true},
+		"no at sign":        {address: "ada.example.com", expected: false},
+		"no domain dot":     {address: "ada@localhost", expected: false},
+		"display name":      {address: "Ada <ada@example.com>", expected: false},
+		"surrounding space": {address: " ada@example.com", expected: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, validEmail(tc.address))
+		})
+	}
+}
+
+func TestValidPhone(t *testing.T) {
+	tests := map[string]struct {
+		number   string
+		expected bool
+	}{
+		"international": {number: "+44 20 7946 0000", expected: true},
+		"grouped":       {number: "(555) 010-0000", expected: true},
+		"dotted":        {number: "555.010.0000", expected: true},
+		"too short":     {number: "555 01", expected: false},
+		"too long":      {number: "+1 555 010 0000 0000 0", expected: false},
+		"letters":       {number: "555-CALL-NOW", expected: false},
+		"plus inside":   {number: "555+0100000", expected: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, validPhone(tc.number))
+		})
+	}
+}
+
+func TestAgeOn(t *testing.T) {
+	dateOfBirth := time.Date(2001, time.December, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		now      time.Time
+		expected int
+	}{
+		"before the birthday":  {now: time.Date(2026, time.December, 9, 0, 0, 0, 0, time.UTC), expected: 24},
+		"on the birthday":      {now: time.Date(2026, time.December, 10, 0, 0, 0, 0, time.UTC), expected: 25},
+		"earlier in the month": {now: time.Date(2026, time.November, 30, 0, 0, 0, 0, time.UTC), expected: 24},
+		"day of birth":         {now: dateOfBirth, expected: 0},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, ageOn(dateOfBirth, tc.now))
+		})
+	}
+}
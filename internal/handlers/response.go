@@ -1,32 +1,149 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
+	"strconv"
+	"time"
 
-	"github.com/go-chi/httplog/v2"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("go-api-tech-challenge/internal/handlers")
+
 type outputCourse struct {
+	ID           int    `json:"id"`
+	Code         string `json:"code" example:"CS101"`
+	Name         string `json:"name"`
+	Credits      int    `json:"credits"`
+	Description  string `json:"description,omitempty"`
+	Level        string `json:"level" example:"undergraduate"`
+	Active       bool   `json:"active"`
+	DepartmentID *int   `json:"department_id,omitempty" example:"1"`
+	Capacity     *int   `json:"capacity,omitempty" example:"30"`
+}
+
+type outputDepartment struct {
 	ID   int    `json:"id"`
-	Name string `json:"name"`
+	Name string `json:"name" example:"Computer Science"`
+}
+
+type outputTerm struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name" example:"Fall 2026"`
+	StartsOn string `json:"starts_on" example:"2026-09-01"`
+	EndsOn   string `json:"ends_on" example:"2026-12-18"`
+}
+
+type outputSection struct {
+	ID           int  `json:"id"`
+	CourseID     int  `json:"course_id"`
+	TermID       int  `json:"term_id"`
+	Number       int  `json:"number" example:"1"`
+	InstructorID *int `json:"instructor_id,omitempty" example:"2"`
+}
+
+// outputEnrollment is a section a person is enrolled in, named by its course and term.
+type outputEnrollment struct {
+	SectionID     int    `json:"section_id"`
+	SectionNumber int    `json:"section_number" example:"1"`
+	CourseID      int    `json:"course_id"`
+	CourseCode    string `json:"course_code" example:"CS101"`
+	CourseName    string `json:"course_name" example:"Programming"`
+	TermID        int    `json:"term_id"`
+	TermName      string `json:"term_name" example:"Fall 2026"`
+	InstructorID  *int   `json:"instructor_id,omitempty" example:"2"`
 }
 
 type outputPerson struct {
-	ID        int    `json:"id"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Type      string `json:"type"`
-	Age       int    `json:"age"`
-	Courses   []int  `json:"courses,omitempty"`
+	ID           int    `json:"id"`
+	FirstName    string `json:"first_name"`
+	LastName     string `json:"last_name"`
+	Type         string `json:"type"`
+	Age          int    `json:"age"`
+	ExternalID   string `json:"external_id,omitempty"`
+	Email        string `json:"email,omitempty"`
+	Phone        string `json:"phone,omitempty"`
+	DateOfBirth  string `json:"date_of_birth,omitempty" example:"2001-12-10"`
+	DepartmentID *int   `json:"department_id,omitempty" example:"1"`
+	Sections     []int  `json:"sections,omitempty"`
+	Courses      []int  `json:"courses,omitempty"`
+	// Waitlisted are the sections the person is waiting for a seat in, in the order they joined.
+	Waitlisted []int     `json:"waitlisted,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// personCSVColumns lays out persons in a CSV export, with a person's course and section IDs each in
+// one cell separated by semicolons.
+var personCSVColumns = csvColumns[outputPerson]{
+	header: []string{"id", "first_name", "last_name", "type", "age", "courses", "external_id", "email", "phone",
+		"date_of_birth", "created_at", "updated_at", "department_id", "sections"},
+	row: func(person outputPerson) []string {
+		return []string{
+			strconv.Itoa(person.ID),
+			csvText(person.FirstName),
+			csvText(person.LastName),
+			csvText(person.Type),
+			strconv.Itoa(person.Age),
+			csvInts(person.Courses),
+			csvText(person.ExternalID),
+			csvText(person.Email),
+			csvText(person.Phone),
+			person.DateOfBirth,
+			person.CreatedAt.Format(time.RFC3339),
+			person.UpdatedAt.Format(time.RFC3339),
+			csvOptionalInt(person.DepartmentID),
+			csvInts(person.Sections),
+		}
+	},
+}
+
+// courseCSVColumns lays out courses in a CSV export.
+var courseCSVColumns = csvColumns[outputCourse]{
+	header: []string{"id", "name", "code", "credits", "description", "level", "active", "department_id", "capacity"},
+	row: func(course outputCourse) []string {
+		return []string{
+			strconv.Itoa(course.ID),
+			csvText(course.Name),
+			course.Code,
+			strconv.Itoa(course.Credits),
+			csvText(course.Description),
+			course.Level,
+			strconv.FormatBool(course.Active),
+			csvOptionalInt(course.DepartmentID),
+			csvOptionalInt(course.Capacity),
+		}
+	},
+}
+
+type outputComponent struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
 }
 
 // mapOutput maps a models.Course struct to an outputCourse struct.
 func mapOutputCourse(course models.Course) outputCourse {
 	return outputCourse{
-		ID:   course.ID,
-		Name: course.Name,
+		ID:           course.ID,
+		Code:         course.Code,
+		Name:         course.Name,
+		Credits:      course.Credits,
+		Description:  course.Description,
+		Level:        course.Level,
+		Active:       course.Active,
+		DepartmentID: course.DepartmentID,
+		Capacity:     course.Capacity,
 	}
 }
 
@@ -47,16 +164,118 @@ func mapOutputPerson(person models.Person) outputPerson {
 		intCourseIDs[i] = int(id) // Convert each int64 to int
 	}
 
-	return outputPerson{
-		ID:        person.ID,
-		FirstName: person.FirstName,
-		LastName:  person.LastName,
-		Type:      person.Type,
-		Age:       person.Age,
-		Courses:   intCourseIDs,
+	personOut := outputPerson{
+		ID:           person.ID,
+		FirstName:    person.FirstName,
+		LastName:     person.LastName,
+		Type:         person.Type,
+		Age:          person.Age,
+		ExternalID:   person.ExternalID,
+		Email:        person.Email,
+		Phone:        person.Phone,
+		DepartmentID: person.DepartmentID,
+		Sections:     person.Sections,
+		Courses:      intCourseIDs,
+		Waitlisted:   person.Waitlisted,
+		CreatedAt:    person.CreatedAt,
+		UpdatedAt:    person.UpdatedAt,
+	}
+	if person.DateOfBirth != nil {
+		personOut.DateOfBirth = person.DateOfBirth.Format(time.DateOnly)
+	}
+	return personOut
+}
+
+// mapOutputDepartment maps a models.Department struct to an outputDepartment struct.
+func mapOutputDepartment(department models.Department) outputDepartment {
+	return outputDepartment{
+		ID:   department.ID,
+		Name: department.Name,
+	}
+}
+
+// mapMultipleOutputDepartment maps a slice of []models.Department to a slice of
+// []outputDepartment.
+func mapMultipleOutputDepartment(departments []models.Department) []outputDepartment {
+	departmentsOut := make([]outputDepartment, len(departments))
+	for i, department := range departments {
+		departmentsOut[i] = mapOutputDepartment(department)
+	}
+	return departmentsOut
+}
+
+// mapOutputTerm maps a models.Term struct to an outputTerm struct.
+func mapOutputTerm(term models.Term) outputTerm {
+	return outputTerm{
+		ID:       term.ID,
+		Name:     term.Name,
+		StartsOn: term.StartsOn.Format(time.DateOnly),
+		EndsOn:   term.EndsOn.Format(time.DateOnly),
 	}
 }
 
+// mapMultipleOutputTerm maps a slice of []models.Term to a slice of []outputTerm.
+func mapMultipleOutputTerm(terms []models.Term) []outputTerm {
+	termsOut := make([]outputTerm, len(terms))
+	for i, term := range terms {
+		termsOut[i] = mapOutputTerm(term)
+	}
+	return termsOut
+}
+
+// mapOutputSection maps a models.Section struct to an outputSection struct.
+func mapOutputSection(section models.Section) outputSection {
+	return outputSection{
+		ID:           section.ID,
+		CourseID:     section.CourseID,
+		TermID:       section.TermID,
+		Number:       section.Number,
+		InstructorID: section.InstructorID,
+	}
+}
+
+// mapMultipleOutputSection maps a slice of []models.Section to a slice of []outputSection.
+func mapMultipleOutputSection(sections []models.Section) []outputSection {
+	sectionsOut := make([]outputSection, len(sections))
+	for i, section := range sections {
+		sectionsOut[i] = mapOutputSection(section)
+	}
+	return sectionsOut
+}
+
+// mapMultipleOutputEnrollment maps a slice of []models.Enrollment to a slice of
+// []outputEnrollment.
+func mapMultipleOutputEnrollment(enrollments []models.Enrollment) []outputEnrollment {
+	enrollmentsOut := make([]outputEnrollment, len(enrollments))
+	for i, enrollment := range enrollments {
+		enrollmentsOut[i] = outputEnrollment{
+			SectionID:     enrollment.Section.ID,
+			SectionNumber: enrollment.Section.Number,
+			CourseID:      enrollment.Section.CourseID,
+			CourseCode:    enrollment.CourseCode,
+			CourseName:    enrollment.CourseName,
+			TermID:        enrollment.Section.TermID,
+			TermName:      enrollment.TermName,
+			InstructorID:  enrollment.Section.InstructorID,
+		}
+	}
+	return enrollmentsOut
+}
+
+func mapOutputSearchResults(results []models.SearchResult) []outputSearchResult {
+	resultsOut := make([]outputSearchResult, len(results))
+	for i, result := range results {
+		resultsOut[i] = outputSearchResult{
+			Type:      result.Type,
+			ID:        result.ID,
+			Title:     result.Title,
+			Highlight: result.Highlight,
+			Rank:      result.Rank,
+		}
+	}
+	return resultsOut
+}
+
 func mapMultipleOutputPerson(person []models.Person) []outputPerson {
 	personsOut := make([]outputPerson, len(person))
 	for i := 0; i < len(person); i++ {
@@ -68,6 +287,60 @@ func mapMultipleOutputPerson(person []models.Person) []outputPerson {
 
 }
 
+// mapOutputReadiness maps a models.Readiness struct to a responseReadiness struct.
+func mapOutputReadiness(readiness models.Readiness) responseReadiness {
+	status := "ready"
+	if !readiness.Ready {
+		status = "not ready"
+	}
+
+	components := make([]outputComponent, len(readiness.Components))
+	for i, component := range readiness.Components {
+		components[i] = outputComponent{
+			Name:      component.Name,
+			Status:    component.Status,
+			LatencyMS: float64(component.Latency.Microseconds()) / 1000,
+			Error:     component.Error,
+		}
+	}
+
+	return responseReadiness{
+		Status:     status,
+		Components: components,
+	}
+}
+
+// mapOutputPoolStats maps a models.PoolStats struct to a responsePoolStats struct.
+func mapOutputPoolStats(stats models.PoolStats) responsePoolStats {
+	return responsePoolStats{
+		TotalConns:              stats.TotalConns,
+		AcquiredConns:           stats.AcquiredConns,
+		IdleConns:               stats.IdleConns,
+		ConstructingConns:       stats.ConstructingConns,
+		MaxConns:                stats.MaxConns,
+		AcquireCount:            stats.AcquireCount,
+		AcquireDurationMS:       float64(stats.AcquireDuration.Microseconds()) / 1000,
+		EmptyAcquireCount:       stats.EmptyAcquireCount,
+		CanceledAcquireCount:    stats.CanceledAcquireCount,
+		NewConnsCount:           stats.NewConnsCount,
+		MaxLifetimeDestroyCount: stats.MaxLifetimeDestroyCount,
+		MaxIdleDestroyCount:     stats.MaxIdleDestroyCount,
+	}
+}
+
+// mapOutputCacheStats maps a models.CacheStats struct to a responseCacheStats struct.
+func mapOutputCacheStats(stats models.CacheStats) responseCacheStats {
+	return responseCacheStats{
+		Hits:          stats.Hits,
+		Misses:        stats.Misses,
+		HitRatio:      stats.HitRatio,
+		Errors:        stats.Errors,
+		Invalidations: stats.Invalidations,
+		Entries:       stats.Entries,
+		Evictions:     stats.Evictions,
+	}
+}
+
 type responseCourse struct {
 	Course outputCourse `json:"course"`
 }
@@ -76,6 +349,34 @@ type responseCourses struct {
 	Courses []outputCourse `json:"courses"`
 }
 
+type responseDepartment struct {
+	Department outputDepartment `json:"department"`
+}
+
+type responseDepartments struct {
+	Departments []outputDepartment `json:"departments"`
+}
+
+type responseTerm struct {
+	Term outputTerm `json:"term"`
+}
+
+type responseTerms struct {
+	Terms []outputTerm `json:"terms"`
+}
+
+type responseSection struct {
+	Section outputSection `json:"section"`
+}
+
+type responseSections struct {
+	Sections []outputSection `json:"sections"`
+}
+
+type responseEnrollments struct {
+	Enrollments []outputEnrollment `json:"enrollments"`
+}
+
 type responseMsg struct {
 	Message string `json:"message"`
 }
@@ -84,25 +385,142 @@ type responsePerson struct {
 	Person outputPerson `json:"person"`
 }
 
+// responseDuplicates is the response to creating a person who may already exist, listing the
+// existing persons they look like.
+type responseDuplicates struct {
+	Error      string         `json:"error"`
+	Candidates []outputPerson `json:"candidates"`
+}
+
+// outputSearchResult is one person or course found by a search. Highlight is the title with the
+// matching parts wrapped in <mark> tags; the title itself is not escaped.
+type outputSearchResult struct {
+	Type      string  `json:"type" enums:"person,course"`
+	ID        int     `json:"id"`
+	Title     string  `json:"title"`
+	Highlight string  `json:"highlight" example:"<mark>Bill</mark> Gates"`
+	Rank      float64 `json:"rank"`
+}
+
+// responseSearch lists the results of a search, best match first.
+type responseSearch struct {
+	Query   string               `json:"query"`
+	Results []outputSearchResult `json:"results"`
+}
+
 type responsePersons struct {
 	Persons []outputPerson `json:"persons"`
 }
 
+// outputRowError lists the problems found with one row of an import, by the line it starts on.
+type outputRowError struct {
+	Line     int       `json:"line"`
+	Problems []problem `json:"problems"`
+}
+
+type responseImport struct {
+	DryRun   bool             `json:"dry_run"`
+	Rows     int              `json:"rows"`
+	Imported int              `json:"imported"`
+	Persons  []outputPerson   `json:"persons,omitempty"`
+	Errors   []outputRowError `json:"errors,omitempty"`
+}
+
+// outputBatchResult is the response to one operation of a batch. Body holds the JSON the
+// operation responded with.
+type outputBatchResult struct {
+	Status int             `json:"status" example:"201"`
+	Body   json.RawMessage `json:"body,omitempty" swaggertype:"object"`
+}
+
+type responseBatch struct {
+	Mode       string              `json:"mode" example:"atomic"`
+	RolledBack bool                `json:"rolled_back"`
+	Results    []outputBatchResult `json:"results"`
+}
+
+type responseReadiness struct {
+	Status     string            `json:"status"`
+	Components []outputComponent `json:"components"`
+}
+
+type responsePoolStats struct {
+	TotalConns              int32   `json:"total_conns"`
+	AcquiredConns           int32   `json:"acquired_conns"`
+	IdleConns               int32   `json:"idle_conns"`
+	ConstructingConns       int32   `json:"constructing_conns"`
+	MaxConns                int32   `json:"max_conns"`
+	AcquireCount            int64   `json:"acquire_count"`
+	AcquireDurationMS       float64 `json:"acquire_duration_ms"`
+	EmptyAcquireCount       int64   `json:"empty_acquire_count"`
+	CanceledAcquireCount    int64   `json:"canceled_acquire_count"`
+	NewConnsCount           int64   `json:"new_conns_count"`
+	MaxLifetimeDestroyCount int64   `json:"max_lifetime_destroy_count"`
+	MaxIdleDestroyCount     int64   `json:"max_idle_destroy_count"`
+}
+
+type responseCacheStats struct {
+	Hits          uint64  `json:"hits"`
+	Misses        uint64  `json:"misses"`
+	HitRatio      float64 `json:"hit_ratio"`
+	Errors        uint64  `json:"errors"`
+	Invalidations uint64  `json:"invalidations"`
+	Entries       int     `json:"entries"`
+	Evictions     uint64  `json:"evictions"`
+}
+
 //type responseID struct {
 //ObjectID int `json:"object_id"`
 //}
 
+// personConflict returns the message for an error saying a person's unique details are already
+// used by another person, and whether err is such an error.
+func personConflict(err error) (string, bool) {
+	switch {
+	case errors.Is(err, models.ErrExternalIDTaken):
+		return "external_id is already in use", true
+	case errors.Is(err, models.ErrEmailTaken):
+		return "email is already in use", true
+	}
+	return "", false
+}
+
+// missingDepartment is the response to putting a course or person in a department that does not
+// exist.
+var missingDepartment = responseErr{
+	ValidationErrors: []problem{{Name: "department_id", Description: "department does not exist"}},
+}
+
+// missingSection is the response to enrolling a person in a section that does not exist.
+var missingSection = responseErr{
+	ValidationErrors: []problem{{Name: "sections", Description: "section does not exist"}},
+}
+
 type responseErr struct {
 	Error            string    `json:"error,omitempty"`
 	ValidationErrors []problem `json:"validation_errors,omitempty"`
+	RequestID        string    `json:"request_id,omitempty"`
 }
 
-// encodeResponse encodes data as a JSON response.
-func encodeResponse(w http.ResponseWriter, logger *httplog.Logger, status int, data any) {
+// encodeResponse encodes data as a JSON response. Encoding runs in its own span so that slow
+// serialization of large bodies can be told apart from the database work that produced them. Error
+// responses are tagged with the request ID so clients can quote it when reporting a problem.
+func encodeResponse(ctx context.Context, w http.ResponseWriter, status int, data any) {
+	_, span := tracer.Start(ctx, "encodeResponse", trace.WithAttributes(
+		attribute.Int("http.response.status_code", status),
+	))
+	defer span.End()
+
+	if errResp, ok := data.(responseErr); ok {
+		errResp.RequestID = middleware.GetReqID(ctx)
+		data = errResp
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		logger.Error("Error while marshaling data", "err", err, "data", data)
+		tracing.RecordError(span, err)
+		logging.FromContext(ctx).Error("Error while marshaling data", "err", err, "data", data)
 		http.Error(w, `{"Error": "Internal server error"}`, http.StatusInternalServerError)
 	}
 }
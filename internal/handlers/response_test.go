@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeResponse(t *testing.T) {
+	ctx := context.WithValue(context.Background(), middleware.RequestIDKey, "req-1")
+
+	tests := map[string]struct {
+		data         any
+		expectedBody string
+	}{
+		"error response carries request ID": {
+			data:         responseErr{Error: "Error retrieving data"},
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data", RequestID: "req-1"}),
+		},
+		"success response is unchanged": {
+			data:         responseMsg{Message: "ok"},
+			expectedBody: testutil.ToJSONString(responseMsg{Message: "ok"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			encodeResponse(ctx, rr, http.StatusOK, tc.data)
+
+			assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Limits on a search request.
+const (
+	searchDefaultLimit   = 20
+	searchMaxLimit       = 100
+	searchMaxQueryLength = 200
+)
+
+type Searcher interface {
+	Search(ctx context.Context, text string, limit int) ([]models.SearchResult, error)
+}
+
+// HandleSearch is a Handler that searches persons and courses by name.
+//
+//	@Summary		Search persons and courses
+//	@Description	Finds persons by full name and courses by name, matching partial words and, on Postgres, misspellings. Matches in each title are highlighted with <mark> tags
+//	@Tags			search
+//	@Accept			json
+//	@Produce		json
+//	@Param			q			query		string	true	"words to search for"
+//	@Param			limit		query		int		false	"maximum number of results, 20 by default"	minimum(1)	maximum(100)
+//	@Success		200			{object}	handlers.responseSearch
+//	@Failure		400			{object}	handlers.responseErr
+//	@Failure		500			{object}	handlers.responseErr
+//	@Router			/api/search	[GET]
+func HandleSearch(service Searcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// setup
+		ctx, span := tracer.Start(r.Context(), "HandleSearch")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+
+		text := strings.TrimSpace(r.URL.Query().Get("q"))
+		var problems []problem
+		switch {
+		case text == "":
+			problems = append(problems, problem{Name: "q", Description: "must not be blank"})
+		case utf8.RuneCountInString(text) > searchMaxQueryLength:
+			problems = append(problems, problem{
+				Name:        "q",
+				Description: fmt.Sprintf("must not be longer than %d characters", searchMaxQueryLength),
+			})
+		}
+		limit := searchDefaultLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			var err error
+			limit, err = strconv.Atoi(value)
+			if err != nil || limit < 1 || limit > searchMaxLimit {
+				problems = append(problems, problem{
+					Name:        "limit",
+					Description: fmt.Sprintf("must be a whole number from 1 to %d", searchMaxLimit),
+				})
+			}
+		}
+		if len(problems) > 0 {
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				ValidationErrors: problems,
+			})
+			return
+		}
+		span.SetAttributes(attribute.Int("search.limit", limit))
+
+		results, err := service.Search(ctx, text, limit)
+		if err != nil {
+			logger.Error("error searching", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error searching",
+			})
+			return
+		}
+		span.SetAttributes(attribute.Int("search.results", len(results)))
+
+		encodeResponse(ctx, w, http.StatusOK, responseSearch{
+			Query:   text,
+			Results: mapOutputSearchResults(results),
+		})
+	}
+}
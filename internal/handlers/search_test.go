@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleSearch(t *testing.T) {
+	results := []models.SearchResult{
+		{Type: models.SearchTypePerson, ID: 4, Title: "Bill Gates", Highlight: "<mark>Bill</mark> Gates", Rank: 0.9},
+		{Type: models.SearchTypeCourse, ID: 2, Title: "Databases", Highlight: "Databases", Rank: 0.3},
+	}
+
+	tests := map[string]struct {
+		query        string
+		mockCalled   bool
+		mockText     string
+		mockLimit    int
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"results found": {
+			query:        "?q=+bil+",
+			mockCalled:   true,
+			mockText:     "bil",
+			mockLimit:    searchDefaultLimit,
+			mockOutput:   []any{results, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseSearch{Query: "bil", Results: mapOutputSearchResults(results)}),
+		},
+		"no results": {
+			query:        "?q=zzz&limit=5",
+			mockCalled:   true,
+			mockText:     "zzz",
+			mockLimit:    5,
+			mockOutput:   []any{[]models.SearchResult{}, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: `{"query": "zzz", "results": []}`,
+		},
+		"missing query": {
+			query:        "?q=%20",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "q", Description: "must not be blank"},
+			}}),
+		},
+		"query too long": {
+			query:        "?q=" + strings.Repeat("a", searchMaxQueryLength+1),
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "q", Description: "must not be longer than 200 characters"},
+			}}),
+		},
+		"invalid limit": {
+			query:        "?q=bill&limit=1000",
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{ValidationErrors: []problem{
+				{Name: "limit", Description: "must be a whole number from 1 to 100"},
+			}}),
+		},
+		"internal server error": {
+			query:        "?q=bill",
+			mockCalled:   true,
+			mockText:     "bill",
+			mockLimit:    searchDefaultLimit,
+			mockOutput:   []any{nil, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error searching"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockService := new(serviceMock.Searcher)
+			handler := HandleSearch(mockService)
+
+			if tc.mockCalled {
+				mockService.
+					On("Search", mock.Anything, tc.mockText, tc.mockLimit).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/search"+tc.query, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "Search")
+			}
+		})
+	}
+}
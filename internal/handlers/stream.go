@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// List formats a client can ask for with the Accept header or the `format` query parameter.
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
+	formatCSV    = "csv"
+)
+
+var formatContentTypes = map[string]string{
+	formatJSON:   "application/json",
+	formatNDJSON: "application/x-ndjson",
+	formatCSV:    "text/csv",
+}
+
+// streamFlushEvery is how many list items are written between flushes, so that a client starts
+// receiving a long list while the rest is still being read.
+const streamFlushEvery = 100
+
+// negotiateFormat picks the list format for a request. The `format` query parameter wins over the
+// Accept header; an unknown `format` is an error, while an Accept header naming no supported type
+// gets JSON.
+func negotiateFormat(r *http.Request) (string, error) {
+	if format := r.URL.Query().Get("format"); format != "" {
+		format = strings.ToLower(format)
+		if _, ok := formatContentTypes[format]; !ok {
+			return "", fmt.Errorf("format must be one of json, ndjson or csv, got %q", format)
+		}
+		return format, nil
+	}
+
+	best, bestQuality := formatJSON, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		for _, format := range []string{formatJSON, formatNDJSON, formatCSV} {
+			if (mediaType == formatContentTypes[format] || format == formatNDJSON && mediaType == "application/ndjson") &&
+				quality > bestQuality {
+				best, bestQuality = format, quality
+			}
+		}
+	}
+	return best, nil
+}
+
+// csvColumns describes how list items are written as CSV rows.
+type csvColumns[T any] struct {
+	header []string
+	row    func(item T) []string
+}
+
+// listStream writes a list an item at a time, so that it is sent as it is read rather than held in
+// memory. JSON is written as an object holding the list, such as `{"persons":[...]}`, NDJSON as one
+// item per line and CSV as a header row followed by a row per item. Nothing is sent before the
+// first item, so that a failure until then can still be answered with an error response.
+type listStream[T any] struct {
+	w       http.ResponseWriter
+	format  string
+	field   string
+	columns csvColumns[T]
+	encoder *json.Encoder
+	csv     *csv.Writer
+	count   int
+}
+
+func newListStream[T any](w http.ResponseWriter, format string, field string, columns csvColumns[T]) *listStream[T] {
+	s := &listStream[T]{
+		w:       w,
+		format:  format,
+		field:   field,
+		columns: columns,
+		encoder: json.NewEncoder(w),
+	}
+	if format == formatCSV {
+		s.csv = csv.NewWriter(w)
+	}
+	return s
+}
+
+// Write sends one item of the list.
+func (s *listStream[T]) Write(item T) error {
+	if s.count == 0 {
+		if err := s.begin(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch s.format {
+	case formatCSV:
+		err = s.csv.Write(s.columns.row(item))
+	case formatNDJSON:
+		err = s.encoder.Encode(item)
+	default:
+		if s.count > 0 {
+			if _, err = fmt.Fprint(s.w, ","); err != nil {
+				return err
+			}
+		}
+		// Encode appends a newline, which is valid whitespace between items.
+		err = s.encoder.Encode(item)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.count++
+	if s.count%streamFlushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+// Started reports whether any of the response has been sent.
+func (s *listStream[T]) Started() bool {
+	return s.count > 0
+}
+
+// Close ends the list, sending the opening too if the list was empty.
+func (s *listStream[T]) Close() error {
+	if s.count == 0 {
+		if err := s.begin(); err != nil {
+			return err
+		}
+	}
+	switch s.format {
+	case formatCSV:
+		s.csv.Flush()
+		return s.csv.Error()
+	case formatNDJSON:
+		return nil
+	default:
+		_, err := fmt.Fprint(s.w, "]}\n")
+		return err
+	}
+}
+
+func (s *listStream[T]) begin() error {
+	header := s.w.Header()
+	header.Add("Vary", "Accept")
+	switch s.format {
+	case formatCSV:
+		header.Set("Content-Type", "text/csv; charset=utf-8")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", s.field+".csv"))
+	default:
+		header.Set("Content-Type", formatContentTypes[s.format])
+	}
+	s.w.WriteHeader(http.StatusOK)
+
+	switch s.format {
+	case formatCSV:
+		return s.csv.Write(s.columns.header)
+	case formatNDJSON:
+		return nil
+	default:
+		field, _ := json.Marshal(s.field)
+		_, err := fmt.Fprintf(s.w, "{%s:[", field)
+		return err
+	}
+}
+
+func (s *listStream[T]) flush() error {
+	if s.format == formatCSV {
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if err := http.NewResponseController(s.w).Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// csvText guards a CSV cell against being run as a formula when the file is opened in a
+// spreadsheet.
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// csvPlain undoes csvText, so that an exported file can be imported again.
+func csvPlain(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+// csvInts joins numbers into a single CSV cell.
+func csvInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return strings.Join(parts, ";")
+}
+
+// csvOptionalInt writes a number that may be missing, leaving the cell empty when it is.
+func csvOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+// abortStream gives up on a response that is already partly sent. The connection is dropped so
+// that the client sees an error rather than a list cut short that looks complete.
+func abortStream() {
+	panic(http.ErrAbortHandler)
+}
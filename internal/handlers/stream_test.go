@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := map[string]struct {
+		url            string
+		accept         string
+		expectedFormat string
+		expectedErr    string
+	}{
+		"default": {
+			url:            "/api/person",
+			expectedFormat: formatJSON,
+		},
+		"any": {
+			url:            "/api/person",
+			accept:         "*/*",
+			expectedFormat: formatJSON,
+		},
+		"csv": {
+			url:            "/api/person",
+			accept:         "text/csv",
+			expectedFormat: formatCSV,
+		},
+		"ndjson": {
+			url:            "/api/person",
+			accept:         "application/x-ndjson",
+			expectedFormat: formatNDJSON,
+		},
+		"highest quality wins": {
+			url:            "/api/person",
+			accept:         "application/json;q=0.5, text/csv;q=0.9, */*;q=0.1",
+			expectedFormat: formatCSV,
+		},
+		"unsupported types fall back to json": {
+			url:            "/api/person",
+			accept:         "text/html",
+			expectedFormat: formatJSON,
+		},
+		"query parameter wins": {
+			url:            "/api/person?format=NDJSON",
+			accept:         "text/csv",
+			expectedFormat: formatNDJSON,
+		},
+		"unknown query parameter": {
+			url:         "/api/person?format=xlsx",
+			expectedErr: `format must be one of json, ndjson or csv, got "xlsx"`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+
+			format, err := negotiateFormat(req)
+
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedFormat, format)
+		})
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	tests := map[string]struct {
+		value    string
+		expected string
+	}{
+		"plain text":   {value: "Jobs", expected: "Jobs"},
+		"empty":        {value: "", expected: ""},
+		"formula":      {value: "=HYPERLINK(\"x\")", expected: "'=HYPERLINK(\"x\")"},
+		"leading plus": {value: "+1", expected: "'+1"},
+		"leading at":   {value: "@SUM(A1)", expected: "'@SUM(A1)"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, csvText(tc.value))
+		})
+	}
+}
@@ -2,17 +2,18 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-
-	"github.com/go-chi/httplog/v2"
 )
 
 type CourseUpdater interface {
-	UpdateCourse(ctx context.Context, courseID int, courseName string) (models.Course, error)
+	UpdateCourse(ctx context.Context, courseID int, course models.Course) (models.Course, error)
 }
 
 // UpdateCourse is a Handler that returns the course associated with the given ID.
@@ -25,18 +26,23 @@ type CourseUpdater interface {
 //	@Param			ID					path		int	true "ID of course to update"
 //	@Param			course				body		handlers.inputCourse	true	"Course Object"
 //	@Success		200					{object}	handlers.responseCourse
+//	@Failure		400					{object}	handlers.responseErr
+//	@Failure		409					{object}	handlers.responseErr	"the code is already in use"
+//	@Failure		422					{object}	handlers.responseErr	"the department does not exist"
 //	@Failure		500					{object}	handlers.responseErr
 //	@Router			/api/course/{ID}	[PUT]
-func HandleUpdateCourse(logger *httplog.Logger, service CourseUpdater) http.HandlerFunc {
+func HandleUpdateCourse(service CourseUpdater) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleUpdateCourse")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 		// setup
 		idString := chi.URLParam(r, "ID")
 		courseID, err := strconv.Atoi(idString)
 		if err != nil {
 			logger.Error("error getting ID", "error", err)
-			encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 				Error: "Not a valid ID",
 			})
 			return
@@ -48,29 +54,42 @@ func HandleUpdateCourse(logger *httplog.Logger, service CourseUpdater) http.Hand
 			switch {
 			case len(problems) > 0:
 				logger.Error("Problems validating input", "error", err, "problems", problems)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					ValidationErrors: problems,
 				})
 			default:
 				logger.Error("BodyParser error", "error", err)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					Error: "missing values or malformed body",
 				})
 			}
 			return
 		}
 		// get values from database
-		course, err := service.UpdateCourse(ctx, courseID, courseIn.Name)
+		course, err := service.UpdateCourse(ctx, courseID, courseIn)
+		if errors.Is(err, models.ErrDepartmentNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, missingDepartment)
+			return
+		}
+		if errors.Is(err, models.ErrCourseCodeTaken) {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "code is already in use",
+			})
+			return
+		}
 		if err != nil {
 			logger.Error("error getting all courses", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
 		courseOut := mapOutputCourse(course)
-		encodeResponse(w, logger, http.StatusOK, responseCourse{
+		encodeResponse(ctx, w, http.StatusOK, responseCourse{
 			Course: courseOut,
 		})
 	}
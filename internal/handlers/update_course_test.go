@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -15,17 +16,19 @@ import (
 	serviceMock "go-api-tech-challenge/internal/handlers/mock"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleUpdateCourse(t *testing.T) {
 	mockService := new(serviceMock.CourseUpdater)
-	logger := httplog.NewLogger("test")
-	handler := HandleUpdateCourse(logger, mockService)
+	handler := HandleUpdateCourse(mockService)
 
-	course := models.Course{ID: 1, Name: "Databases"}
+	courseIn := models.Course{Code: "CS220", Name: "Databases", Credits: 3, Description: "SQL", Level: "graduate"}
+	course := courseIn
+	course.ID = 1
 	courseOut := mapOutputCourse(course)
+	body := `{"code": "CS220", "name": "Databases", "credits": 3, "description": "SQL", "level": "graduate", "active": false}`
 
 	tests := map[string]struct {
 		courseID     string
@@ -37,7 +40,7 @@ func TestHandleUpdateCourse(t *testing.T) {
 	}{
 		"course updated successfully": {
 			courseID:     "1",
-			body:         `{"name": "Databases"}`,
+			body:         body,
 			mockCalled:   true,
 			mockOutput:   []any{course, nil},
 			expectedCode: http.StatusOK,
@@ -45,7 +48,7 @@ func TestHandleUpdateCourse(t *testing.T) {
 		},
 		"invalid course ID": {
 			courseID:     "abc",
-			body:         `{"name": "Databases"}`,
+			body:         body,
 			mockCalled:   false,
 			expectedCode: http.StatusBadRequest,
 			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
@@ -64,13 +67,23 @@ func TestHandleUpdateCourse(t *testing.T) {
 			expectedCode: http.StatusBadRequest,
 			expectedBody: testutil.ToJSONString(responseErr{
 				ValidationErrors: []problem{
+					{Name: "code", Description: "must be 2 to 4 letters and 3 digits, optionally followed by a letter, such as CS101"},
 					{Name: "name", Description: "must not be blank"},
+					{Name: "credits", Description: "must be between 1 and 12"},
 				},
 			}),
 		},
+		"code taken": {
+			courseID:     "1",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Course{}, fmt.Errorf("wrapped: %w", models.ErrCourseCodeTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "code is already in use"}),
+		},
 		"internal server error": {
 			courseID:     "1",
-			body:         `{"name": "Databases"}`,
+			body:         body,
 			mockCalled:   true,
 			mockOutput:   []any{models.Course{}, errors.New("test error")},
 			expectedCode: http.StatusInternalServerError,
@@ -92,7 +105,7 @@ func TestHandleUpdateCourse(t *testing.T) {
 			if tc.mockCalled {
 				id, _ := strconv.Atoi(tc.courseID) // Convert courseID to integer
 				mockService.
-					On("UpdateCourse", ctx, id, course.Name).
+					On("UpdateCourse", mock.Anything, id, courseIn).
 					Return(tc.mockOutput...).
 					Once()
 			}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type DepartmentUpdater interface {
+	UpdateDepartment(ctx context.Context, id int, department models.Department) (models.Department, error)
+}
+
+// HandleUpdateDepartment is a Handler that renames the department associated with the given ID.
+//
+//	@Summary		Update Department
+//	@Description	Renames department associated with given ID
+//	@Tags			departments
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID						path		int							true	"ID of department to update"
+//	@Param			department				body		handlers.inputDepartment	true	"Department Object"
+//	@Success		200						{object}	handlers.responseDepartment
+//	@Failure		400						{object}	handlers.responseErr
+//	@Failure		404						{object}	handlers.responseErr
+//	@Failure		409						{object}	handlers.responseErr	"the name is already in use"
+//	@Failure		500						{object}	handlers.responseErr
+//	@Router			/api/department/{ID}	[PUT]
+func HandleUpdateDepartment(service DepartmentUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleUpdateDepartment")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		// setup
+		departmentID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		// get and validate body as object
+		departmentIn, problems, err := decodeValidateBody[inputDepartment](r)
+		if err != nil {
+			switch {
+			case len(problems) > 0:
+				logger.Error("Problems validating input", "error", err, "problems", problems)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					ValidationErrors: problems,
+				})
+			default:
+				logger.Error("BodyParser error", "error", err)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: "missing values or malformed body",
+				})
+			}
+			return
+		}
+
+		department, err := service.UpdateDepartment(ctx, departmentID, departmentIn)
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "department not found",
+			})
+			return
+		case errors.Is(err, models.ErrDepartmentNameTaken):
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "name is already in use",
+			})
+			return
+		case err != nil:
+			logger.Error("error updating department", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error updating department",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseDepartment{
+			Department: mapOutputDepartment(department),
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleUpdateDepartment(t *testing.T) {
+	mockService := new(serviceMock.DepartmentUpdater)
+	handler := HandleUpdateDepartment(mockService)
+
+	departmentIn := models.Department{Name: "Applied Design"}
+	department := models.Department{ID: 2, Name: "Applied Design"}
+	body := `{"name": "Applied Design"}`
+
+	tests := map[string]struct {
+		departmentID string
+		body         string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"department updated successfully": {
+			departmentID: "2",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{department, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseDepartment{Department: mapOutputDepartment(department)}),
+		},
+		"invalid department ID": {
+			departmentID: "design",
+			body:         body,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"validation errors in body": {
+			departmentID: "2",
+			body:         `{}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "name", Description: "must not be blank"}},
+			}),
+		},
+		"department not found": {
+			departmentID: "9",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Department{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "department not found"}),
+		},
+		"name taken": {
+			departmentID: "2",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Department{}, fmt.Errorf("wrapped: %w", models.ErrDepartmentNameTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "name is already in use"}),
+		},
+		"internal server error": {
+			departmentID: "2",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Department{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error updating department"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, "/api/department/"+tc.departmentID, strings.NewReader(tc.body))
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.departmentID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.departmentID)
+				mockService.
+					On("UpdateDepartment", mock.Anything, id, departmentIn).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "UpdateDepartment")
+			}
+		})
+	}
+}
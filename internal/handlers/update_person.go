@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 )
 
 type PersonUpdater interface {
@@ -23,12 +25,16 @@ type PersonUpdater interface {
 //	@Param			name				path		string	true "last name of person to update"
 //	@Param			person				body		handlers.inputPerson	true	"Person Object"
 //	@Success		200					{object}	handlers.responsePerson
+//	@Failure		409					{object}	handlers.responseErr	"the external ID is already in use"
+//	@Failure		422					{object}	handlers.responseErr	"the department or a section does not exist"
 //	@Failure		500					{object}	handlers.responseErr
 //	@Router			/api/person/{name}	[PUT]
-func HandleUpdatePerson(logger *httplog.Logger, service PersonUpdater) http.HandlerFunc {
+func HandleUpdatePerson(service PersonUpdater) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// setup
-		ctx := r.Context()
+		ctx, span := tracer.Start(r.Context(), "HandleUpdatePerson")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
 		lastName := chi.URLParam(r, "name")
 
 		// get values from database
@@ -37,12 +43,14 @@ func HandleUpdatePerson(logger *httplog.Logger, service PersonUpdater) http.Hand
 			switch {
 			case len(problems) > 0:
 				logger.Error("Problems validating input", "error", err, "problems", problems)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					ValidationErrors: problems,
 				})
 			default:
 				logger.Error("BodyParser error", "error", err)
-				encodeResponse(w, logger, http.StatusBadRequest, responseErr{
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
 					Error: "missing values or malformed body",
 				})
 			}
@@ -50,16 +58,31 @@ func HandleUpdatePerson(logger *httplog.Logger, service PersonUpdater) http.Hand
 		}
 
 		person, err := service.UpdatePerson(ctx, lastName, personIn)
+		if errors.Is(err, models.ErrDepartmentNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, missingDepartment)
+			return
+		}
+		if errors.Is(err, models.ErrSectionNotFound) {
+			encodeResponse(ctx, w, http.StatusUnprocessableEntity, missingSection)
+			return
+		}
+		if message, ok := personConflict(err); ok {
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: message,
+			})
+			return
+		}
 		if err != nil {
 			logger.Error("error updating person", "error", err)
-			encodeResponse(w, logger, http.StatusInternalServerError, responseErr{
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
 				Error: "Error retrieving data",
 			})
 			return
 		}
 
 		personOut := mapOutputPerson(person)
-		encodeResponse(w, logger, http.StatusOK, responsePerson{
+		encodeResponse(ctx, w, http.StatusOK, responsePerson{
 			Person: personOut,
 		})
 	}
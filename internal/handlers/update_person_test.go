@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -13,22 +14,20 @@ import (
 	"go-api-tech-challenge/internal/testutil"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 func TestHandleUpdatePerson(t *testing.T) {
 	mockService := new(serviceMock.PersonUpdater)
-	logger := httplog.NewLogger("test")
-	handler := HandleUpdatePerson(logger, mockService)
+	handler := HandleUpdatePerson(mockService)
 
 	personIn := models.Person{
 		FirstName: "John",
 		LastName:  "Doe",
 		Type:      "student",
 		Age:       25,
-		Courses:   []int{1, 2},
+		Sections:  []int{1, 2},
 	}
 
 	personOut := models.Person{
@@ -37,6 +36,7 @@ func TestHandleUpdatePerson(t *testing.T) {
 		LastName:  "Doe",
 		Type:      "student",
 		Age:       25,
+		Sections:  []int{1, 2},
 		Courses:   []int{1, 2},
 	}
 
@@ -50,7 +50,7 @@ func TestHandleUpdatePerson(t *testing.T) {
 	}{
 		"person updated successfully": {
 			lastName:     "Doe",
-			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "courses": [1, 2]}`,
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
 			mockCalled:   true,
 			mockOutput:   []any{personOut, nil},
 			expectedCode: http.StatusOK,
@@ -78,15 +78,23 @@ func TestHandleUpdatePerson(t *testing.T) {
 		},
 		"person not found": {
 			lastName:     "Smith",
-			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "courses": [1, 2]}`,
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
 			mockCalled:   true,
 			mockOutput:   []any{models.Person{}, errors.New("person not found")},
 			expectedCode: http.StatusInternalServerError,
 			expectedBody: testutil.ToJSONString(responseErr{Error: "Error retrieving data"}),
 		},
+		"external id taken": {
+			lastName:     "Doe",
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
+			mockCalled:   true,
+			mockOutput:   []any{models.Person{}, fmt.Errorf("wrapped: %w", models.ErrExternalIDTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "external_id is already in use"}),
+		},
 		"internal server error": {
 			lastName:     "Doe",
-			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "courses": [1, 2]}`,
+			body:         `{"first_name": "John", "last_name": "Doe", "type": "student", "age": 25, "sections": [1, 2]}`,
 			mockCalled:   true,
 			mockOutput:   []any{models.Person{}, errors.New("test error")},
 			expectedCode: http.StatusInternalServerError,
@@ -112,9 +120,9 @@ func TestHandleUpdatePerson(t *testing.T) {
 							p.LastName == personIn.LastName &&
 							p.Type == personIn.Type &&
 							p.Age == personIn.Age &&
-							len(p.Courses) == len(personIn.Courses) &&
-							p.Courses[0] == personIn.Courses[0] &&
-							p.Courses[1] == personIn.Courses[1]
+							len(p.Sections) == len(personIn.Sections) &&
+							p.Sections[0] == personIn.Sections[0] &&
+							p.Sections[1] == personIn.Sections[1]
 					})).
 					Return(tc.mockOutput...).
 					Once()
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type TermUpdater interface {
+	UpdateTerm(ctx context.Context, id int, term models.Term) (models.Term, error)
+}
+
+// HandleUpdateTerm is a Handler that renames or moves the term associated with the given ID.
+//
+//	@Summary		Update Term
+//	@Description	Renames or moves term associated with given ID
+//	@Tags			terms
+//	@Accept			json
+//	@Produce		json
+//	@Param			ID				path		int					true	"ID of term to update"
+//	@Param			term			body		handlers.inputTerm	true	"Term Object"
+//	@Success		200				{object}	handlers.responseTerm
+//	@Failure		400				{object}	handlers.responseErr
+//	@Failure		404				{object}	handlers.responseErr
+//	@Failure		409				{object}	handlers.responseErr	"the name is already in use"
+//	@Failure		500				{object}	handlers.responseErr
+//	@Router			/api/term/{ID}	[PUT]
+func HandleUpdateTerm(service TermUpdater) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		ctx, span := tracer.Start(r.Context(), "HandleUpdateTerm")
+		defer span.End()
+		logger := logging.Subsystem(ctx, logging.SubsystemHandlers)
+		// setup
+		termID, err := strconv.Atoi(chi.URLParam(r, "ID"))
+		if err != nil {
+			logger.Error("error getting ID", "error", err)
+			encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+				Error: "Not a valid ID",
+			})
+			return
+		}
+
+		// get and validate body as object
+		termIn, problems, err := decodeValidateBody[inputTerm](r)
+		if err != nil {
+			switch {
+			case len(problems) > 0:
+				logger.Error("Problems validating input", "error", err, "problems", problems)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					ValidationErrors: problems,
+				})
+			default:
+				logger.Error("BodyParser error", "error", err)
+				tracing.RecordError(span, err)
+				encodeResponse(ctx, w, http.StatusBadRequest, responseErr{
+					Error: "missing values or malformed body",
+				})
+			}
+			return
+		}
+
+		term, err := service.UpdateTerm(ctx, termID, termIn)
+		switch {
+		case errors.Is(err, models.ErrNotFound):
+			encodeResponse(ctx, w, http.StatusNotFound, responseErr{
+				Error: "term not found",
+			})
+			return
+		case errors.Is(err, models.ErrTermNameTaken):
+			encodeResponse(ctx, w, http.StatusConflict, responseErr{
+				Error: "name is already in use",
+			})
+			return
+		case err != nil:
+			logger.Error("error updating term", "error", err)
+			tracing.RecordError(span, err)
+			encodeResponse(ctx, w, http.StatusInternalServerError, responseErr{
+				Error: "Error updating term",
+			})
+			return
+		}
+
+		encodeResponse(ctx, w, http.StatusOK, responseTerm{
+			Term: mapOutputTerm(term),
+		})
+	}
+}
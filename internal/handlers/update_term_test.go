@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	serviceMock "go-api-tech-challenge/internal/handlers/mock"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHandleUpdateTerm(t *testing.T) {
+	mockService := new(serviceMock.TermUpdater)
+	handler := HandleUpdateTerm(mockService)
+
+	startsOn := time.Date(2027, time.January, 25, 0, 0, 0, 0, time.UTC)
+	endsOn := time.Date(2027, time.May, 21, 0, 0, 0, 0, time.UTC)
+	termIn := models.Term{Name: "Spring 2027", StartsOn: startsOn, EndsOn: endsOn}
+	term := models.Term{ID: 2, Name: "Spring 2027", StartsOn: startsOn, EndsOn: endsOn}
+	body := `{"name": "Spring 2027", "starts_on": "2027-01-25", "ends_on": "2027-05-21"}`
+
+	tests := map[string]struct {
+		termID       string
+		body         string
+		mockCalled   bool
+		mockOutput   []any
+		expectedCode int
+		expectedBody string
+	}{
+		"term updated successfully": {
+			termID:       "2",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{term, nil},
+			expectedCode: http.StatusOK,
+			expectedBody: testutil.ToJSONString(responseTerm{Term: mapOutputTerm(term)}),
+		},
+		"invalid term ID": {
+			termID:       "spring",
+			body:         body,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Not a valid ID"}),
+		},
+		"validation errors in body": {
+			termID:       "2",
+			body:         `{}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{
+					{Name: "name", Description: "must not be blank"},
+					{Name: "starts_on", Description: "must be a date in the form YYYY-MM-DD"},
+					{Name: "ends_on", Description: "must be a date in the form YYYY-MM-DD"},
+				},
+			}),
+		},
+		"ends before it starts": {
+			termID:       "2",
+			body:         `{"name": "Spring 2027", "starts_on": "2027-05-21", "ends_on": "2027-05-21"}`,
+			mockCalled:   false,
+			expectedCode: http.StatusBadRequest,
+			expectedBody: testutil.ToJSONString(responseErr{
+				ValidationErrors: []problem{{Name: "ends_on", Description: "must be after starts_on"}},
+			}),
+		},
+		"term not found": {
+			termID:       "9",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, fmt.Errorf("wrapped: %w", models.ErrNotFound)},
+			expectedCode: http.StatusNotFound,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "term not found"}),
+		},
+		"name taken": {
+			termID:       "2",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, fmt.Errorf("wrapped: %w", models.ErrTermNameTaken)},
+			expectedCode: http.StatusConflict,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "name is already in use"}),
+		},
+		"internal server error": {
+			termID:       "2",
+			body:         body,
+			mockCalled:   true,
+			mockOutput:   []any{models.Term{}, errors.New("test error")},
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: testutil.ToJSONString(responseErr{Error: "Error updating term"}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPut, "/api/term/"+tc.termID, strings.NewReader(tc.body))
+			assert.NoError(t, err)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("ID", tc.termID)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			if tc.mockCalled {
+				id, _ := strconv.Atoi(tc.termID)
+				mockService.
+					On("UpdateTerm", mock.Anything, id, termIn).
+					Return(tc.mockOutput...).
+					Once()
+			}
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedCode, rr.Code, "Wrong code received")
+			assert.JSONEq(t, tc.expectedBody, rr.Body.String(), "Wrong response body")
+
+			if tc.mockCalled {
+				mockService.AssertExpectations(t)
+			} else {
+				mockService.AssertNotCalled(t, "UpdateTerm")
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Package idempotency makes POST requests safe to retry. A client sends an Idempotency-Key header
+// with a value unique to the change it wants made; the first request with that key runs, and its
+// response is stored and replayed to any retry, which does not run again.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// Header carries the client's idempotency key.
+	Header = "Idempotency-Key"
+	// ReplayedHeader is set to "true" on a response replayed from an earlier request.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+// maxKeyLength bounds a key; a UUID, the usual choice, is 36 characters.
+const maxKeyLength = 255
+
+// staleAfter is how long a request may hold its key without finishing before a retry may claim the
+// key, in case the server running the request stopped.
+const staleAfter = time.Minute
+
+// Store keeps idempotency keys and the responses to the requests that used them.
+type Store interface {
+	ReserveIdempotencyKey(ctx context.Context, key string, requestHash string, ttl time.Duration, staleAfter time.Duration) (models.IdempotencyRecord, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, key string, requestHash string, response models.IdempotentResponse) error
+	ReleaseIdempotencyKey(ctx context.Context, key string, requestHash string) error
+}
+
+// Middleware handles POST requests carrying an Idempotency-Key header; other requests pass
+// straight through. A key is kept for ttl after it is first used. Within that window:
+//
+//   - a retry with the same method, path and body gets the stored response, marked with
+//     Idempotent-Replayed;
+//   - a request reusing the key for anything else gets 422 Unprocessable Entity;
+//   - a retry arriving while the first request is still running gets 409 Conflict.
+//
+// Server errors are not stored, so that a request which failed that way can be retried.
+func Middleware(store Store, ttl time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := r.Header.Get(Header)
+			if r.Method != http.MethodPost || key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			logger := logging.Subsystem(ctx, logging.SubsystemHTTP)
+
+			if len(key) > maxKeyLength {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("%s must not be longer than %d characters", Header, maxKeyLength))
+				return
+			}
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "failed to read request body")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			hash := requestHash(r, body)
+
+			record, reserved, err := store.ReserveIdempotencyKey(ctx, key, hash, ttl, staleAfter)
+			if err != nil {
+				logger.Error("Failed to reserve idempotency key", "err", err)
+				writeError(w, http.StatusInternalServerError, "failed to check idempotency key")
+				return
+			}
+			switch {
+			case reserved:
+				run(next, w, r, store, key, hash, logger)
+			case record.RequestHash != hash:
+				writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%s was already used for a different request", Header))
+			case !record.Completed:
+				w.Header().Set("Retry-After", "1")
+				writeError(w, http.StatusConflict, fmt.Sprintf("a request with this %s is still in progress", Header))
+			default:
+				replay(w, record.Response)
+			}
+		})
+	}
+}
+
+// run serves a request that holds its key, then stores the response or, if there is none worth
+// keeping, releases the key. The store is updated even if the client has gone away.
+func run(next http.Handler, w http.ResponseWriter, r *http.Request, store Store, key string, hash string, logger *slog.Logger) {
+	ctx := context.WithoutCancel(r.Context())
+	recorder := &recordingWriter{ResponseWriter: w}
+	completed := false
+	defer func() {
+		if completed {
+			return
+		}
+		if err := store.ReleaseIdempotencyKey(ctx, key, hash); err != nil {
+			logger.Error("Failed to release idempotency key", "err", err)
+		}
+	}()
+
+	next.ServeHTTP(recorder, r)
+
+	if recorder.status == 0 {
+		recorder.status = http.StatusOK
+	}
+	if recorder.status >= http.StatusInternalServerError {
+		return
+	}
+	response := models.IdempotentResponse{
+		Status:      recorder.status,
+		ContentType: recorder.Header().Get("Content-Type"),
+		Body:        recorder.body.Bytes(),
+	}
+	if err := store.CompleteIdempotencyKey(ctx, key, hash, response); err != nil {
+		logger.Error("Failed to store idempotent response", "err", err)
+		return
+	}
+	completed = true
+}
+
+func replay(w http.ResponseWriter, response models.IdempotentResponse) {
+	if response.ContentType != "" {
+		w.Header().Set("Content-Type", response.ContentType)
+	}
+	w.Header().Set(ReplayedHeader, "true")
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(response.Body)
+}
+
+// requestHash identifies a request by its method, path, query and body.
+func requestHash(r *http.Request, body []byte) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s %s\n", r.Method, r.URL.RequestURI())
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
+
+// recordingWriter passes a response through while keeping a copy of its status and body.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 && status >= http.StatusOK {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *recordingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
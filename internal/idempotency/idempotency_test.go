@@ -0,0 +1,241 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryStore is a Store keeping keys in a map. Keys never expire.
+type memoryStore struct {
+	mu      sync.Mutex
+	records map[string]models.IdempotencyRecord
+	err     error
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{records: map[string]models.IdempotencyRecord{}}
+}
+
+func (s *memoryStore) ReserveIdempotencyKey(ctx context.Context, key string, requestHash string, ttl time.Duration, staleAfter time.Duration) (models.IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return models.IdempotencyRecord{}, false, s.err
+	}
+	if record, ok := s.records[key]; ok {
+		return record, false, nil
+	}
+	record := models.IdempotencyRecord{RequestHash: requestHash}
+	s.records[key] = record
+	return record, true, nil
+}
+
+func (s *memoryStore) CompleteIdempotencyKey(ctx context.Context, key string, requestHash string, response models.IdempotentResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = models.IdempotencyRecord{RequestHash: requestHash, Completed: true, Response: response}
+	return nil
+}
+
+func (s *memoryStore) ReleaseIdempotencyKey(ctx context.Context, key string, requestHash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+type testRequest struct {
+	method string
+	key    string
+	body   string
+}
+
+type testResponse struct {
+	status   int
+	body     string
+	replayed bool
+}
+
+func TestMiddleware(t *testing.T) {
+	longKey := strings.Repeat("k", maxKeyLength+1)
+
+	tests := map[string]struct {
+		storeErr      error
+		handlerStatus int
+		requests      []testRequest
+		expected      []testResponse
+		expectedRuns  int
+	}{
+		"retry is replayed": {
+			requests: []testRequest{
+				{method: http.MethodPost, key: "a", body: `{"first_name":"John"}`},
+				{method: http.MethodPost, key: "a", body: `{"first_name":"John"}`},
+			},
+			expected: []testResponse{
+				{status: http.StatusCreated, body: `{"id":1}`},
+				{status: http.StatusCreated, body: `{"id":1}`, replayed: true},
+			},
+			expectedRuns: 1,
+		},
+		"reuse with a different body": {
+			requests: []testRequest{
+				{method: http.MethodPost, key: "a", body: `{"first_name":"John"}`},
+				{method: http.MethodPost, key: "a", body: `{"first_name":"Jane"}`},
+			},
+			expected: []testResponse{
+				{status: http.StatusCreated, body: `{"id":1}`},
+				{status: http.StatusUnprocessableEntity, body: `{"error":"Idempotency-Key was already used for a different request"}`},
+			},
+			expectedRuns: 1,
+		},
+		"different keys both run": {
+			requests: []testRequest{
+				{method: http.MethodPost, key: "a", body: `{}`},
+				{method: http.MethodPost, key: "b", body: `{}`},
+			},
+			expected: []testResponse{
+				{status: http.StatusCreated, body: `{"id":1}`},
+				{status: http.StatusCreated, body: `{"id":2}`},
+			},
+			expectedRuns: 2,
+		},
+		"requests without a key are not deduplicated": {
+			requests: []testRequest{
+				{method: http.MethodPost, body: `{}`},
+				{method: http.MethodPost, body: `{}`},
+			},
+			expected: []testResponse{
+				{status: http.StatusCreated, body: `{"id":1}`},
+				{status: http.StatusCreated, body: `{"id":2}`},
+			},
+			expectedRuns: 2,
+		},
+		"other methods are not deduplicated": {
+			requests: []testRequest{
+				{method: http.MethodPut, key: "a", body: `{}`},
+				{method: http.MethodPut, key: "a", body: `{}`},
+			},
+			expected: []testResponse{
+				{status: http.StatusCreated, body: `{"id":1}`},
+				{status: http.StatusCreated, body: `{"id":2}`},
+			},
+			expectedRuns: 2,
+		},
+		"server errors are not stored": {
+			handlerStatus: http.StatusInternalServerError,
+			requests: []testRequest{
+				{method: http.MethodPost, key: "a", body: `{}`},
+				{method: http.MethodPost, key: "a", body: `{}`},
+			},
+			expected: []testResponse{
+				{status: http.StatusInternalServerError, body: `{"id":1}`},
+				{status: http.StatusInternalServerError, body: `{"id":2}`},
+			},
+			expectedRuns: 2,
+		},
+		"key too long": {
+			requests: []testRequest{{method: http.MethodPost, key: longKey, body: `{}`}},
+			expected: []testResponse{
+				{status: http.StatusBadRequest, body: `{"error":"Idempotency-Key must not be longer than 255 characters"}`},
+			},
+		},
+		"store error": {
+			storeErr: errors.New("connection refused"),
+			requests: []testRequest{{method: http.MethodPost, key: "a", body: `{}`}},
+			expected: []testResponse{
+				{status: http.StatusInternalServerError, body: `{"error":"failed to check idempotency key"}`},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			store := newMemoryStore()
+			store.err = tc.storeErr
+			runs := 0
+			handler := Middleware(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				runs++
+				status := tc.handlerStatus
+				if status == 0 {
+					status = http.StatusCreated
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"id":` + string(rune('0'+runs)) + `}`))
+			}))
+
+			for i, request := range tc.requests {
+				req := httptest.NewRequest(request.method, "/api/person", strings.NewReader(request.body))
+				if request.key != "" {
+					req.Header.Set(Header, request.key)
+				}
+				rr := httptest.NewRecorder()
+				handler.ServeHTTP(rr, req)
+
+				expected := tc.expected[i]
+				assert.Equal(t, expected.status, rr.Code, "request %d", i)
+				assert.JSONEq(t, expected.body, rr.Body.String(), "request %d", i)
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"), "request %d", i)
+				if expected.replayed {
+					assert.Equal(t, "true", rr.Header().Get(ReplayedHeader), "request %d", i)
+				} else {
+					assert.Empty(t, rr.Header().Get(ReplayedHeader), "request %d", i)
+				}
+			}
+			assert.Equal(t, tc.expectedRuns, runs)
+		})
+	}
+}
+
+func TestMiddlewareInProgress(t *testing.T) {
+	store := newMemoryStore()
+	started, finish := make(chan struct{}), make(chan struct{})
+	handler := Middleware(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-finish
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req := httptest.NewRequest(http.MethodPost, "/api/person", strings.NewReader(`{}`))
+		req.Header.Set(Header, "a")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}()
+	<-started
+
+	req := httptest.NewRequest(http.MethodPost, "/api/person", strings.NewReader(`{}`))
+	req.Header.Set(Header, "a")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	close(finish)
+	<-done
+
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Equal(t, "1", rr.Header().Get("Retry-After"))
+}
+
+func TestMiddlewareReleasesKeyOnPanic(t *testing.T) {
+	store := newMemoryStore()
+	handler := Middleware(store, time.Hour)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/person", strings.NewReader(`{}`))
+	req.Header.Set(Header, "a")
+	assert.Panics(t, func() {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	})
+	assert.Empty(t, store.records, "the key should be released so that the request can be retried")
+}
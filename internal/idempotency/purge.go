@@ -0,0 +1,37 @@
+package idempotency
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// purgeInterval is how often expired keys are deleted.
+const purgeInterval = 10 * time.Minute
+
+// Purger deletes the keys whose window has passed.
+type Purger interface {
+	PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error)
+}
+
+// RunPurge deletes expired keys every purgeInterval until ctx is done. Expired keys are already
+// free to be used again, so a failed purge is only logged.
+func RunPurge(ctx context.Context, purger Purger, logger *slog.Logger) {
+	ticker := time.NewTicker(purgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := purger.PurgeExpiredIdempotencyKeys(ctx)
+			if err != nil {
+				logger.Warn("Failed to purge expired idempotency keys", "err", err)
+				continue
+			}
+			if purged > 0 {
+				logger.Debug("Purged expired idempotency keys", "count", purged)
+			}
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// SubsystemKey is the log attribute that names the part of the application a line came from.
+const SubsystemKey = "subsystem"
+
+// Subsystems whose verbosity can be configured independently of the default log level.
+const (
+	SubsystemHTTP     = "http"
+	SubsystemHandlers = "handlers"
+	SubsystemServices = "services"
+	SubsystemDatabase = "database"
+	SubsystemCache    = "cache"
+)
+
+const redactedValue = "***"
+
+// levels is the set of levels a handler filters by. It is swapped as a whole when the levels are
+// changed at runtime, so a record is never filtered by a mix of old and new levels.
+type levels struct {
+	defaultLevel slog.Level
+	subsystems   map[string]slog.Level
+}
+
+// parseLevels builds levels from a default level and a map of subsystem names to level names.
+func parseLevels(defaultLevel slog.Level, subsystemLevels map[string]string) (*levels, error) {
+	l := &levels{defaultLevel: defaultLevel, subsystems: make(map[string]slog.Level, len(subsystemLevels))}
+	for subsystem, name := range subsystemLevels {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(name)); err != nil {
+			return nil, fmt.Errorf("invalid level for subsystem %q: %w", subsystem, err)
+		}
+		l.subsystems[strings.ToLower(subsystem)] = level
+	}
+	return l, nil
+}
+
+// levelFor returns the configured level for a subsystem, or the default level if it has none.
+func (l *levels) levelFor(subsystem string) slog.Level {
+	if level, ok := l.subsystems[subsystem]; ok {
+		return level
+	}
+	return l.defaultLevel
+}
+
+// handler wraps another slog.Handler, filtering records by the level configured for their subsystem
+// and masking sensitive values before they reach the wrapped handler. The wrapped handler must not
+// filter by level itself, otherwise lowering the level at runtime would have no effect.
+type handler struct {
+	next      slog.Handler
+	subsystem string
+	// levels is shared by every handler derived from the same logger.
+	levels   *atomic.Pointer[levels]
+	redactor *redactor
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.levels.Load().levelFor(h.subsystem) && h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, h.redactor.string(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(h.redactor.attr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		if attr.Key == SubsystemKey {
+			clone.subsystem = attr.Value.String()
+		}
+		redacted[i] = h.redactor.attr(attr)
+	}
+	clone.next = h.next.WithAttrs(redacted)
+	return &clone
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// forSubsystem returns a copy of h filtered at the subsystem's level without tagging its records,
+// for loggers such as the request logger whose output format is owned by another package.
+func (h *handler) forSubsystem(subsystem string) *handler {
+	clone := *h
+	clone.subsystem = subsystem
+	return &clone
+}
+
+// partialBody is a body that could not be parsed as JSON, usually because it was truncated.
+type partialBody string
+
+// redactor masks attributes whose keys are in a deny list and any occurrence of a secret value.
+type redactor struct {
+	keys    map[string]bool
+	secrets []string
+	// fields matches `"key": value` pairs for deny-listed keys in bodies that are not valid JSON.
+	fields *regexp.Regexp
+}
+
+func newRedactor(keys []string, secrets []string) *redactor {
+	r := &redactor{keys: make(map[string]bool, len(keys))}
+	var quoted []string
+	for _, key := range keys {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key != "" {
+			r.keys[key] = true
+			quoted = append(quoted, regexp.QuoteMeta(key))
+		}
+	}
+	if len(quoted) > 0 {
+		r.fields = regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]*)`)
+	}
+	for _, secret := range secrets {
+		if secret != "" {
+			r.secrets = append(r.secrets, secret)
+		}
+	}
+	return r
+}
+
+func (r *redactor) attr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if r.keys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, redactedValue)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = r.attr(member)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindString:
+		return slog.String(attr.Key, r.string(attr.Value.String()))
+	case slog.KindAny:
+		switch value := attr.Value.Any().(type) {
+		case error:
+			return slog.String(attr.Key, r.string(value.Error()))
+		case json.RawMessage:
+			return slog.String(attr.Key, r.string(string(r.json(value))))
+		case partialBody:
+			return slog.String(attr.Key, r.string(r.partial(string(value))))
+		}
+	}
+	return attr
+}
+
+// string replaces every secret in s with the redacted placeholder.
+func (r *redactor) string(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redactedValue)
+	}
+	return s
+}
+
+// partial masks deny-listed keys in a body that may be truncated JSON.
+func (r *redactor) partial(body string) string {
+	if r.fields == nil {
+		return body
+	}
+	return r.fields.ReplaceAllString(body, `${1}"`+redactedValue+`"`)
+}
+
+// json masks deny-listed keys at any depth of a JSON document. Input that is not valid JSON is
+// returned unchanged.
+func (r *redactor) json(raw []byte) []byte {
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return raw
+	}
+	out, err := json.Marshal(r.jsonValue(doc))
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+func (r *redactor) jsonValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, member := range v {
+			if r.keys[strings.ToLower(key)] {
+				v[key] = redactedValue
+				continue
+			}
+			v[key] = r.jsonValue(member)
+		}
+	case []any:
+		for i, member := range v {
+			v[i] = r.jsonValue(member)
+		}
+	}
+	return value
+}
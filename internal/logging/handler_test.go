@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New("test", Options{
+		Format:       FormatJSON,
+		RedactFields: []string{"Authorization", "age"},
+		Secrets:      []string{"hunter2"},
+		Writer:       &buf,
+	})
+	assert.NoError(t, err)
+
+	logger.Info("connecting with password hunter2",
+		"age", 67,
+		"error", errors.New("auth failed for password=hunter2"),
+		slog.Group("header", "authorization", "Bearer token", "accept", "application/json"),
+	)
+
+	var entry map[string]any
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "connecting with password ***", entry["msg"])
+	assert.Equal(t, "***", entry["age"])
+	assert.Equal(t, "auth failed for password=***", entry["error"])
+	assert.Equal(t, map[string]any{"authorization": "***", "accept": "application/json"}, entry["header"])
+}
+
+func TestHandlerSubsystemLevels(t *testing.T) {
+	tests := map[string]struct {
+		subsystem    string
+		level        slog.Level
+		expectLogged bool
+	}{
+		"default level allows info": {
+			level:        slog.LevelInfo,
+			expectLogged: true,
+		},
+		"default level drops debug": {
+			level:        slog.LevelDebug,
+			expectLogged: false,
+		},
+		"subsystem raised to debug": {
+			subsystem:    SubsystemDatabase,
+			level:        slog.LevelDebug,
+			expectLogged: true,
+		},
+		"subsystem lowered to warn": {
+			subsystem:    SubsystemServices,
+			level:        slog.LevelInfo,
+			expectLogged: false,
+		},
+		"unconfigured subsystem uses default": {
+			subsystem:    SubsystemHandlers,
+			level:        slog.LevelInfo,
+			expectLogged: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New("test", Options{
+				Level:           slog.LevelInfo,
+				Format:          FormatJSON,
+				SubsystemLevels: map[string]string{"database": "debug", "services": "warn"},
+				Writer:          &buf,
+			})
+			assert.NoError(t, err)
+
+			l := logger.Logger
+			if tc.subsystem != "" {
+				l = WithSubsystem(l, tc.subsystem)
+			}
+			l.Log(context.Background(), tc.level, "message")
+
+			assert.Equal(t, tc.expectLogged, strings.Contains(buf.String(), "message"))
+		})
+	}
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	_, err := New("test", Options{Format: "xml"})
+	assert.Error(t, err)
+
+	_, err = New("test", Options{SubsystemLevels: map[string]string{"database": "loud"}})
+	assert.Error(t, err)
+}
+
+func TestSetLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New("test", Options{
+		Level:  slog.LevelInfo,
+		Format: FormatJSON,
+		Writer: &buf,
+	})
+	assert.NoError(t, err)
+
+	// Loggers derived before the change must follow it too.
+	database := WithSubsystem(logger.Logger, SubsystemDatabase)
+
+	database.Debug("before")
+	assert.NotContains(t, buf.String(), "before")
+
+	assert.NoError(t, SetLevels(logger.Logger, slog.LevelWarn, map[string]string{"database": "debug"}))
+
+	database.Debug("database debug")
+	logger.Info("default info")
+	assert.Contains(t, buf.String(), "database debug")
+	assert.NotContains(t, buf.String(), "default info")
+
+	assert.Error(t, SetLevels(logger.Logger, slog.LevelInfo, map[string]string{"database": "loud"}))
+	assert.Error(t, SetLevels(slog.Default(), slog.LevelInfo, nil))
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
+	"sync/atomic"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httplog/v2"
+)
+
+// Supported values for the log format.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Options holds the settings used to build the application logger.
+type Options struct {
+	Level           slog.Level
+	Format          string
+	Concise         bool
+	SubsystemLevels map[string]string
+	RequestHeaders  bool
+	ResponseHeaders bool
+	// RedactFields are attribute, header and JSON body keys whose values are masked.
+	RedactFields []string
+	// Secrets are literal values, such as the database password, masked wherever they appear.
+	Secrets []string
+	// Writer is where logs are written, os.Stdout when nil.
+	Writer io.Writer
+}
+
+type contextKey struct{}
+
+// New builds an httplog.Logger whose records pass through a handler that applies per-subsystem
+// levels and redaction before they are formatted.
+func New(serviceName string, opts Options) (*httplog.Logger, error) {
+	var jsonFormat bool
+	switch strings.ToLower(opts.Format) {
+	case "", FormatText:
+	case FormatJSON:
+		jsonFormat = true
+	default:
+		return nil, fmt.Errorf("[in logging.New] unknown log format: %q", opts.Format)
+	}
+
+	initial, err := parseLevels(opts.Level, opts.SubsystemLevels)
+	if err != nil {
+		return nil, fmt.Errorf("[in logging.New] %w", err)
+	}
+	current := &atomic.Pointer[levels]{}
+	current.Store(initial)
+
+	// httplog lower-cases HideRequestHeaders in place, so hand it a copy.
+	hideHeaders := make([]string, len(opts.RedactFields))
+	copy(hideHeaders, opts.RedactFields)
+
+	logger := httplog.NewLogger(serviceName, httplog.Options{
+		// Filtering is done by handler so that levels can be changed at runtime.
+		LogLevel:           slog.Level(math.MinInt32),
+		JSON:               jsonFormat,
+		Concise:            opts.Concise,
+		RequestHeaders:     opts.RequestHeaders,
+		ResponseHeaders:    opts.ResponseHeaders,
+		HideRequestHeaders: hideHeaders,
+		Writer:             opts.Writer,
+	})
+	logger.Logger = slog.New(&handler{
+		next:     logger.Handler(),
+		levels:   current,
+		redactor: newRedactor(opts.RedactFields, opts.Secrets),
+	})
+
+	return logger, nil
+}
+
+// SetLevels replaces the default and per-subsystem levels of a logger built by New, and of every
+// logger derived from it, without restarting the application.
+func SetLevels(logger *slog.Logger, level slog.Level, subsystemLevels map[string]string) error {
+	h, ok := logger.Handler().(*handler)
+	if !ok {
+		return errors.New("[in logging.SetLevels] logger was not built by logging.New")
+	}
+
+	updated, err := parseLevels(level, subsystemLevels)
+	if err != nil {
+		return fmt.Errorf("[in logging.SetLevels] %w", err)
+	}
+	h.levels.Store(updated)
+	return nil
+}
+
+// WithLogger returns a copy of ctx that carries logger. It is used for work that runs outside an
+// HTTP request, where no request-scoped log entry exists.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the request-scoped logger created by the request logging middleware, which
+// already carries the request ID. Outside a request it falls back to a logger stored with
+// WithLogger, and finally to slog.Default.
+func FromContext(ctx context.Context) *slog.Logger {
+	if entry, ok := ctx.Value(middleware.LogEntryCtxKey).(*httplog.RequestLoggerEntry); ok && entry != nil {
+		return entry.Logger
+	}
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// Subsystem returns the context logger tagged with a subsystem, so that its verbosity follows the
+// level configured for that subsystem.
+func Subsystem(ctx context.Context, subsystem string) *slog.Logger {
+	return WithSubsystem(FromContext(ctx), subsystem)
+}
+
+// WithSubsystem tags logger with a subsystem, so that its verbosity follows the level configured
+// for that subsystem.
+func WithSubsystem(logger *slog.Logger, subsystem string) *slog.Logger {
+	return logger.With(SubsystemKey, subsystem)
+}
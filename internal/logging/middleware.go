@@ -0,0 +1,169 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httplog/v2"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header used to accept and return the request ID.
+const RequestIDHeader = "X-Request-ID"
+
+const maxRequestIDLength = 128
+
+// BodyOptions controls whether request and response bodies are added to the request log line.
+type BodyOptions struct {
+	Request  bool
+	Response bool
+	// MaxBytes caps how much of each body is captured.
+	MaxBytes int
+}
+
+// RequestLogger is an http middleware that assigns every request an ID, returns it in the
+// X-Request-ID response header and logs the request with a logger that carries the ID. Handlers and
+// services retrieve that logger with FromContext.
+//
+// It replaces httplog.RequestLogger, which uses chi's RequestID middleware and therefore neither
+// validates incoming IDs nor echoes them back to the client.
+func RequestLogger(logger *httplog.Logger, bodies BodyOptions) func(next http.Handler) http.Handler {
+	if h, ok := logger.Handler().(*handler); ok {
+		logger = &httplog.Logger{
+			Logger:  slog.New(h.forSubsystem(SubsystemHTTP)),
+			Options: logger.Options,
+		}
+	}
+
+	return chi.Chain(
+		RequestID,
+		httplog.Handler(logger),
+		attachRequestID,
+		logBodies(bodies),
+	).Handler
+}
+
+// RequestID accepts a well-formed X-Request-ID header or generates a new ID, stores it in the
+// request context under chi's request ID key and sets it on the response.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, requestID)
+		trace.SpanFromContext(r.Context()).SetAttributes(attribute.String("http.request_id", requestID))
+
+		ctx := context.WithValue(r.Context(), middleware.RequestIDKey, requestID)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// attachRequestID adds the request ID as a top-level field on the request-scoped log entry so that
+// it appears on every line logged through FromContext.
+func attachRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestID := middleware.GetReqID(r.Context()); requestID != "" {
+			httplog.LogEntrySetField(r.Context(), "requestID", slog.StringValue(requestID))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// logBodies captures up to MaxBytes of the request and response bodies and adds them to the request
+// log entry, where they are written on the response line. JSON bodies are redacted field by field.
+func logBodies(opts BodyOptions) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !opts.Request && !opts.Response {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var requestBody, responseBody *cappedBuffer
+			if opts.Request && r.Body != nil {
+				requestBody = &cappedBuffer{max: opts.MaxBytes}
+				r.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.TeeReader(r.Body, requestBody), r.Body}
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			if opts.Response {
+				responseBody = &cappedBuffer{max: opts.MaxBytes}
+				ww.Tee(responseBody)
+			}
+
+			next.ServeHTTP(ww, r)
+
+			if requestBody != nil && requestBody.Len() > 0 {
+				httplog.LogEntrySetField(r.Context(), "requestBody", requestBody.value())
+			}
+			if responseBody != nil && responseBody.Len() > 0 {
+				httplog.LogEntrySetField(r.Context(), "responseBody", responseBody.value())
+			}
+		})
+	}
+}
+
+// cappedBuffer keeps the first max bytes written to it and silently discards the rest.
+type cappedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.max - b.Len(); remaining < len(p) {
+		b.truncated = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+// value returns the captured body as a log value. Complete JSON bodies are passed as raw JSON and
+// anything else as a partialBody, so the log handler can redact individual fields of either.
+func (b *cappedBuffer) value() slog.Value {
+	if !b.truncated && json.Valid(b.Bytes()) {
+		return slog.AnyValue(json.RawMessage(b.Bytes()))
+	}
+	if b.truncated {
+		return slog.AnyValue(partialBody(b.String() + "..."))
+	}
+	return slog.AnyValue(partialBody(b.String()))
+}
+
+// validRequestID reports whether a client supplied ID is safe to log and echo back.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/httplog/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	tests := map[string]struct {
+		header          string
+		expectGenerated bool
+	}{
+		"accepts client request ID": {
+			header: "abc-123",
+		},
+		"generates missing request ID": {
+			header:          "",
+			expectGenerated: true,
+		},
+		"replaces malformed request ID": {
+			header:          "bad id\nwith newline",
+			expectGenerated: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := httplog.NewLogger("test", httplog.Options{JSON: true, Concise: true, Writer: &buf})
+
+			var handlerID string
+			handler := RequestLogger(logger, BodyOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerID = middleware.GetReqID(r.Context())
+				FromContext(r.Context()).Info("handler log line")
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/course", nil)
+			if tc.header != "" {
+				req.Header.Set(RequestIDHeader, tc.header)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			responseID := rr.Header().Get(RequestIDHeader)
+			assert.Equal(t, handlerID, responseID)
+			if tc.expectGenerated {
+				assert.NotEqual(t, tc.header, responseID)
+				assert.Len(t, responseID, 32)
+			} else {
+				assert.Equal(t, tc.header, responseID)
+			}
+
+			var handlerLine map[string]any
+			for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+				var entry map[string]any
+				assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+				if entry["msg"] == "handler log line" {
+					handlerLine = entry
+				}
+			}
+			assert.NotNil(t, handlerLine)
+			assert.Equal(t, responseID, handlerLine["requestID"])
+		})
+	}
+}
+
+func TestRequestLoggerBodies(t *testing.T) {
+	tests := map[string]struct {
+		bodies               BodyOptions
+		requestBody          string
+		expectedRequestBody  any
+		expectedResponseBody any
+	}{
+		"bodies disabled": {
+			bodies:      BodyOptions{},
+			requestBody: `{"first_name":"Bill","age":67}`,
+		},
+		"json bodies are redacted": {
+			bodies:               BodyOptions{Request: true, Response: true, MaxBytes: 1024},
+			requestBody:          `{"first_name":"Bill","age":67}`,
+			expectedRequestBody:  `{"age":"***","first_name":"Bill"}`,
+			expectedResponseBody: `{"person":{"age":"***","id":1}}`,
+		},
+		"truncated bodies are capped and redacted": {
+			bodies:               BodyOptions{Request: true, MaxBytes: 24},
+			requestBody:          `{"age":67,"first_name":"Bill"}`,
+			expectedRequestBody:  `{"age":"***","first_name":"...`,
+			expectedResponseBody: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger, err := New("test", Options{Format: FormatJSON, Concise: true, RedactFields: []string{"age"}, Writer: &buf})
+			assert.NoError(t, err)
+
+			handler := RequestLogger(logger, tc.bodies)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.requestBody, string(body))
+				w.WriteHeader(http.StatusOK)
+				_, _ = w.Write([]byte(`{"person":{"id":1,"age":67}}`))
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/person", strings.NewReader(tc.requestBody))
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			var responseLine map[string]any
+			assert.NoError(t, json.Unmarshal(buf.Bytes(), &responseLine))
+			assert.Equal(t, tc.expectedRequestBody, responseLine["requestBody"])
+			assert.Equal(t, tc.expectedResponseBody, responseLine["responseBody"])
+		})
+	}
+}
@@ -1,10 +1,41 @@
 package models
 
+// The levels a course can be taught at.
+const (
+	CourseLevelUndergraduate = "undergraduate"
+	CourseLevelGraduate      = "graduate"
+)
+
 type Course struct {
-	ID   int    `json:"id" gorm:"primaryKey"`
-	Name string `json:"name"`
+	ID int `json:"id" gorm:"primaryKey"`
+	// Code is a unique short name for the course, such as CS101, always in upper case.
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Credits     int    `json:"credits"`
+	Description string `json:"description"`
+	Level       string `json:"level"`
+	// Active is false for courses that are no longer offered.
+	Active bool `json:"active"`
+	// DepartmentID is the department teaching the course, if any.
+	DepartmentID *int `json:"department_id,omitempty"`
+	// Capacity is how many students each section of the course holds, or nil for no limit.
+	Capacity *int `json:"capacity,omitempty"`
 }
 
 func (Course) TableName() string {
 	return "course"
 }
+
+// CourseFilter narrows a list of courses. Its zero value matches every course.
+type CourseFilter struct {
+	// Level, when set, is the only level matched.
+	Level string
+	// Active, when set, matches only courses whose Active flag equals it.
+	Active *bool
+	// MinCredits and MaxCredits bound the credits matched; zero leaves that side open.
+	MinCredits int
+	MaxCredits int
+	// CodePrefix matches codes starting with it, regardless of case, such as "CS" for every
+	// computer science course.
+	CodePrefix string
+}
@@ -0,0 +1,8 @@
+package models
+
+// Department is an academic department, such as Computer Science, that courses and professors
+// belong to.
+type Department struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when a record a change refers to does not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrExternalIDTaken is returned when a person is given an external ID another person has.
+	ErrExternalIDTaken = errors.New("external ID is already in use")
+	// ErrEmailTaken is returned when a person is given an email address another person has.
+	ErrEmailTaken = errors.New("email is already in use")
+	// ErrCourseCodeTaken is returned when a course is given a code another course has.
+	ErrCourseCodeTaken = errors.New("course code is already in use")
+	// ErrDepartmentNameTaken is returned when a department is given a name another department has.
+	ErrDepartmentNameTaken = errors.New("department name is already in use")
+	// ErrDepartmentNotFound is returned when a course or person is put in a department that does
+	// not exist.
+	ErrDepartmentNotFound = errors.New("department does not exist")
+	// ErrTermNameTaken is returned when a term is given a name another term has.
+	ErrTermNameTaken = errors.New("term name is already in use")
+	// ErrTermNotFound is returned when a section is put in a term that does not exist.
+	ErrTermNotFound = errors.New("term does not exist")
+	// ErrTermInUse is returned when deleting a term that still has sections.
+	ErrTermInUse = errors.New("term has sections")
+	// ErrSectionTaken is returned when a section is given the number of another section of the
+	// same course in the same term.
+	ErrSectionTaken = errors.New("section number is already in use")
+	// ErrSectionNotFound is returned when a person is enrolled in a section that does not exist.
+	ErrSectionNotFound = errors.New("section does not exist")
+	// ErrInstructorNotProfessor is returned when a section is given an instructor who is not an
+	// existing professor.
+	ErrInstructorNotProfessor = errors.New("instructor must be an existing professor")
+	// ErrPersonTypesDiffer is returned when merging a student and a professor, whose enrollments
+	// and taught sections cannot be carried over to each other.
+	ErrPersonTypesDiffer = errors.New("persons to merge must be of the same type")
+)
+
+// DuplicatePersonError is returned when creating a person who looks like one or more persons that
+// already exist.
+type DuplicatePersonError struct {
+	Candidates []Person
+}
+
+func (e *DuplicatePersonError) Error() string {
+	return fmt.Sprintf("person may already exist: %d similar persons found", len(e.Candidates))
+}
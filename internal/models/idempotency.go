@@ -0,0 +1,17 @@
+package models
+
+// IdempotentResponse is the response stored for a request sent with an idempotency key, to be
+// replayed when the request is retried.
+type IdempotentResponse struct {
+	Status      int
+	ContentType string
+	Body        []byte
+}
+
+// IdempotencyRecord is what is stored for an idempotency key: the hash of the request that first
+// used it and, once that request has finished, its response.
+type IdempotencyRecord struct {
+	RequestHash string
+	Completed   bool
+	Response    IdempotentResponse
+}
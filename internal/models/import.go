@@ -0,0 +1,12 @@
+package models
+
+// ImportResult is the outcome of importing persons in bulk. When MissingSections is not empty the
+// import was rejected and nothing was written.
+type ImportResult struct {
+	// Persons holds the imported persons in input order, with their new IDs unless nothing was
+	// written.
+	Persons []Person
+	// MissingSections lists, by the index of the person in the import, the section IDs they refer
+	// to that do not exist.
+	MissingSections map[int][]int
+}
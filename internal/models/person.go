@@ -1,10 +1,27 @@
 package models
 
+import "time"
+
 type Person struct {
 	ID        int    `json:"id"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Type      string `json:"type"`
-	Age       int    `json:"age"`
-	Courses   []int  `json:"courses"`
+	// Age is derived from DateOfBirth when it is known.
+	Age         int        `json:"age"`
+	ExternalID  string     `json:"external_id,omitempty"`
+	Email       string     `json:"email,omitempty"`
+	Phone       string     `json:"phone,omitempty"`
+	DateOfBirth *time.Time `json:"date_of_birth,omitempty"`
+	// DepartmentID is the department a professor belongs to, if any. Students have none.
+	DepartmentID *int `json:"department_id,omitempty"`
+	// Sections are the course sections the person is enrolled in. Courses are the courses of those
+	// sections, each listed once, and cannot be set directly.
+	Sections []int `json:"sections"`
+	Courses  []int `json:"courses"`
+	// Waitlisted are the full sections the person is waiting for a seat in, in the order they
+	// joined the waitlists.
+	Waitlisted []int     `json:"waitlisted"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
 }
@@ -0,0 +1,26 @@
+package models
+
+// The kinds of record a search can find.
+const (
+	SearchTypePerson = "person"
+	SearchTypeCourse = "course"
+)
+
+// Search highlighting marks the parts of a result's title that matched the query with these.
+const (
+	HighlightStart = "<mark>"
+	HighlightStop  = "</mark>"
+)
+
+// SearchResult is one person or course matching a search.
+type SearchResult struct {
+	// Type is SearchTypePerson or SearchTypeCourse.
+	Type string
+	ID   int
+	// Title is the person's full name or the course's name.
+	Title string
+	// Highlight is Title with the matching parts between HighlightStart and HighlightStop.
+	Highlight string
+	// Rank orders results, higher first. Ranks from different search backends are not comparable.
+	Rank float64
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Term is a teaching period, such as Fall 2026, in which courses are given in sections.
+type Term struct {
+	ID       int       `json:"id"`
+	Name     string    `json:"name"`
+	StartsOn time.Time `json:"starts_on"`
+	EndsOn   time.Time `json:"ends_on"`
+}
+
+// Section is one running of a course in a term. Persons enroll in sections rather than in courses.
+type Section struct {
+	ID       int `json:"id"`
+	CourseID int `json:"course_id"`
+	TermID   int `json:"term_id"`
+	// Number tells apart the sections of a course in the same term, starting at 1.
+	Number int `json:"number"`
+	// InstructorID is the professor teaching the section, if one has been assigned.
+	InstructorID *int `json:"instructor_id,omitempty"`
+}
+
+// Enrollment is a section a person is enrolled in, with the course and term it belongs to.
+type Enrollment struct {
+	Section    Section
+	CourseCode string
+	CourseName string
+	TermName   string
+}
+
+// WaitlistPromotion is a student given a seat in a section they were waiting for, because a seat
+// was freed or added.
+type WaitlistPromotion struct {
+	PersonID  int `json:"person_id"`
+	SectionID int `json:"section_id"`
+}
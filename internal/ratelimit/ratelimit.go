@@ -0,0 +1,132 @@
+package ratelimit
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sweepInterval is how often buckets of clients that have gone quiet are dropped.
+const sweepInterval = time.Minute
+
+// Limit is a token bucket rate: clients may make RequestsPerSecond requests on average, with bursts
+// of up to Burst requests. A RequestsPerSecond of zero or less disables limiting.
+type Limit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+func (l Limit) enabled() bool {
+	return l.RequestsPerSecond > 0
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter limits requests per client, identified by remote IP. Its limit can be changed while it is
+// serving requests.
+type Limiter struct {
+	limit atomic.Pointer[Limit]
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+// New returns a Limiter enforcing limit.
+func New(limit Limit) *Limiter {
+	l := &Limiter{
+		now:     time.Now,
+		buckets: map[string]*bucket{},
+	}
+	l.SetLimit(limit)
+	return l
+}
+
+// SetLimit replaces the limit. Clients keep their remaining tokens, capped at the new burst.
+func (l *Limiter) SetLimit(limit Limit) {
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+	l.limit.Store(&limit)
+}
+
+// Allow takes a token from key's bucket. When none is left it returns false and how long the client
+// should wait before retrying.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	limit := *l.limit.Load()
+	if !limit.enabled() {
+		return true, 0
+	}
+
+	now := l.now()
+	burst := float64(limit.Burst)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now, limit)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(burst, b.tokens+now.Sub(b.last).Seconds()*limit.RequestsPerSecond)
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / limit.RequestsPerSecond * float64(time.Second))
+		return false, wait
+	}
+	b.tokens--
+	return true, 0
+}
+
+// sweep drops buckets that would have refilled completely, since they behave exactly like a new
+// client. It must be called with l.mu held.
+func (l *Limiter) sweep(now time.Time, limit Limit) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+	l.lastSweep = now
+
+	refill := time.Duration(float64(limit.Burst) / limit.RequestsPerSecond * float64(time.Second))
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Middleware rejects requests from clients over the limit with 429 Too Many Requests and a
+// Retry-After header.
+func (l *Limiter) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, wait := l.Allow(clientIP(r))
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"error":"rate limit exceeded"}` + "\n"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the host part of the remote address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
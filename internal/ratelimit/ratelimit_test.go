@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiterAllow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := New(Limit{RequestsPerSecond: 2, Burst: 2})
+	limiter.now = func() time.Time { return now }
+
+	allowed, _ := limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+
+	allowed, wait := limiter.Allow("a")
+	assert.False(t, allowed)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// Other clients have their own bucket.
+	allowed, _ = limiter.Allow("b")
+	assert.True(t, allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+}
+
+func TestLimiterSetLimit(t *testing.T) {
+	limiter := New(Limit{})
+	for range 10 {
+		allowed, _ := limiter.Allow("a")
+		assert.True(t, allowed)
+	}
+
+	limiter.SetLimit(Limit{RequestsPerSecond: 0.001, Burst: 1})
+	allowed, _ := limiter.Allow("a")
+	assert.True(t, allowed)
+	allowed, _ = limiter.Allow("a")
+	assert.False(t, allowed)
+
+	limiter.SetLimit(Limit{})
+	allowed, _ = limiter.Allow("a")
+	assert.True(t, allowed)
+}
+
+func TestMiddleware(t *testing.T) {
+	limiter := New(Limit{RequestsPerSecond: 1, Burst: 1})
+	handler := limiter.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := map[string]struct {
+		remoteAddr     string
+		expectedStatus int
+	}{
+		"first request":  {remoteAddr: "10.0.0.1:1234", expectedStatus: http.StatusNoContent},
+		"same client":    {remoteAddr: "10.0.0.1:5678", expectedStatus: http.StatusTooManyRequests},
+		"another client": {remoteAddr: "10.0.0.2:1234", expectedStatus: http.StatusNoContent},
+	}
+
+	for _, name := range []string{"first request", "same client", "another client"} {
+		tc := tests[name]
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/course", nil)
+			req.RemoteAddr = tc.remoteAddr
+			w := httptest.NewRecorder()
+
+			handler.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus == http.StatusTooManyRequests {
+				assert.Equal(t, "1", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+// Package retry calls an operation until it succeeds, backing off exponentially with jitter between
+// attempts. Attempts run on the caller's goroutine, so the operation's results need no
+// synchronisation.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Defaults used for zero Policy fields.
+const (
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 5 * time.Second
+	DefaultMultiplier   = 2.0
+)
+
+// Policy controls how often and for how long an operation is retried. The zero value retries every
+// error forever, until the context is done, using the default delays and no jitter.
+type Policy struct {
+	// MaxAttempts caps the number of calls, including the first. Zero means no cap.
+	MaxAttempts int
+	// MaxElapsed bounds the total time spent, including attempts still running when it expires.
+	// Zero means no bound beyond the caller's context.
+	MaxElapsed time.Duration
+
+	// InitialDelay is the wait before the second attempt. Each later wait is Multiplier times
+	// longer, up to MaxDelay.
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	Multiplier   float64
+	// Jitter randomly shortens each wait by up to this fraction, between 0 and 1, so that clients
+	// that failed together do not retry together.
+	Jitter float64
+
+	// Retryable reports whether an error is worth another attempt. Nil treats every error as
+	// retryable. Errors wrapped with Permanent are never retried, whatever Retryable says.
+	Retryable func(error) bool
+	// OnAttempt, if set, is called after every failed attempt. If ctx is done while Do waits to try
+	// again, the last attempt is reported a second time, with Final set.
+	OnAttempt func(Attempt)
+}
+
+// Attempt describes a failed attempt, for logging.
+type Attempt struct {
+	// Number counts attempts from 1.
+	Number int
+	Err    error
+	// Elapsed is the time since the first attempt started.
+	Elapsed time.Duration
+	// Delay is how long Do will wait before the next attempt. It is zero when Do is giving up.
+	Delay time.Duration
+	// Final is set when Do will not try again.
+	Final bool
+}
+
+// Error is returned when Do gives up. It wraps the error from the last attempt and, when Do stopped
+// because its context was done or MaxElapsed passed, the context's error too.
+type Error struct {
+	Attempts int
+	Last     error
+	Stopped  error
+}
+
+func (e *Error) Error() string {
+	if e.Stopped != nil {
+		return fmt.Sprintf("gave up after %d attempts: %v: %v", e.Attempts, e.Stopped, e.Last)
+	}
+	return fmt.Sprintf("gave up after %d attempts: %v", e.Attempts, e.Last)
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Stopped == nil {
+		return []error{e.Last}
+	}
+	return []error{e.Last, e.Stopped}
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not worth retrying. Do returns as soon as it sees it.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err, or any error it wraps, was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent permanentError
+	return errors.As(err, &permanent)
+}
+
+// Do calls fn until it returns nil, it returns an error the policy does not retry, the policy's
+// attempts or time run out, or ctx is done. fn receives a context that is cancelled when MaxElapsed
+// passes.
+func Do(ctx context.Context, policy Policy, fn func(ctx context.Context) error) error {
+	_, err := DoValue(ctx, policy, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, fn(ctx)
+	})
+	return err
+}
+
+// DoValue is Do for operations that return a value. The value from the successful attempt is
+// returned; on failure it is the zero value.
+func DoValue[T any](ctx context.Context, policy Policy, fn func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if policy.MaxAttempts < 0 {
+		return zero, fmt.Errorf("[in retry.Do] max attempts must not be negative, got %d", policy.MaxAttempts)
+	}
+	if policy.MaxElapsed > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.MaxElapsed)
+		defer cancel()
+	}
+
+	start := time.Now()
+	for number := 1; ; number++ {
+		value, err := fn(ctx)
+		if err == nil {
+			return value, nil
+		}
+
+		attempt := Attempt{
+			Number:  number,
+			Err:     err,
+			Elapsed: time.Since(start),
+		}
+		stopped := ctx.Err()
+		giveUp := stopped != nil ||
+			IsPermanent(err) ||
+			(policy.Retryable != nil && !policy.Retryable(err)) ||
+			(policy.MaxAttempts > 0 && number >= policy.MaxAttempts)
+		if !giveUp {
+			attempt.Delay = policy.Delay(number)
+		}
+		attempt.Final = giveUp
+		if policy.OnAttempt != nil {
+			policy.OnAttempt(attempt)
+		}
+		if giveUp {
+			return zero, &Error{Attempts: number, Last: err, Stopped: stopped}
+		}
+
+		timer := time.NewTimer(attempt.Delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if policy.OnAttempt != nil {
+				attempt.Elapsed, attempt.Delay, attempt.Final = time.Since(start), 0, true
+				policy.OnAttempt(attempt)
+			}
+			return zero, &Error{Attempts: number, Last: err, Stopped: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// Delay returns how long to wait after the given failed attempt, counting from 1.
+func (p Policy) Delay(attempt int) time.Duration {
+	initial := p.InitialDelay
+	if initial <= 0 {
+		initial = DefaultInitialDelay
+	}
+	maxDelay := p.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = DefaultMaxDelay
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = DefaultMultiplier
+	}
+
+	delay := float64(initial) * math.Pow(multiplier, float64(max(attempt, 1)-1))
+	delay = min(delay, float64(maxDelay))
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
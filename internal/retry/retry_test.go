@@ -0,0 +1,240 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	errTransient = errors.New("connection refused")
+	errFatal     = errors.New("password authentication failed")
+)
+
+func TestDo(t *testing.T) {
+	fast := Policy{InitialDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+	tests := map[string]struct {
+		policy           Policy
+		results          []error
+		expectedAttempts int
+		expectedErr      error
+		expectedStopped  error
+	}{
+		"succeeds first time": {
+			policy:           fast,
+			results:          []error{nil},
+			expectedAttempts: 1,
+		},
+		"succeeds after transient failures": {
+			policy:           fast,
+			results:          []error{errTransient, errTransient, nil},
+			expectedAttempts: 3,
+		},
+		"gives up after max attempts": {
+			policy: Policy{
+				MaxAttempts:  2,
+				InitialDelay: time.Millisecond,
+			},
+			results:          []error{errTransient, errTransient, nil},
+			expectedAttempts: 2,
+			expectedErr:      errTransient,
+		},
+		"permanent errors fail fast": {
+			policy:           fast,
+			results:          []error{Permanent(errFatal), nil},
+			expectedAttempts: 1,
+			expectedErr:      errFatal,
+		},
+		"errors the classifier rejects fail fast": {
+			policy: Policy{
+				InitialDelay: time.Millisecond,
+				Retryable:    func(err error) bool { return !errors.Is(err, errFatal) },
+			},
+			results:          []error{errTransient, errFatal, nil},
+			expectedAttempts: 2,
+			expectedErr:      errFatal,
+		},
+		"stops when max elapsed passes": {
+			policy: Policy{
+				MaxElapsed:   20 * time.Millisecond,
+				InitialDelay: time.Hour,
+				MaxDelay:     time.Hour,
+			},
+			results:          []error{errTransient, nil},
+			expectedAttempts: 1,
+			expectedErr:      errTransient,
+			expectedStopped:  context.DeadlineExceeded,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			err := Do(context.Background(), tc.policy, func(ctx context.Context) error {
+				attempts++
+				return tc.results[attempts-1]
+			})
+
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			if tc.expectedErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, tc.expectedErr)
+			var retryErr *Error
+			if assert.ErrorAs(t, err, &retryErr) {
+				assert.Equal(t, tc.expectedAttempts, retryErr.Attempts)
+				assert.Equal(t, tc.expectedStopped, retryErr.Stopped)
+			}
+		})
+	}
+}
+
+func TestDoStopsWhenContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	attempts := 0
+	done := make(chan error)
+	go func() {
+		done <- Do(ctx, Policy{InitialDelay: time.Hour}, func(ctx context.Context) error {
+			attempts++
+			return errTransient
+		})
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, errTransient)
+		assert.Equal(t, 1, attempts)
+	case <-time.After(time.Second):
+		t.Fatal("Do kept retrying after the context was cancelled")
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	attempts := 0
+	value, err := DoValue(context.Background(), Policy{InitialDelay: time.Millisecond}, func(ctx context.Context) (string, error) {
+		attempts++
+		if attempts < 3 {
+			return "partial", errTransient
+		}
+		return "connected", nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "connected", value)
+
+	value, err = DoValue(context.Background(), Policy{MaxAttempts: 1}, func(ctx context.Context) (string, error) {
+		return "partial", errTransient
+	})
+
+	assert.ErrorIs(t, err, errTransient)
+	assert.Equal(t, "", value)
+}
+
+func TestOnAttempt(t *testing.T) {
+	var attempts []Attempt
+	policy := Policy{
+		MaxAttempts:  3,
+		InitialDelay: time.Millisecond,
+		Multiplier:   2,
+		OnAttempt: func(attempt Attempt) {
+			attempts = append(attempts, attempt)
+		},
+	}
+
+	err := Do(context.Background(), policy, func(ctx context.Context) error {
+		return errTransient
+	})
+
+	assert.ErrorIs(t, err, errTransient)
+	if assert.Len(t, attempts, 3) {
+		for i, attempt := range attempts {
+			assert.Equal(t, i+1, attempt.Number)
+			assert.Equal(t, errTransient, attempt.Err)
+		}
+		assert.Equal(t, time.Millisecond, attempts[0].Delay)
+		assert.Equal(t, 2*time.Millisecond, attempts[1].Delay)
+		assert.False(t, attempts[1].Final)
+		assert.Equal(t, time.Duration(0), attempts[2].Delay)
+		assert.True(t, attempts[2].Final)
+	}
+}
+
+func TestOnAttemptWhenContextDoneWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var attempts []Attempt
+	policy := Policy{
+		InitialDelay: time.Hour,
+		MaxDelay:     time.Hour,
+		OnAttempt: func(attempt Attempt) {
+			attempts = append(attempts, attempt)
+			if !attempt.Final {
+				cancel()
+			}
+		},
+	}
+
+	err := Do(ctx, policy, func(ctx context.Context) error {
+		return errTransient
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	if assert.Len(t, attempts, 2) {
+		assert.Equal(t, time.Hour, attempts[0].Delay)
+		assert.False(t, attempts[0].Final)
+		assert.Equal(t, 1, attempts[1].Number)
+		assert.Equal(t, time.Duration(0), attempts[1].Delay)
+		assert.True(t, attempts[1].Final)
+	}
+}
+
+func TestDelay(t *testing.T) {
+	tests := map[string]struct {
+		policy      Policy
+		attempt     int
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		"defaults": {
+			attempt:     1,
+			expectedMin: DefaultInitialDelay,
+			expectedMax: DefaultInitialDelay,
+		},
+		"grows exponentially": {
+			policy:      Policy{InitialDelay: 10 * time.Millisecond, Multiplier: 3},
+			attempt:     3,
+			expectedMin: 90 * time.Millisecond,
+			expectedMax: 90 * time.Millisecond,
+		},
+		"capped at max delay": {
+			policy:      Policy{InitialDelay: time.Second, MaxDelay: 3 * time.Second},
+			attempt:     10,
+			expectedMin: 3 * time.Second,
+			expectedMax: 3 * time.Second,
+		},
+		"jitter shortens the delay": {
+			policy:      Policy{InitialDelay: time.Second, Jitter: 0.5},
+			attempt:     1,
+			expectedMin: 500 * time.Millisecond,
+			expectedMax: time.Second,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for range 100 {
+				delay := tc.policy.Delay(tc.attempt)
+				assert.GreaterOrEqual(t, delay, tc.expectedMin)
+				assert.LessOrEqual(t, delay, tc.expectedMax)
+			}
+		})
+	}
+}
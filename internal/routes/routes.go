@@ -1,20 +1,29 @@
 package routes
 
 import (
+	"go-api-tech-challenge/internal/cache"
 	"go-api-tech-challenge/internal/handlers"
+	"go-api-tech-challenge/internal/idempotency"
 	"go-api-tech-challenge/internal/services"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/httplog/v2"
 )
 
 type Option func(*routerOptions)
 
 type routerOptions struct {
 	registerHealthRoute bool
+	cacheStats          handlers.CacheStatsReporter
+	cacheMaxAge         time.Duration
+	importMaxRows       int
+	batchMaxOperations  int
+	idempotencyStore    idempotency.Store
+	idempotencyKeyTTL   time.Duration
+	searcher            handlers.Searcher
 }
 
-// WithRegisterHealthRoute controls whether a healthcheck route will be registered. If `false` is
+// WithRegisterHealthRoute controls whether the liveness, readiness and pool statistics routes will be registered. If `false` is
 // passed in or this function is not called, the default is `false`.
 func WithRegisterHealthRoute(registerHealthRoute bool) Option {
 	return func(options *routerOptions) {
@@ -22,39 +31,135 @@ func WithRegisterHealthRoute(registerHealthRoute bool) Option {
 	}
 }
 
-func RegisterRoutes(router *chi.Mux, logger *httplog.Logger, svsCourse *services.CourseService, svsPerson *services.PersonService, opts ...Option) {
+// WithCacheStats registers the read cache statistics route alongside the other health routes.
+func WithCacheStats(stats handlers.CacheStatsReporter) Option {
+	return func(options *routerOptions) {
+		options.cacheStats = stats
+	}
+}
+
+// WithCacheMaxAge sets how long clients may reuse successful course and person reads. If this
+// function is not called, reads are sent with `Cache-Control: no-cache`.
+func WithCacheMaxAge(maxAge time.Duration) Option {
+	return func(options *routerOptions) {
+		options.cacheMaxAge = maxAge
+	}
+}
+
+// WithImportMaxRows caps the number of rows a person import may have. If this function is not
+// called, the cap is 10000 rows.
+func WithImportMaxRows(maxRows int) Option {
+	return func(options *routerOptions) {
+		options.importMaxRows = maxRows
+	}
+}
+
+// WithBatchMaxOperations caps the number of operations a batch may have. If this function is not
+// called, the cap is 100 operations.
+func WithBatchMaxOperations(maxOperations int) Option {
+	return func(options *routerOptions) {
+		options.batchMaxOperations = maxOperations
+	}
+}
+
+// WithIdempotency makes POST requests carrying an Idempotency-Key header safe to retry, keeping
+// each key and its response in store for ttl.
+func WithIdempotency(store idempotency.Store, ttl time.Duration) Option {
+	return func(options *routerOptions) {
+		options.idempotencyStore = store
+		options.idempotencyKeyTTL = ttl
+	}
+}
+
+// WithSearch registers the search route, answered by searcher. If this function is not called, the
+// route is not registered.
+func WithSearch(searcher handlers.Searcher) Option {
+	return func(options *routerOptions) {
+		options.searcher = searcher
+	}
+}
+
+func RegisterRoutes(router *chi.Mux, svsCourse *services.CourseService, svsPerson *services.PersonService, svsDepartment *services.DepartmentService, svsTerm *services.TermService, svsHealth *services.HealthService, svsBatch *services.BatchService, opts ...Option) {
 
 	options := routerOptions{
 		registerHealthRoute: true,
+		importMaxRows:       10000,
+		batchMaxOperations:  100,
 	}
 	for _, opt := range opts {
 		opt(&options)
 	}
 
 	router.Route("/api", func(router chi.Router) {
+		if options.idempotencyStore != nil {
+			router.Use(idempotency.Middleware(options.idempotencyStore, options.idempotencyKeyTTL))
+		}
 
 		if options.registerHealthRoute {
-			router.Get("/health-check", handlers.HandleHealth(logger))
+			router.Route("/health", func(router chi.Router) {
+				router.Get("/live", handlers.HandleLiveness())
+				router.Get("/ready", handlers.HandleReadiness(svsHealth))
+				router.Get("/pool", handlers.HandlePoolStats(svsHealth))
+				if options.cacheStats != nil {
+					router.Get("/cache", handlers.HandleCacheStats(options.cacheStats))
+				}
+			})
 		}
 
 		router.Route("/course", func(router chi.Router) {
+			router.Use(cache.Control(options.cacheMaxAge))
 
-			router.Get("/", handlers.HandleListCourses(logger, svsCourse))
-			router.Post("/", handlers.HandleCreateCourse(logger, svsCourse))
-			router.Get("/{ID}", handlers.HandleGetCourseByID(logger, svsCourse))
-			router.Put("/{ID}", handlers.HandleUpdateCourse(logger, svsCourse))
-			router.Delete("/{ID}", handlers.HandleDeleteCourse(logger, svsCourse))
+			router.Get("/", handlers.HandleListCourses(svsCourse))
+			router.Post("/", handlers.HandleCreateCourse(svsCourse))
+			router.Get("/{ID}", handlers.HandleGetCourseByID(svsCourse))
+			router.Put("/{ID}", handlers.HandleUpdateCourse(svsCourse))
+			router.Delete("/{ID}", handlers.HandleDeleteCourse(svsCourse))
+			router.Get("/{ID}/sections", handlers.HandleListCourseSections(svsCourse))
+			router.Post("/{ID}/sections", handlers.HandleCreateSection(svsCourse))
 
 		})
 		router.Route("/person", func(router chi.Router) {
+			router.Use(cache.Control(options.cacheMaxAge))
 
-			router.Get("/", handlers.HandleListPersons(logger, svsPerson))
-			router.Post("/", handlers.HandleCreatePerson(logger, svsPerson))
-			router.Get("/{name}", handlers.HandleGetPersonByName(logger, svsPerson))
-			router.Put("/{name}", handlers.HandleUpdatePerson(logger, svsPerson))
-			router.Delete("/{name}", handlers.HandleDeletePerson(logger, svsPerson))
+			router.Get("/", handlers.HandleListPersons(svsPerson))
+			router.Post("/", handlers.HandleCreatePerson(svsPerson))
+			router.Post("/import", handlers.HandleImportPersons(svsPerson, options.importMaxRows))
+			router.Get("/{name}", handlers.HandleGetPersonByName(svsPerson))
+			router.Put("/{name}", handlers.HandleUpdatePerson(svsPerson))
+			router.Delete("/{name}", handlers.HandleDeletePerson(svsPerson))
+			router.Get("/{name}/enrollments", handlers.HandleListPersonEnrollments(svsPerson))
+			router.Post("/{id}/merge", handlers.HandleMergePersons(svsPerson))
 
 		})
+		router.Route("/department", func(router chi.Router) {
+			router.Use(cache.Control(options.cacheMaxAge))
+
+			router.Get("/", handlers.HandleListDepartments(svsDepartment))
+			router.Post("/", handlers.HandleCreateDepartment(svsDepartment))
+			router.Get("/{ID}", handlers.HandleGetDepartmentByID(svsDepartment))
+			router.Put("/{ID}", handlers.HandleUpdateDepartment(svsDepartment))
+			router.Delete("/{ID}", handlers.HandleDeleteDepartment(svsDepartment))
+			router.Get("/{ID}/courses", handlers.HandleListDepartmentCourses(svsDepartment))
+			router.Get("/{ID}/professors", handlers.HandleListDepartmentProfessors(svsDepartment))
+
+		})
+		router.Route("/term", func(router chi.Router) {
+			router.Use(cache.Control(options.cacheMaxAge))
+
+			router.Get("/", handlers.HandleListTerms(svsTerm))
+			router.Post("/", handlers.HandleCreateTerm(svsTerm))
+			router.Get("/{ID}", handlers.HandleGetTermByID(svsTerm))
+			router.Put("/{ID}", handlers.HandleUpdateTerm(svsTerm))
+			router.Delete("/{ID}", handlers.HandleDeleteTerm(svsTerm))
+
+		})
+		if options.searcher != nil {
+			router.With(cache.Control(options.cacheMaxAge)).Get("/search", handlers.HandleSearch(options.searcher))
+		}
+
+		// Operations of a batch are routed from this router, so they skip the middleware that
+		// already ran for the batch request.
+		router.Post("/batch", handlers.HandleBatch(router, svsBatch, options.batchMaxOperations))
 	})
 
 }
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/tracing"
+)
+
+// BatchService groups calls to the other services into one transaction.
+type BatchService struct {
+	database DB
+}
+
+func NewBatchService(db DB) *BatchService {
+	return &BatchService{
+		database: db,
+	}
+}
+
+// InTransaction calls fn with a context whose service calls all share one transaction. The
+// transaction commits if fn returns nil; otherwise it rolls back and fn's error is returned.
+func (s *BatchService) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, span := tracer.Start(ctx, "BatchService.InTransaction")
+	defer span.End()
+
+	txCtx, tx, err := database.BeginShared(ctx, s.database)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.InTransaction] %w", err)
+	}
+
+	if err := fn(txCtx); err != nil {
+		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
+			logging.Subsystem(ctx, logging.SubsystemServices).Error("failed to roll back transaction", "error", rollbackErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.InTransaction] %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchServiceInTransaction(t *testing.T) {
+	insertQuery := regexp.QuoteMeta(`INSERT INTO course (code, name, credits, description, level, active, department_id, capacity)`)
+	listQuery := regexp.QuoteMeta(`SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course ORDER BY id asc`)
+	programming := models.Course{ID: 1, Code: "CS101", Name: "Programming", Credits: 4, Level: "undergraduate", Active: true}
+	compilers := models.Course{Code: "CS410", Name: "Compilers", Credits: 3, Level: "graduate", Active: true}
+	errRejected := errors.New("rejected")
+	created := compilers
+	created.ID = 2
+
+	tests := map[string]struct {
+		mockBeginErr         error
+		fnErr                error
+		mockCommitErr        error
+		expectedErr          error
+		expectedInvalidation bool
+	}{
+		"committed": {
+			expectedInvalidation: true,
+		},
+		"rolled back when fn fails": {
+			fnErr:       errRejected,
+			expectedErr: errRejected,
+		},
+		"commit fails": {
+			mockCommitErr: errors.New("connection reset"),
+		},
+		"begin fails": {
+			mockBeginErr: errors.New("connection refused"),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			mockDB, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			readCache := cache.NewAside(cache.NewLRU(10), time.Minute)
+			courses := NewCourseService(mockDB, WithCache(readCache))
+			service := NewBatchService(mockDB)
+
+			if tc.mockBeginErr != nil {
+				mockDB.ExpectBegin().WillReturnError(tc.mockBeginErr)
+			} else {
+				mockDB.ExpectBegin()
+				mockDB.ExpectQuery(insertQuery).
+					WithArgs(compilers.Code, compilers.Name, compilers.Credits, compilers.Description, compilers.Level, compilers.Active, compilers.DepartmentID, compilers.Capacity).
+					WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(2))
+				mockDB.ExpectQuery(listQuery).
+					WillReturnRows(testutil.MustStructsToRows([]models.Course{programming, created}))
+				switch {
+				case tc.fnErr != nil:
+					mockDB.ExpectRollback()
+				case tc.mockCommitErr != nil:
+					mockDB.ExpectCommit().WillReturnError(tc.mockCommitErr)
+				default:
+					mockDB.ExpectCommit()
+				}
+			}
+
+			err = service.InTransaction(ctx, func(ctx context.Context) error {
+				_, err := courses.CreateCourse(ctx, compilers)
+				assert.NoError(t, err)
+				list, err := courses.ListCourses(ctx, models.CourseFilter{})
+				assert.NoError(t, err)
+				assert.Equal(t, []models.Course{programming, created}, list,
+					"reads in the transaction should see its writes")
+				assert.Zero(t, readCache.CacheStats(ctx).Invalidations, "the cache should not be invalidated before commit")
+				return tc.fnErr
+			})
+
+			switch {
+			case tc.expectedErr != nil:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			case tc.mockBeginErr != nil || tc.mockCommitErr != nil:
+				assert.Error(t, err)
+			default:
+				assert.NoError(t, err)
+			}
+			stats := readCache.CacheStats(ctx)
+			assert.Equal(t, tc.expectedInvalidation, stats.Invalidations == 1)
+			assert.Zero(t, stats.Entries, "reads in the transaction should not be cached")
+			assert.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Cache keys for reads. Every key for a kind of record starts with its prefix, so that a change
+// can invalidate all of them at once.
+const (
+	courseKeyPrefix = "course:"
+	courseListKey   = courseKeyPrefix + "list"
+	personKeyPrefix = "person:"
+	personListKey   = personKeyPrefix + "list"
+
+	departmentKeyPrefix = "department:"
+	departmentListKey   = departmentKeyPrefix + "list"
+	termKeyPrefix       = "term:"
+	termListKey         = termKeyPrefix + "list"
+)
+
+func courseKey(id int) string {
+	return courseKeyPrefix + "id:" + strconv.Itoa(id)
+}
+
+// courseSectionsKey is the key for every section of the course, in any term.
+func courseSectionsKey(courseID int) string {
+	return courseKeyPrefix + "sections:" + strconv.Itoa(courseID)
+}
+
+// courseCodeKey is case-insensitive, like the lookup by code.
+func courseCodeKey(code string) string {
+	return courseKeyPrefix + "code:" + strings.ToUpper(code)
+}
+
+func departmentKey(id int) string {
+	return departmentKeyPrefix + "id:" + strconv.Itoa(id)
+}
+
+func termKey(id int) string {
+	return termKeyPrefix + "id:" + strconv.Itoa(id)
+}
+
+// personKey is case-insensitive, like the lookup by name.
+func personKey(name string) string {
+	return personKeyPrefix + "name:" + strings.ToLower(name)
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourseServiceCache(t *testing.T) {
+	ctx := context.Background()
+	listQuery := regexp.QuoteMeta(`SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course ORDER BY id asc`)
+	programming := models.Course{ID: 1, Code: "CS101", Name: "Programming", Credits: 4, Level: "undergraduate", Active: true}
+	compilers := models.Course{ID: 1, Code: "CS410", Name: "Compilers", Credits: 3, Level: "graduate", Active: true}
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	readCache := cache.NewAside(cache.NewLRU(10), time.Minute)
+	service := NewCourseService(mockDB, WithCache(readCache))
+
+	mockDB.ExpectQuery(listQuery).
+		WillReturnRows(testutil.MustStructsToRows([]models.Course{programming}))
+	mockDB.ExpectBegin()
+	mockDB.ExpectExec(regexp.QuoteMeta(`UPDATE course SET code = $1, name = $2`)).
+		WithArgs(compilers.Code, compilers.Name, compilers.Credits, compilers.Description, compilers.Level, compilers.Active, compilers.DepartmentID, compilers.Capacity, 1).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT id FROM course_section WHERE course_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}))
+	mockDB.ExpectCommit()
+	mockDB.ExpectQuery(listQuery).
+		WillReturnRows(testutil.MustStructsToRows([]models.Course{compilers}))
+
+	for range 2 {
+		courses, err := service.ListCourses(ctx, models.CourseFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Course{programming}, courses)
+	}
+
+	_, err = service.UpdateCourse(ctx, 1, compilers)
+	assert.NoError(t, err)
+
+	courses, err := service.ListCourses(ctx, models.CourseFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Course{compilers}, courses)
+
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+	assert.Equal(t, models.CacheStats{Hits: 1, Misses: 2, HitRatio: 1.0 / 3, Invalidations: 1, Entries: 1}, readCache.CacheStats(ctx))
+}
+
+func TestPersonServiceCacheInvalidatedByEnrollment(t *testing.T) {
+	ctx := context.Background()
+	getQuery := `SELECT p.id as person_id, .* WHERE LOWER\(p.last_name\) = LOWER\(\$1\)`
+	columns := []string{"person_id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	service := NewPersonService(mockDB, WithCache(cache.NewAside(cache.NewLRU(10), time.Minute)))
+
+	mockDB.ExpectQuery(getQuery).WithArgs("Jobs").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(1, "Steve", "Jobs", "professor", 56, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{1}, []int{1}, []int{}))
+	mockDB.ExpectBegin()
+	mockDB.ExpectQuery(`UPDATE person`).
+		WithArgs("Steve", "Jobs", "professor", 56, "", "", "", (*time.Time)(nil), (*int)(nil), "jobs").
+		WillReturnRows(pgxmock.NewRows(updatePersonColumns).
+			AddRow(1, "Steve", "Jobs", "professor", 56, "", "", "", nil, time.Time{}, time.Time{}, nil, "professor"))
+	mockDB.ExpectQuery(regexp.QuoteMeta(`DELETE FROM enrollment WHERE person_id = $1 RETURNING section_id`)).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"section_id"}).AddRow(1))
+	mockDB.ExpectExec(regexp.QuoteMeta(`DELETE FROM waitlist WHERE person_id = $1`)).
+		WithArgs(1, []int{4}).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+	mockDB.ExpectExec(regexp.QuoteMeta(`SELECT id FROM course_section WHERE id = ANY($1) ORDER BY id FOR UPDATE`)).
+		WithArgs([]int{1, 4}).
+		WillReturnResult(pgxmock.NewResult("SELECT", 2))
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT cs.id, c.capacity - COUNT(p.id)`)).
+		WithArgs([]int{4}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}))
+	mockDB.ExpectCopyFrom(pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}).
+		WillReturnResult(1)
+	mockDB.ExpectQuery(`WITH queue AS`).
+		WithArgs([]int{1}).
+		WillReturnRows(pgxmock.NewRows([]string{"person_id", "section_id"}))
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT e.section_id, cs.course_id FROM enrollment e`)).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"section_id", "course_id"}).AddRow(4, 2))
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT section_id FROM waitlist WHERE person_id = $1`)).
+		WithArgs(1).
+		WillReturnRows(pgxmock.NewRows([]string{"section_id"}))
+	mockDB.ExpectCommit()
+	mockDB.ExpectQuery(getQuery).WithArgs("JOBS").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(1, "Steve", "Jobs", "professor", 56, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{2}, []int{4}, []int{}))
+
+	for _, name := range []string{"Jobs", "jobs"} {
+		person, err := service.GetPersonByName(ctx, name)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1}, person.Courses)
+	}
+
+	_, err = service.UpdatePerson(ctx, "jobs", models.Person{FirstName: "Steve", LastName: "Jobs", Type: "professor", Age: 56, Sections: []int{4}})
+	assert.NoError(t, err)
+
+	person, err := service.GetPersonByName(ctx, "JOBS")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2}, person.Courses)
+
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
@@ -2,30 +2,80 @@ package services
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type CourseService struct {
-	database *sql.DB
+	database         DB
+	reads            reader
+	cache            *cache.Aside
+	statementTimeout time.Duration
 }
 
-func NewCourseService(db *sql.DB) *CourseService {
+func NewCourseService(db DB, opts ...Option) *CourseService {
+	options := newServiceOptions(opts)
 	return &CourseService{
-		database: db,
+		database:         db,
+		reads:            reader{primary: db, replicas: options.replicas},
+		cache:            options.cache,
+		statementTimeout: options.statementTimeout,
+	}
+}
+
+// ListCourses returns the courses matching filter, by ID. The whole list is small, so it is read
+// and cached as one and filtered afterwards.
+func (s *CourseService) ListCourses(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	courses, err := cache.Load(ctx, s.cache, courseListKey, s.listCourses)
+	if err != nil {
+		return courses, err
+	}
+	return filterCourses(courses, filter), nil
+}
+
+// filterCourses returns the courses matching filter, in the order given.
+func filterCourses(courses []models.Course, filter models.CourseFilter) []models.Course {
+	if filter == (models.CourseFilter{}) {
+		return courses
+	}
+	matched := []models.Course{}
+	for _, course := range courses {
+		switch {
+		case filter.Level != "" && course.Level != filter.Level,
+			filter.Active != nil && course.Active != *filter.Active,
+			filter.MinCredits != 0 && course.Credits < filter.MinCredits,
+			filter.MaxCredits != 0 && course.Credits > filter.MaxCredits,
+			!strings.HasPrefix(course.Code, strings.ToUpper(filter.CodePrefix)):
+			continue
+		}
+		matched = append(matched, course)
 	}
+	return matched
 }
 
-func (s *CourseService) ListCourses(ctx context.Context) ([]models.Course, error) {
+func (s *CourseService) listCourses(ctx context.Context) ([]models.Course, error) {
 
-	query := `SELECT * FROM course 
+	query := `SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course 
 	ORDER BY id asc`
-	rows, err := s.database.QueryContext(
+	ctx, span := startQuerySpan(ctx, "CourseService.ListCourses", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(
 		ctx,
 		query,
 	)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return []models.Course{}, fmt.Errorf("[in services.ListCourses] failed to get courses: %w", err)
 	}
 	defer rows.Close()
@@ -33,29 +83,44 @@ func (s *CourseService) ListCourses(ctx context.Context) ([]models.Course, error
 	var courses []models.Course
 	for rows.Next() {
 		var course models.Course
-		err = rows.Scan(&course.ID, &course.Name)
+		err = rows.Scan(courseFields(&course)...)
 		if err != nil {
+			tracing.RecordError(span, err)
 			return []models.Course{}, fmt.Errorf("[in services.ListCourses] failed to scan course from row: %w", err)
 		}
 		courses = append(courses, course)
 	}
 
 	if err = rows.Err(); err != nil {
+		tracing.RecordError(span, err)
 		return []models.Course{}, fmt.Errorf("[in services.ListCourses] failed to scan courses: %w", err)
 	}
+	setRowsReturned(span, len(courses))
+	logging.Subsystem(ctx, logging.SubsystemServices).Debug("listed courses", "count", len(courses))
 
 	return courses, nil
 
 }
 
 func (s *CourseService) GetCourseByID(ctx context.Context, id int) (models.Course, error) {
+	return cache.Load(ctx, s.cache, courseKey(id), func(ctx context.Context) (models.Course, error) {
+		return s.getCourseByID(ctx, id)
+	})
+}
+
+func (s *CourseService) getCourseByID(ctx context.Context, id int) (models.Course, error) {
 	var course models.Course
-	query := "SELECT id, name FROM course WHERE id = $1"
+	query := "SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course WHERE id = $1"
+	ctx, span := startQuerySpan(ctx, "CourseService.GetCourseByID", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
 
-	err := s.database.QueryRowContext(ctx, query, id).Scan(&course.ID, &course.Name)
+	err := s.reads.QueryRow(ctx, query, id).Scan(courseFields(&course)...)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return models.Course{}, fmt.Errorf("[in services.GetCourseByIDByID] no course found with id: %d", id)
+		tracing.RecordError(span, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Course{}, fmt.Errorf("[in services.GetCourseByIDByID] no course found with id %d: %w", id, models.ErrNotFound)
 		}
 		return models.Course{}, fmt.Errorf("[in services.GetCourseByIDByID] failed to retrieve course: %w", err)
 	}
@@ -63,57 +128,146 @@ func (s *CourseService) GetCourseByID(ctx context.Context, id int) (models.Cours
 	return course, nil
 }
 
-func (s *CourseService) UpdateCourse(ctx context.Context, courseID int, newName string) (models.Course, error) {
-	query := `UPDATE course SET name = $1 WHERE id = $2`
-	result, err := s.database.ExecContext(ctx, query, newName, courseID)
+// GetCourseByCode returns the course with the given code, regardless of case. It wraps
+// models.ErrNotFound when there is none.
+func (s *CourseService) GetCourseByCode(ctx context.Context, code string) (models.Course, error) {
+	return cache.Load(ctx, s.cache, courseCodeKey(code), func(ctx context.Context) (models.Course, error) {
+		return s.getCourseByCode(ctx, code)
+	})
+}
+
+func (s *CourseService) getCourseByCode(ctx context.Context, code string) (models.Course, error) {
+	var course models.Course
+	query := "SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course WHERE upper(code) = upper($1)"
+	ctx, span := startQuerySpan(ctx, "CourseService.GetCourseByCode", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := s.reads.QueryRow(ctx, query, code).Scan(courseFields(&course)...)
 	if err != nil {
+		tracing.RecordError(span, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Course{}, fmt.Errorf("[in services.GetCourseByCode] no course found with code %s: %w", code, models.ErrNotFound)
+		}
+		return models.Course{}, fmt.Errorf("[in services.GetCourseByCode] failed to retrieve course: %w", err)
+	}
+
+	return course, nil
+}
+
+// UpdateCourse replaces the course with the given ID. Seats freed by raising or removing its
+// capacity go to the students waiting for them.
+func (s *CourseService) UpdateCourse(ctx context.Context, courseID int, course models.Course) (models.Course, error) {
+	ctx, span := tracer.Start(ctx, "CourseService.UpdateCourse")
+	defer span.End()
 
-		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to update course: %w", err)
+	tx, err := conn(ctx, s.database).Begin(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to begin transaction: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	query := `UPDATE course SET code = $1, name = $2, credits = $3, description = $4, level = $5, active = $6,
+	department_id = $7, capacity = $8
+	WHERE id = $9`
+	queryCtx, querySpan := startQuerySpan(ctx, "update course", query)
+	queryCtx, cancelQuery := withStatementTimeout(queryCtx, s.statementTimeout)
+	defer cancelQuery()
+	result, err := tx.Exec(queryCtx, query, course.Code, course.Name, course.Credits, course.Description,
+		course.Level, course.Active, course.DepartmentID, course.Capacity, courseID)
 	if err != nil {
-		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to get rows affected: %w", err)
+		tracing.RecordError(querySpan, err)
+		querySpan.End()
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to update course: %w", constraintError(err))
 	}
 
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(querySpan, rowsAffected)
+	querySpan.End()
+
 	if rowsAffected == 0 {
+		rollback(ctx, tx)
 		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] no course found with id: %d", courseID)
 
 	}
 
-	return models.Course{
-		ID:   courseID,
-		Name: newName,
-	}, nil
+	sectionIDs, err := s.lockCourseSections(ctx, tx, courseID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to lock sections: %w", err)
+	}
+	promotions, err := promoteWaitlisted(ctx, tx, s.statementTimeout, sectionIDs)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to promote waitlisted students: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Course{}, fmt.Errorf("[in services.UpdateCourse] failed to commit transaction: %w", err)
+	}
+	s.cache.Invalidate(ctx, courseKeyPrefix)
+	if len(promotions) > 0 {
+		s.cache.Invalidate(ctx, personKeyPrefix)
+	}
+
+	course.ID = courseID
+	return course, nil
 }
 
-func (s *CourseService) CreateCourse(ctx context.Context, courseName string) (models.Course, error) {
-	query := `INSERT INTO course (name) VALUES ($1) RETURNING id`
-	var newID int
+func (s *CourseService) CreateCourse(ctx context.Context, course models.Course) (models.Course, error) {
+	query := `INSERT INTO course (code, name, credits, description, level, active, department_id, capacity)
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	RETURNING id`
+	ctx, span := startQuerySpan(ctx, "CourseService.CreateCourse", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
 
-	err := s.database.QueryRowContext(ctx, query, courseName).Scan(&newID)
+	err := conn(ctx, s.database).QueryRow(ctx, query, course.Code, course.Name, course.Credits, course.Description,
+		course.Level, course.Active, course.DepartmentID, course.Capacity).Scan(&course.ID)
 	if err != nil {
-		return models.Course{}, fmt.Errorf("failed to create course: %w", err)
+		tracing.RecordError(span, err)
+		return models.Course{}, fmt.Errorf("failed to create course: %w", constraintError(err))
 	}
-	return models.Course{ID: newID, Name: courseName}, nil
+	s.cache.Invalidate(ctx, courseKeyPrefix)
+	return course, nil
 }
 
 func (s *CourseService) DeleteCourse(ctx context.Context, courseID int) error {
 	query := `DELETE FROM course WHERE id = $1`
+	ctx, span := startQuerySpan(ctx, "CourseService.DeleteCourse", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
 
-	result, err := s.database.ExecContext(ctx, query, courseID)
+	result, err := conn(ctx, s.database).Exec(ctx, query, courseID)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return fmt.Errorf("[in services.DeleteCourse] failed to delete course: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("[in services.DeleteCourse] failed to get rows affected: %w", err)
-	}
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(span, rowsAffected)
 
 	if rowsAffected == 0 {
 		return fmt.Errorf("[in services.DeleteCourse] no course found with id %d", courseID)
 	}
+	// The delete cascades to the course's sections, and with them to enrollments and waitlists.
+	s.cache.Invalidate(ctx, courseKeyPrefix, personKeyPrefix)
 
 	return nil
 }
+
+// courseFields returns where to scan the columns every course query selects, in order: id, code,
+// name, credits, description, level, active, department_id and capacity.
+func courseFields(course *models.Course) []any {
+	return []any{&course.ID, &course.Code, &course.Name, &course.Credits, &course.Description, &course.Level,
+		&course.Active, &course.DepartmentID, &course.Capacity}
+}
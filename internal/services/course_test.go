@@ -2,17 +2,18 @@ package services
 
 import (
 	"context"
-	"database/sql"
-	"database/sql/driver"
 	"errors"
 	"fmt"
 	"regexp"
 	"testing"
+	"time"
 
 	"go-api-tech-challenge/internal/models"
 	"go-api-tech-challenge/internal/testutil"
 
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -20,7 +21,7 @@ import (
 type testSuit struct {
 	suite.Suite
 	service *CourseService
-	dbMock  sqlmock.Sqlmock
+	dbMock  pgxmock.PgxPoolIface
 }
 
 func TestTestSuit(t *testing.T) {
@@ -28,11 +29,11 @@ func TestTestSuit(t *testing.T) {
 }
 
 func (s *testSuit) SetupSuite() {
-	db, mock, err := sqlmock.New()
+	mock, err := pgxmock.NewPool()
 	assert.NoError(s.T(), err)
 
 	s.dbMock = mock
-	s.service = NewCourseService(db)
+	s.service = NewCourseService(mock)
 }
 
 func (s *testSuit) TearDownSuite() {
@@ -44,12 +45,12 @@ func (s *testSuit) TestListCourses() {
 	t := s.T()
 
 	courses := []models.Course{
-		{ID: 1, Name: "Databases"},
-		{ID: 2, Name: "Operating Systems"},
+		{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true},
+		{ID: 2, Code: "CS340", Name: "Operating Systems", Credits: 4, Level: "undergraduate"},
 	}
 
 	testCases := map[string]struct {
-		mockReturn     *sqlmock.Rows
+		mockReturn     *pgxmock.Rows
 		mockReturnErr  error
 		expectedReturn []models.Course
 		expectedError  error
@@ -61,7 +62,7 @@ func (s *testSuit) TestListCourses() {
 			expectedError:  nil,
 		},
 		"Error getting courses": {
-			mockReturn:     &sqlmock.Rows{},
+			mockReturn:     pgxmock.NewRows(nil),
 			mockReturnErr:  errors.New("test"),
 			expectedReturn: []models.Course{},
 			expectedError:  fmt.Errorf("[in services.ListCourses] failed to get courses: %w", errors.New("test")),
@@ -70,13 +71,13 @@ func (s *testSuit) TestListCourses() {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			exp := `SELECT * FROM course`
+			exp := `SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course`
 			s.dbMock.
 				ExpectQuery(regexp.QuoteMeta(exp)).
 				WillReturnRows(tc.mockReturn).
 				WillReturnError(tc.mockReturnErr)
 
-			actualReturn, err := s.service.ListCourses(context.Background())
+			actualReturn, err := s.service.ListCourses(context.Background(), models.CourseFilter{})
 
 			assert.Equal(t, tc.expectedError, err)
 			assert.Equal(t, tc.expectedReturn, actualReturn)
@@ -90,39 +91,82 @@ func (s *testSuit) TestListCourses() {
 func (s *testSuit) TestUpdateCourse() {
 	t := s.T()
 
-	courseIn := models.Course{ID: 1, Name: "Databases"}
-	courseOut := models.Course{ID: 1, Name: "Advanced Databases"}
+	capacity := 40
+	courseIn := models.Course{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true}
+	courseOut := models.Course{ID: 1, Code: "CS420", Name: "Advanced Databases", Credits: 4, Level: "graduate", Active: true, Capacity: &capacity}
+	lockQuery := `SELECT id FROM course_section WHERE course_id = $1 ORDER BY id FOR UPDATE`
+	promoteQuery := `INSERT INTO enrollment (person_id, section_id)
+			SELECT person_id, section_id FROM promoted ORDER BY id
+			ON CONFLICT DO NOTHING
+			RETURNING person_id, section_id`
+	notifyQuery := `SELECT pg_notify($1, $2)`
 
 	testCases := map[string]struct {
-		mockInputArgs  []driver.Value
-		mockReturn     driver.Result
-		mockReturnErr  error
-		inputID        int
-		inputCourse    models.Course
-		expectedReturn models.Course
-		expectedError  error
+		mockInputArgs    []any
+		mockReturn       pgconn.CommandTag
+		mockReturnErr    error
+		mockSections     *pgxmock.Rows
+		mockPromoted     *pgxmock.Rows
+		expectedNotified []string
+		inputID          int
+		inputCourse      models.Course
+		expectedReturn   models.Course
+		expectedError    error
 	}{
 		"course updated by ID": {
-			mockInputArgs:  []driver.Value{courseOut.Name, int(courseOut.ID)},
-			mockReturn:     sqlmock.NewResult(1, 1),
-			mockReturnErr:  nil,
+			mockInputArgs:  []any{courseOut.Code, courseOut.Name, courseOut.Credits, courseOut.Description, courseOut.Level, courseOut.Active, courseOut.DepartmentID, courseOut.Capacity, int(courseOut.ID)},
+			mockReturn:     pgxmock.NewResult("UPDATE", 1),
+			mockSections:   pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(4),
+			mockPromoted:   pgxmock.NewRows([]string{"person_id", "section_id"}),
 			inputID:        int(courseIn.ID),
 			inputCourse:    courseOut,
 			expectedReturn: courseOut,
 			expectedError:  nil,
 		},
+		"waitlisted students promoted to the seats added": {
+			mockInputArgs: []any{courseOut.Code, courseOut.Name, courseOut.Credits, courseOut.Description, courseOut.Level, courseOut.Active, courseOut.DepartmentID, courseOut.Capacity, int(courseOut.ID)},
+			mockReturn:    pgxmock.NewResult("UPDATE", 1),
+			mockSections:  pgxmock.NewRows([]string{"id"}).AddRow(1).AddRow(4),
+			mockPromoted:  pgxmock.NewRows([]string{"person_id", "section_id"}).AddRow(3, 1).AddRow(5, 4),
+			expectedNotified: []string{
+				`{"person_id":3,"section_id":1}`,
+				`{"person_id":5,"section_id":4}`,
+			},
+			inputID:        int(courseIn.ID),
+			inputCourse:    courseOut,
+			expectedReturn: courseOut,
+			expectedError:  nil,
+		},
+		"course without sections": {
+			mockInputArgs:  []any{courseIn.Code, courseIn.Name, courseIn.Credits, courseIn.Description, courseIn.Level, courseIn.Active, courseIn.DepartmentID, courseIn.Capacity, 2},
+			mockReturn:     pgxmock.NewResult("UPDATE", 1),
+			mockSections:   pgxmock.NewRows([]string{"id"}),
+			inputID:        2,
+			inputCourse:    courseIn,
+			expectedReturn: models.Course{ID: 2, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true},
+			expectedError:  nil,
+		},
 		"Error updating course": {
-			mockInputArgs:  []driver.Value{courseIn.Name, 5},
-			mockReturn:     nil,
+			mockInputArgs:  []any{courseIn.Code, courseIn.Name, courseIn.Credits, courseIn.Description, courseIn.Level, courseIn.Active, courseIn.DepartmentID, courseIn.Capacity, 5},
+			mockReturn:     pgconn.CommandTag{},
 			mockReturnErr:  errors.New("test"),
 			inputID:        5,
 			inputCourse:    courseIn,
 			expectedReturn: models.Course{},
 			expectedError:  fmt.Errorf("[in services.UpdateCourse] failed to update course: %w", errors.New("test")),
 		},
+		"code taken": {
+			mockInputArgs:  []any{courseIn.Code, courseIn.Name, courseIn.Credits, courseIn.Description, courseIn.Level, courseIn.Active, courseIn.DepartmentID, courseIn.Capacity, 5},
+			mockReturn:     pgconn.CommandTag{},
+			mockReturnErr:  &pgconn.PgError{Code: "23505", ConstraintName: "course_code"},
+			inputID:        5,
+			inputCourse:    courseIn,
+			expectedReturn: models.Course{},
+			expectedError:  fmt.Errorf("[in services.UpdateCourse] failed to update course: %w", models.ErrCourseCodeTaken),
+		},
 		"no rows affected": {
-			mockInputArgs:  []driver.Value{courseIn.Name, 88},
-			mockReturn:     sqlmock.NewResult(0, 0),
+			mockInputArgs:  []any{courseIn.Code, courseIn.Name, courseIn.Credits, courseIn.Description, courseIn.Level, courseIn.Active, courseIn.DepartmentID, courseIn.Capacity, 88},
+			mockReturn:     pgxmock.NewResult("UPDATE", 0),
 			mockReturnErr:  nil,
 			inputID:        88,
 			inputCourse:    courseIn,
@@ -135,8 +179,10 @@ func (s *testSuit) TestUpdateCourse() {
 		t.Run(name, func(t *testing.T) {
 
 			exp := `UPDATE course 
-			SET name = $1 
-			WHERE id = $2`
+			SET code = $1, name = $2, credits = $3, description = $4, level = $5, active = $6,
+			department_id = $7, capacity = $8
+			WHERE id = $9`
+			s.dbMock.ExpectBegin()
 			mock := s.dbMock.ExpectExec(regexp.QuoteMeta(exp)).
 				WithArgs(tc.mockInputArgs...)
 
@@ -145,9 +191,27 @@ func (s *testSuit) TestUpdateCourse() {
 			} else {
 				mock.WillReturnResult(tc.mockReturn)
 			}
+			if tc.mockSections == nil {
+				s.dbMock.ExpectRollback()
+			} else {
+				s.dbMock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+					WithArgs(tc.inputID).
+					WillReturnRows(tc.mockSections)
+				if tc.mockPromoted != nil {
+					s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+						WithArgs([]int{1, 4}).
+						WillReturnRows(tc.mockPromoted)
+				}
+				for _, payload := range tc.expectedNotified {
+					s.dbMock.ExpectExec(regexp.QuoteMeta(notifyQuery)).
+						WithArgs(WaitlistPromotionChannel, payload).
+						WillReturnResult(pgxmock.NewResult("SELECT", 1))
+				}
+				s.dbMock.ExpectCommit()
+			}
 
 			// Call the actual UpdateCourse function
-			actualReturn, err := s.service.UpdateCourse(context.Background(), tc.inputID, tc.inputCourse.Name)
+			actualReturn, err := s.service.UpdateCourse(context.Background(), tc.inputID, tc.inputCourse)
 
 			assert.Equal(t, tc.expectedError, err)
 			assert.Equal(t, tc.expectedReturn, actualReturn)
@@ -161,34 +225,34 @@ func (s *testSuit) TestUpdateCourse() {
 func (s *testSuit) TestGetCourseByID() {
 	t := s.T()
 
-	course := models.Course{ID: 1, Name: "Databases"}
+	course := models.Course{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true}
 
 	testCases := map[string]struct {
-		mockInputArgs  []driver.Value
-		mockRows       *sqlmock.Rows
+		mockInputArgs  []any
+		mockRows       *pgxmock.Rows
 		mockReturnErr  error
 		inputID        int
 		expectedReturn models.Course
 		expectedError  error
 	}{
 		"course found by ID": {
-			mockInputArgs:  []driver.Value{course.ID},
-			mockRows:       sqlmock.NewRows([]string{"id", "name"}).AddRow(course.ID, course.Name),
+			mockInputArgs:  []any{course.ID},
+			mockRows:       testutil.MustStructsToRows([]models.Course{course}),
 			mockReturnErr:  nil,
 			inputID:        course.ID,
 			expectedReturn: course,
 			expectedError:  nil,
 		},
 		"course not found": {
-			mockInputArgs:  []driver.Value{999},
-			mockRows:       sqlmock.NewRows([]string{"id", "name"}),
-			mockReturnErr:  sql.ErrNoRows,
+			mockInputArgs:  []any{999},
+			mockRows:       testutil.MustStructToEmptyRow(course),
+			mockReturnErr:  pgx.ErrNoRows,
 			inputID:        999,
 			expectedReturn: models.Course{},
-			expectedError:  fmt.Errorf("[in services.GetCourseByIDByID] no course found with id: %d", 999),
+			expectedError:  fmt.Errorf("[in services.GetCourseByIDByID] no course found with id %d: %w", 999, models.ErrNotFound),
 		},
 		"Error retrieving course": {
-			mockInputArgs:  []driver.Value{5},
+			mockInputArgs:  []any{5},
 			mockRows:       nil,
 			mockReturnErr:  errors.New("test error"),
 			inputID:        5,
@@ -199,7 +263,7 @@ func (s *testSuit) TestGetCourseByID() {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			exp := `SELECT id, name FROM course WHERE id = $1`
+			exp := `SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course WHERE id = $1`
 			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(exp)).
 				WithArgs(tc.mockInputArgs...)
 
@@ -220,42 +284,115 @@ func (s *testSuit) TestGetCourseByID() {
 	}
 }
 
+func (s *testSuit) TestGetCourseByCode() {
+	t := s.T()
+
+	course := models.Course{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true}
+
+	testCases := map[string]struct {
+		inputCode      string
+		mockRows       *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Course
+		expectedError  error
+	}{
+		"course found by code in any case": {
+			inputCode:      "cs220",
+			mockRows:       testutil.MustStructsToRows([]models.Course{course}),
+			expectedReturn: course,
+		},
+		"course not found": {
+			inputCode:     "CS999",
+			mockReturnErr: pgx.ErrNoRows,
+			expectedError: fmt.Errorf("[in services.GetCourseByCode] no course found with code %s: %w", "CS999", models.ErrNotFound),
+		},
+		"Error retrieving course": {
+			inputCode:     "CS220",
+			mockReturnErr: errors.New("test error"),
+			expectedError: fmt.Errorf("[in services.GetCourseByCode] failed to retrieve course: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			exp := `SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course WHERE upper(code) = upper($1)`
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(exp)).
+				WithArgs(tc.inputCode)
+
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockRows)
+			}
+
+			actualReturn, err := s.service.GetCourseByCode(context.Background(), tc.inputCode)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func (s *testSuit) TestCreateCourse() {
 	t := s.T()
 
-	courseName := "Databases"
-	newID := 1
+	course := models.Course{Code: "CS220", Name: "Databases", Credits: 3, Description: "SQL", Level: "undergraduate", Active: true}
+	created := course
+	created.ID = 1
+	insertArgs := []any{course.Code, course.Name, course.Credits, course.Description, course.Level, course.Active,
+		course.DepartmentID, course.Capacity}
 
 	testCases := map[string]struct {
-		mockInputArgs  []driver.Value
-		mockRows       *sqlmock.Rows
+		mockInputArgs  []any
+		mockRows       *pgxmock.Rows
 		mockReturnErr  error
-		inputCourse    string
+		inputCourse    models.Course
 		expectedReturn models.Course
 		expectedError  error
 	}{
 		"course created successfully": {
-			mockInputArgs:  []driver.Value{courseName},
-			mockRows:       sqlmock.NewRows([]string{"id"}).AddRow(newID), // Simulate returned new ID
+			mockInputArgs:  insertArgs,
+			mockRows:       pgxmock.NewRows([]string{"id"}).AddRow(created.ID), // Simulate returned new ID
 			mockReturnErr:  nil,
-			inputCourse:    courseName,
-			expectedReturn: models.Course{ID: newID, Name: courseName},
+			inputCourse:    course,
+			expectedReturn: created,
 			expectedError:  nil,
 		},
 		"error creating course": {
-			mockInputArgs:  []driver.Value{courseName},
+			mockInputArgs:  insertArgs,
 			mockRows:       nil,
 			mockReturnErr:  errors.New("test error"),
-			inputCourse:    courseName,
+			inputCourse:    course,
 			expectedReturn: models.Course{},
 			expectedError:  fmt.Errorf("failed to create course: %w", errors.New("test error")),
 		},
+		"code taken": {
+			mockInputArgs:  insertArgs,
+			mockRows:       nil,
+			mockReturnErr:  &pgconn.PgError{Code: "23505", ConstraintName: "course_code"},
+			inputCourse:    course,
+			expectedReturn: models.Course{},
+			expectedError:  fmt.Errorf("failed to create course: %w", models.ErrCourseCodeTaken),
+		},
+		"department missing": {
+			mockInputArgs:  insertArgs,
+			mockRows:       nil,
+			mockReturnErr:  &pgconn.PgError{Code: "23503", ConstraintName: "course_department"},
+			inputCourse:    course,
+			expectedReturn: models.Course{},
+			expectedError:  fmt.Errorf("failed to create course: %w", models.ErrDepartmentNotFound),
+		},
 	}
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 
-			exp := `INSERT INTO course (name) VALUES ($1) RETURNING id`
+			exp := `INSERT INTO course (code, name, credits, description, level, active, department_id, capacity)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			RETURNING id`
 			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(exp)).
 				WithArgs(tc.mockInputArgs...)
 
@@ -282,29 +419,29 @@ func (s *testSuit) TestDeleteCourse() {
 	t := s.T()
 
 	testCases := map[string]struct {
-		mockInputArgs []driver.Value
-		mockReturn    driver.Result
+		mockInputArgs []any
+		mockReturn    pgconn.CommandTag
 		mockReturnErr error
 		inputID       int
 		expectedError error
 	}{
 		"course deleted successfully": {
-			mockInputArgs: []driver.Value{1},
-			mockReturn:    sqlmock.NewResult(0, 1),
+			mockInputArgs: []any{1},
+			mockReturn:    pgxmock.NewResult("DELETE", 1),
 			mockReturnErr: nil,
 			inputID:       1,
 			expectedError: nil,
 		},
 		"no course found with given ID": {
-			mockInputArgs: []driver.Value{999},
-			mockReturn:    sqlmock.NewResult(0, 0),
+			mockInputArgs: []any{999},
+			mockReturn:    pgxmock.NewResult("DELETE", 0),
 			mockReturnErr: nil,
 			inputID:       999,
 			expectedError: fmt.Errorf("[in services.DeleteCourse] no course found with id %d", 999),
 		},
 		"error executing delete": {
-			mockInputArgs: []driver.Value{1},
-			mockReturn:    nil,
+			mockInputArgs: []any{1},
+			mockReturn:    pgconn.CommandTag{},
 			mockReturnErr: errors.New("test error"),
 			inputID:       1,
 			expectedError: fmt.Errorf("[in services.DeleteCourse] failed to delete course: %w", errors.New("test error")),
@@ -332,3 +469,74 @@ func (s *testSuit) TestDeleteCourse() {
 		})
 	}
 }
+
+func TestCourseServiceStatementTimeout(t *testing.T) {
+	testCases := map[string]struct {
+		timeout       time.Duration
+		delay         time.Duration
+		expectedError error
+	}{
+		"statement finishes within timeout": {
+			timeout: time.Second,
+		},
+		"statement cancelled after timeout": {
+			timeout:       10 * time.Millisecond,
+			delay:         time.Second,
+			expectedError: context.DeadlineExceeded,
+		},
+		"no timeout": {
+			delay: 20 * time.Millisecond,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			service := NewCourseService(mock, WithStatementTimeout(tc.timeout))
+
+			mock.ExpectQuery(regexp.QuoteMeta("SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course WHERE id = $1")).
+				WithArgs(1).
+				WillReturnRows(testutil.MustStructsToRows([]models.Course{{ID: 1, Code: "CS220", Name: "Databases"}})).
+				WillDelayFor(tc.delay)
+
+			_, err = service.GetCourseByID(context.Background(), 1)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFilterCourses(t *testing.T) {
+	programming := models.Course{ID: 1, Code: "CS101", Credits: 4, Level: "undergraduate", Active: true}
+	compilers := models.Course{ID: 2, Code: "CS410", Credits: 3, Level: "graduate", Active: true}
+	design := models.Course{ID: 3, Code: "DES310", Credits: 3, Level: "undergraduate"}
+	courses := []models.Course{programming, compilers, design}
+	active, inactive := true, false
+
+	tests := map[string]struct {
+		filter   models.CourseFilter
+		expected []models.Course
+	}{
+		"no filter":         {filter: models.CourseFilter{}, expected: courses},
+		"level":             {filter: models.CourseFilter{Level: "graduate"}, expected: []models.Course{compilers}},
+		"active":            {filter: models.CourseFilter{Active: &active}, expected: []models.Course{programming, compilers}},
+		"inactive":          {filter: models.CourseFilter{Active: &inactive}, expected: []models.Course{design}},
+		"credit range":      {filter: models.CourseFilter{MinCredits: 2, MaxCredits: 3}, expected: []models.Course{compilers, design}},
+		"code prefix":       {filter: models.CourseFilter{CodePrefix: "cs"}, expected: []models.Course{programming, compilers}},
+		"filters combine":   {filter: models.CourseFilter{Level: "undergraduate", MaxCredits: 3}, expected: []models.Course{design}},
+		"nothing matches":   {filter: models.CourseFilter{CodePrefix: "MATH"}, expected: []models.Course{}},
+		"minimum only":      {filter: models.CourseFilter{MinCredits: 4}, expected: []models.Course{programming}},
+		"exact code prefix": {filter: models.CourseFilter{CodePrefix: "CS410"}, expected: []models.Course{compilers}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, filterCourses(courses, tc.filter))
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DB is the part of *pgxpool.Pool that CourseService, PersonService, DepartmentService and
+// TermService use. Tests substitute a pgxmock pool.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Replica is a read replica: a DB that only serves reads and can be health-checked.
+type Replica interface {
+	DB
+	Ping(ctx context.Context) error
+}
+
+// Pool is a DB that can also be pinged and report connection statistics, as HealthService needs.
+type Pool interface {
+	DB
+	Ping(ctx context.Context) error
+	Stat() *pgxpool.Stat
+}
+
+// conn returns the transaction shared through ctx by database.BeginShared, if any, and db
+// otherwise. pgx.Tx is itself a DB, so a service that begins a transaction on it gets a savepoint.
+func conn(ctx context.Context, db DB) DB {
+	if tx, ok := database.SharedTxFrom(ctx); ok {
+		return tx
+	}
+	return db
+}
+
+// constraintErrors maps the unique indexes and foreign keys on department, person, course, term,
+// course_section and enrollment to the error reported when a write would violate them.
+var constraintErrors = map[string]error{
+	"department_name":       models.ErrDepartmentNameTaken,
+	"person_external_id":    models.ErrExternalIDTaken,
+	"person_email":          models.ErrEmailTaken,
+	"person_department":     models.ErrDepartmentNotFound,
+	"course_code":           models.ErrCourseCodeTaken,
+	"course_department":     models.ErrDepartmentNotFound,
+	"term_name":             models.ErrTermNameTaken,
+	"course_section_number": models.ErrSectionTaken,
+	"course_section_term":   models.ErrTermNotFound,
+	"enrollment_section":    models.ErrSectionNotFound,
+}
+
+// constraintError translates a unique or foreign key violation of one of constraintErrors into the
+// matching error from models, leaving any other error as it is.
+func constraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == "23505" || pgErr.Code == "23503") {
+		if mapped, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return mapped
+		}
+	}
+	return err
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type DepartmentService struct {
+	database         DB
+	reads            reader
+	cache            *cache.Aside
+	statementTimeout time.Duration
+}
+
+func NewDepartmentService(db DB, opts ...Option) *DepartmentService {
+	options := newServiceOptions(opts)
+	return &DepartmentService{
+		database:         db,
+		reads:            reader{primary: db, replicas: options.replicas},
+		cache:            options.cache,
+		statementTimeout: options.statementTimeout,
+	}
+}
+
+func (s *DepartmentService) ListDepartments(ctx context.Context) ([]models.Department, error) {
+	return cache.Load(ctx, s.cache, departmentListKey, s.listDepartments)
+}
+
+func (s *DepartmentService) listDepartments(ctx context.Context) ([]models.Department, error) {
+	query := `SELECT id, name FROM department ORDER BY id asc`
+	ctx, span := startQuerySpan(ctx, "DepartmentService.ListDepartments", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(ctx, query)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Department{}, fmt.Errorf("[in services.ListDepartments] failed to get departments: %w", err)
+	}
+	departments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Department, error) {
+		var department models.Department
+		err := row.Scan(&department.ID, &department.Name)
+		return department, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Department{}, fmt.Errorf("[in services.ListDepartments] failed to scan departments: %w", err)
+	}
+	setRowsReturned(span, len(departments))
+	logging.Subsystem(ctx, logging.SubsystemServices).Debug("listed departments", "count", len(departments))
+
+	return departments, nil
+}
+
+// GetDepartmentByID returns the department with the given ID. It wraps models.ErrNotFound when
+// there is none.
+func (s *DepartmentService) GetDepartmentByID(ctx context.Context, id int) (models.Department, error) {
+	return cache.Load(ctx, s.cache, departmentKey(id), func(ctx context.Context) (models.Department, error) {
+		return s.getDepartmentByID(ctx, id)
+	})
+}
+
+func (s *DepartmentService) getDepartmentByID(ctx context.Context, id int) (models.Department, error) {
+	var department models.Department
+	query := "SELECT id, name FROM department WHERE id = $1"
+	ctx, span := startQuerySpan(ctx, "DepartmentService.GetDepartmentByID", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := s.reads.QueryRow(ctx, query, id).Scan(&department.ID, &department.Name)
+	if err != nil {
+		tracing.RecordError(span, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Department{}, fmt.Errorf("[in services.GetDepartmentByID] no department found with id %d: %w", id, models.ErrNotFound)
+		}
+		return models.Department{}, fmt.Errorf("[in services.GetDepartmentByID] failed to retrieve department: %w", err)
+	}
+
+	return department, nil
+}
+
+func (s *DepartmentService) CreateDepartment(ctx context.Context, department models.Department) (models.Department, error) {
+	query := `INSERT INTO department (name) VALUES ($1) RETURNING id`
+	ctx, span := startQuerySpan(ctx, "DepartmentService.CreateDepartment", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := conn(ctx, s.database).QueryRow(ctx, query, department.Name).Scan(&department.ID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Department{}, fmt.Errorf("[in services.CreateDepartment] failed to create department: %w", constraintError(err))
+	}
+	s.cache.Invalidate(ctx, departmentKeyPrefix)
+	return department, nil
+}
+
+// UpdateDepartment renames the department with the given ID. It wraps models.ErrNotFound when
+// there is none.
+func (s *DepartmentService) UpdateDepartment(ctx context.Context, id int, department models.Department) (models.Department, error) {
+	query := `UPDATE department SET name = $1 WHERE id = $2`
+	ctx, span := startQuerySpan(ctx, "DepartmentService.UpdateDepartment", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := conn(ctx, s.database).Exec(ctx, query, department.Name, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Department{}, fmt.Errorf("[in services.UpdateDepartment] failed to update department: %w", constraintError(err))
+	}
+
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(span, rowsAffected)
+	if rowsAffected == 0 {
+		return models.Department{}, fmt.Errorf("[in services.UpdateDepartment] no department found with id %d: %w", id, models.ErrNotFound)
+	}
+	s.cache.Invalidate(ctx, departmentKeyPrefix)
+
+	department.ID = id
+	return department, nil
+}
+
+// DeleteDepartment deletes the department with the given ID, leaving its courses and professors
+// without a department. It wraps models.ErrNotFound when there is none.
+func (s *DepartmentService) DeleteDepartment(ctx context.Context, id int) error {
+	query := `DELETE FROM department WHERE id = $1`
+	ctx, span := startQuerySpan(ctx, "DepartmentService.DeleteDepartment", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := conn(ctx, s.database).Exec(ctx, query, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.DeleteDepartment] failed to delete department: %w", err)
+	}
+
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(span, rowsAffected)
+	if rowsAffected == 0 {
+		return fmt.Errorf("[in services.DeleteDepartment] no department found with id %d: %w", id, models.ErrNotFound)
+	}
+	// The foreign keys cleared the department of every course and person that was in it.
+	s.cache.Invalidate(ctx, departmentKeyPrefix, courseKeyPrefix, personKeyPrefix)
+
+	return nil
+}
+
+// ListDepartmentCourses returns the courses in the department with the given ID, by ID. It wraps
+// models.ErrNotFound when there is no such department.
+func (s *DepartmentService) ListDepartmentCourses(ctx context.Context, id int) ([]models.Course, error) {
+	if _, err := s.GetDepartmentByID(ctx, id); err != nil {
+		return []models.Course{}, fmt.Errorf("[in services.ListDepartmentCourses] %w", err)
+	}
+
+	query := `SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course
+	WHERE department_id = $1
+	ORDER BY id asc`
+	ctx, span := startQuerySpan(ctx, "DepartmentService.ListDepartmentCourses", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(ctx, query, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Course{}, fmt.Errorf("[in services.ListDepartmentCourses] failed to get courses: %w", err)
+	}
+	courses, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Course, error) {
+		var course models.Course
+		err := row.Scan(courseFields(&course)...)
+		return course, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Course{}, fmt.Errorf("[in services.ListDepartmentCourses] failed to scan courses: %w", err)
+	}
+	setRowsReturned(span, len(courses))
+
+	return courses, nil
+}
+
+// ListDepartmentProfessors returns the professors in the department with the given ID, by ID. It
+// wraps models.ErrNotFound when there is no such department.
+func (s *DepartmentService) ListDepartmentProfessors(ctx context.Context, id int) ([]models.Person, error) {
+	if _, err := s.GetDepartmentByID(ctx, id); err != nil {
+		return []models.Person{}, fmt.Errorf("[in services.ListDepartmentProfessors] %w", err)
+	}
+
+	query := selectPersons + `
+	WHERE p.department_id = $1 AND p.type = 'professor'
+	GROUP BY p.id
+	ORDER BY person_id asc`
+	ctx, span := startQuerySpan(ctx, "DepartmentService.ListDepartmentProfessors", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(ctx, query, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Person{}, fmt.Errorf("[in services.ListDepartmentProfessors] failed to get professors: %w", err)
+	}
+	professors, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Person, error) {
+		var professor models.Person
+		err := row.Scan(personRowFields(&professor)...)
+		return professor, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Person{}, fmt.Errorf("[in services.ListDepartmentProfessors] failed to scan professors: %w", err)
+	}
+	setRowsReturned(span, len(professors))
+
+	return professors, nil
+}
@@ -0,0 +1,352 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type departmentTestSuite struct {
+	suite.Suite
+	service *DepartmentService
+	dbMock  pgxmock.PgxPoolIface
+}
+
+func TestDepartmentService(t *testing.T) {
+	suite.Run(t, new(departmentTestSuite))
+}
+
+func (s *departmentTestSuite) SetupSuite() {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(s.T(), err)
+
+	s.dbMock = mock
+	s.service = NewDepartmentService(mock)
+}
+
+func (s *departmentTestSuite) TearDownSuite() {
+	err := s.dbMock.ExpectationsWereMet()
+	assert.NoError(s.T(), err)
+}
+
+func (s *departmentTestSuite) TestListDepartments() {
+	t := s.T()
+
+	departments := []models.Department{{ID: 1, Name: "Computer Science"}, {ID: 2, Name: "Design"}}
+
+	testCases := map[string]struct {
+		mockReturn     *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn []models.Department
+		expectedError  error
+	}{
+		"Return slice of departments": {
+			mockReturn:     testutil.MustStructsToRows(departments),
+			expectedReturn: departments,
+		},
+		"Error getting departments": {
+			mockReturnErr:  errors.New("test"),
+			expectedReturn: []models.Department{},
+			expectedError:  fmt.Errorf("[in services.ListDepartments] failed to get departments: %w", errors.New("test")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name FROM department ORDER BY id asc`))
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockReturn)
+			}
+
+			actualReturn, err := s.service.ListDepartments(context.Background())
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *departmentTestSuite) TestGetDepartmentByID() {
+	t := s.T()
+
+	department := models.Department{ID: 1, Name: "Computer Science"}
+
+	testCases := map[string]struct {
+		inputID        int
+		mockRows       *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Department
+		expectedError  error
+	}{
+		"department found": {
+			inputID:        1,
+			mockRows:       testutil.MustStructsToRows([]models.Department{department}),
+			expectedReturn: department,
+		},
+		"department not found": {
+			inputID:       9,
+			mockReturnErr: pgx.ErrNoRows,
+			expectedError: fmt.Errorf("[in services.GetDepartmentByID] no department found with id %d: %w", 9, models.ErrNotFound),
+		},
+		"Error retrieving department": {
+			inputID:       1,
+			mockReturnErr: errors.New("test error"),
+			expectedError: fmt.Errorf("[in services.GetDepartmentByID] failed to retrieve department: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name FROM department WHERE id = $1`)).
+				WithArgs(tc.inputID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockRows)
+			}
+
+			actualReturn, err := s.service.GetDepartmentByID(context.Background(), tc.inputID)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *departmentTestSuite) TestCreateDepartment() {
+	t := s.T()
+
+	department := models.Department{Name: "Mathematics"}
+	created := department
+	created.ID = 3
+
+	testCases := map[string]struct {
+		mockRows       *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Department
+		expectedError  error
+	}{
+		"department created successfully": {
+			mockRows:       pgxmock.NewRows([]string{"id"}).AddRow(created.ID),
+			expectedReturn: created,
+		},
+		"name taken": {
+			mockReturnErr: &pgconn.PgError{Code: "23505", ConstraintName: "department_name"},
+			expectedError: fmt.Errorf("[in services.CreateDepartment] failed to create department: %w", models.ErrDepartmentNameTaken),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO department (name) VALUES ($1) RETURNING id`)).
+				WithArgs(department.Name)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockRows)
+			}
+
+			actualReturn, err := s.service.CreateDepartment(context.Background(), department)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *departmentTestSuite) TestUpdateDepartment() {
+	t := s.T()
+
+	department := models.Department{Name: "Applied Design"}
+
+	testCases := map[string]struct {
+		inputID        int
+		mockReturn     pgconn.CommandTag
+		mockReturnErr  error
+		expectedReturn models.Department
+		expectedError  error
+	}{
+		"department updated successfully": {
+			inputID:        2,
+			mockReturn:     pgxmock.NewResult("UPDATE", 1),
+			expectedReturn: models.Department{ID: 2, Name: "Applied Design"},
+		},
+		"department not found": {
+			inputID:       9,
+			mockReturn:    pgxmock.NewResult("UPDATE", 0),
+			expectedError: fmt.Errorf("[in services.UpdateDepartment] no department found with id %d: %w", 9, models.ErrNotFound),
+		},
+		"name taken": {
+			inputID:       2,
+			mockReturnErr: &pgconn.PgError{Code: "23505", ConstraintName: "department_name"},
+			expectedError: fmt.Errorf("[in services.UpdateDepartment] failed to update department: %w", models.ErrDepartmentNameTaken),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE department SET name = $1 WHERE id = $2`)).
+				WithArgs(department.Name, tc.inputID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnResult(tc.mockReturn)
+			}
+
+			actualReturn, err := s.service.UpdateDepartment(context.Background(), tc.inputID, department)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *departmentTestSuite) TestDeleteDepartment() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		inputID       int
+		mockReturn    pgconn.CommandTag
+		mockReturnErr error
+		expectedError error
+	}{
+		"department deleted successfully": {
+			inputID:    2,
+			mockReturn: pgxmock.NewResult("DELETE", 1),
+		},
+		"department not found": {
+			inputID:       9,
+			mockReturn:    pgxmock.NewResult("DELETE", 0),
+			expectedError: fmt.Errorf("[in services.DeleteDepartment] no department found with id %d: %w", 9, models.ErrNotFound),
+		},
+		"Error deleting department": {
+			inputID:       2,
+			mockReturnErr: errors.New("test error"),
+			expectedError: fmt.Errorf("[in services.DeleteDepartment] failed to delete department: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM department WHERE id = $1`)).
+				WithArgs(tc.inputID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnResult(tc.mockReturn)
+			}
+
+			err := s.service.DeleteDepartment(context.Background(), tc.inputID)
+
+			assert.Equal(t, tc.expectedError, err)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *departmentTestSuite) TestListDepartmentCourses() {
+	t := s.T()
+
+	departmentID := 1
+	courses := []models.Course{
+		{ID: 1, Code: "CS101", Name: "Programming", Credits: 4, Level: "undergraduate", Active: true, DepartmentID: &departmentID},
+		{ID: 2, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true, DepartmentID: &departmentID},
+	}
+	getQuery := regexp.QuoteMeta(`SELECT id, name FROM department WHERE id = $1`)
+	coursesQuery := regexp.QuoteMeta(`FROM course WHERE department_id = $1`)
+
+	t.Run("courses listed", func(t *testing.T) {
+		s.dbMock.ExpectQuery(getQuery).WithArgs(departmentID).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(departmentID, "Computer Science"))
+		s.dbMock.ExpectQuery(coursesQuery).WithArgs(departmentID).
+			WillReturnRows(testutil.MustStructsToRows(courses))
+
+		actualReturn, err := s.service.ListDepartmentCourses(context.Background(), departmentID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, courses, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("department not found", func(t *testing.T) {
+		s.dbMock.ExpectQuery(getQuery).WithArgs(9).WillReturnError(pgx.ErrNoRows)
+
+		actualReturn, err := s.service.ListDepartmentCourses(context.Background(), 9)
+
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Equal(t, []models.Course{}, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
+
+func (s *departmentTestSuite) TestListDepartmentProfessors() {
+	t := s.T()
+
+	departmentID := 1
+	professor := models.Person{ID: 2, FirstName: "Jeff", LastName: "Bezos", Type: "professor", Age: 60,
+		DepartmentID: &departmentID, Sections: []int{1, 2}, Courses: []int{1, 2}, Waitlisted: []int{}}
+	getQuery := regexp.QuoteMeta(`SELECT id, name FROM department WHERE id = $1`)
+	professorsQuery := regexp.QuoteMeta(`WHERE p.department_id = $1 AND p.type = 'professor'`)
+	columns := []string{"person_id", "first_name", "last_name", "type", "age", "external_id", "email", "phone",
+		"date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}
+
+	t.Run("professors listed", func(t *testing.T) {
+		s.dbMock.ExpectQuery(getQuery).WithArgs(departmentID).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "name"}).AddRow(departmentID, "Computer Science"))
+		s.dbMock.ExpectQuery(professorsQuery).WithArgs(departmentID).
+			WillReturnRows(pgxmock.NewRows(columns).
+				AddRow(2, "Jeff", "Bezos", "professor", 60, "", "", "", nil, time.Time{}, time.Time{}, &departmentID, []int{1, 2}, []int{1, 2}, []int{}))
+
+		actualReturn, err := s.service.ListDepartmentProfessors(context.Background(), departmentID)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []models.Person{professor}, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("department not found", func(t *testing.T) {
+		s.dbMock.ExpectQuery(getQuery).WithArgs(9).WillReturnError(pgx.ErrNoRows)
+
+		actualReturn, err := s.service.ListDepartmentProfessors(context.Background(), 9)
+
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Equal(t, []models.Person{}, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// duplicateAgeTolerance is how far apart the ages of two persons with the same name may be for
+// them to be taken as the same person, allowing for a birthday between records.
+const duplicateAgeTolerance = 1
+
+// normalizedName reduces a person's name to the lower-case letters and digits of their first and
+// last names, so that "Bill Gates", "bill  gates" and "Bill-Gates" all compare equal. It matches
+// the normalization findDuplicates applies in SQL.
+func normalizedName(firstName string, lastName string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(firstName + lastName) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// findDuplicates returns the persons who look like person: the same normalized name and an age
+// within duplicateAgeTolerance. Persons with a different external ID, email address or date of
+// birth are not duplicates, since they are known to be someone else. Creations of persons with the
+// same normalized name are serialized until the transaction ends, so that two concurrent requests
+// cannot both find nothing and both insert.
+func (s *PersonService) findDuplicates(ctx context.Context, tx pgx.Tx, person models.Person) ([]models.Person, error) {
+	name := normalizedName(person.FirstName, person.LastName)
+	lockQuery := `SELECT pg_advisory_xact_lock(hashtext($1))`
+	if err := s.exec(ctx, tx, "lock person name", lockQuery, "person:"+name); err != nil {
+		return nil, err
+	}
+
+	query := selectPersons + `
+	WHERE regexp_replace(lower(p.first_name || p.last_name), '[^[:alnum:]]', '', 'g') = $1
+	AND COALESCE(date_part('year', age(p.date_of_birth))::int, p.age) BETWEEN $2 AND $3
+	AND ($4::text = '' OR p.external_id IS NULL OR p.external_id = $4)
+	AND ($5::text = '' OR p.email IS NULL OR lower(p.email) = lower($5))
+	AND ($6::date IS NULL OR p.date_of_birth IS NULL OR p.date_of_birth = $6)
+	GROUP BY p.id
+	ORDER BY p.id`
+	ctx, span := startQuerySpan(ctx, "find duplicate persons", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, name, person.Age-duplicateAgeTolerance, person.Age+duplicateAgeTolerance,
+		person.ExternalID, person.Email, person.DateOfBirth)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	candidates, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Person, error) {
+		var candidate models.Person
+		err := row.Scan(personRowFields(&candidate)...)
+		return candidate, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsReturned(span, len(candidates))
+	return candidates, nil
+}
+
+// MergePersons folds the duplicate into the survivor: the survivor is enrolled in every section the
+// duplicate was, or waitlisted where the section is full, takes the duplicate's waitlist places,
+// takes the duplicate's external ID, email address, phone number, date of birth and department where
+// it has none of its own, and the duplicate is deleted. The survivor's other details are kept. It
+// returns the survivor as merged. Both persons must be of the same type; it wraps
+// models.ErrPersonTypesDiffer when they are not.
+func (s *PersonService) MergePersons(ctx context.Context, survivorID int, duplicateID int) (models.Person, error) {
+	ctx, span := tracer.Start(ctx, "PersonService.MergePersons")
+	defer span.End()
+
+	tx, err := conn(ctx, s.database).Begin(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Person{}, fmt.Errorf("[in services.MergePersons] failed to begin transaction: %w", err)
+	}
+
+	// Both rows are locked in ID order, so that concurrent merges of the same pair cannot deadlock.
+	persons, err := s.lockPersons(ctx, tx, survivorID, duplicateID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.MergePersons] failed to lock persons: %w", err)
+	}
+	for _, id := range []int{survivorID, duplicateID} {
+		if _, ok := persons[id]; !ok {
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.MergePersons] no person found with ID %d: %w", id, models.ErrNotFound)
+		}
+	}
+	duplicate := persons[duplicateID]
+	// A student cannot teach the duplicate's sections, and a professor would take seats that only
+	// students are given.
+	if survivorType := persons[survivorID].Type; survivorType != duplicate.Type {
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.MergePersons] cannot merge a %s into a %s: %w",
+			duplicate.Type, survivorType, models.ErrPersonTypesDiffer)
+	}
+
+	if err := s.moveEnrollments(ctx, tx, persons[survivorID], duplicateID); err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.MergePersons] %w", err)
+	}
+
+	// The duplicate is deleted before its details are copied, since they are unique.
+	statements := []struct {
+		name  string
+		query string
+		args  []any
+	}{
+		{
+			name:  "move taught sections",
+			query: `UPDATE course_section SET instructor_id = $1 WHERE instructor_id = $2`,
+			args:  []any{survivorID, duplicateID},
+		},
+		{name: "delete duplicate", query: `DELETE FROM person WHERE id = $1`, args: []any{duplicateID}},
+		{
+			name: "copy details",
+			query: `UPDATE person SET external_id = COALESCE(external_id, NULLIF($2, '')),
+			email = COALESCE(email, NULLIF($3, '')),
+			phone = COALESCE(phone, NULLIF($4, '')),
+			date_of_birth = COALESCE(date_of_birth, $5),
+			department_id = COALESCE(department_id, $6),
+			updated_at = now()
+			WHERE id = $1`,
+			args: []any{survivorID, duplicate.ExternalID, duplicate.Email, duplicate.Phone, duplicate.DateOfBirth,
+				duplicate.DepartmentID},
+		},
+	}
+	for _, statement := range statements {
+		if err := s.exec(ctx, tx, statement.name, statement.query, statement.args...); err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.MergePersons] failed to %s: %w", statement.name, err)
+		}
+	}
+
+	survivor, err := s.getPersonByID(ctx, tx, survivorID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.MergePersons] failed to retrieve merged person: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Person{}, fmt.Errorf("[in services.MergePersons] failed to commit transaction: %w", err)
+	}
+	// The sections the duplicate taught are now the survivor's.
+	s.cache.Invalidate(ctx, personKeyPrefix, courseKeyPrefix)
+
+	return survivor, nil
+}
+
+// moveEnrollments gives the survivor the duplicate's enrollments and waitlist places. The survivor
+// is seated in the duplicate's sections like any other enrollment, taking the seats the duplicate
+// gives up. Where both wait for a section, the earlier place is kept, and the seats freed where both
+// were enrolled go to the students waiting.
+func (s *PersonService) moveEnrollments(ctx context.Context, tx pgx.Tx, survivor models.Person, duplicateID int) error {
+	deleteEnrollmentsQuery := `DELETE FROM enrollment WHERE person_id = $1 RETURNING section_id`
+	dropped, err := s.deleteEnrollments(ctx, tx, deleteEnrollmentsQuery, duplicateID)
+	if err != nil {
+		return fmt.Errorf("failed to delete duplicate enrollments: %w", err)
+	}
+	held, _, err := s.selectEnrollments(ctx, tx, survivor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve enrollments: %w", err)
+	}
+	if err := s.lockSections(ctx, tx, append(slices.Clone(held), dropped...)); err != nil {
+		return fmt.Errorf("failed to lock sections: %w", err)
+	}
+
+	mergeWaitlistsQuery := `DELETE FROM waitlist w USING waitlist earlier
+	WHERE w.section_id = earlier.section_id AND w.id > earlier.id
+	AND w.person_id = ANY($1) AND earlier.person_id = ANY($1)`
+	if err := s.exec(ctx, tx, "merge waitlists", mergeWaitlistsQuery, []int{survivor.ID, duplicateID}); err != nil {
+		return fmt.Errorf("failed to merge waitlists: %w", err)
+	}
+	moveWaitlistsQuery := `UPDATE waitlist SET person_id = $1 WHERE person_id = $2`
+	if err := s.exec(ctx, tx, "move waitlists", moveWaitlistsQuery, survivor.ID, duplicateID); err != nil {
+		return fmt.Errorf("failed to move waitlists: %w", err)
+	}
+
+	survivor.Sections = slices.DeleteFunc(slices.Clone(dropped), func(sectionID int) bool {
+		return slices.Contains(held, sectionID)
+	})
+	if err := s.enroll(ctx, tx, survivor); err != nil {
+		return fmt.Errorf("failed to copy enrollments: %w", err)
+	}
+	leaveWaitlistsQuery := `DELETE FROM waitlist
+	WHERE person_id = $1 AND section_id IN (SELECT section_id FROM enrollment WHERE person_id = $1)`
+	if err := s.exec(ctx, tx, "leave held waitlists", leaveWaitlistsQuery, survivor.ID); err != nil {
+		return fmt.Errorf("failed to leave waitlists: %w", err)
+	}
+
+	if _, err := promoteWaitlisted(ctx, tx, s.statementTimeout, dropped); err != nil {
+		return fmt.Errorf("failed to promote waitlisted students: %w", err)
+	}
+	return nil
+}
+
+// lockPersons locks the rows of the given persons for update, returning the type and unique details
+// of each one that exists, and their department, by ID.
+func (s *PersonService) lockPersons(ctx context.Context, tx pgx.Tx, ids ...int) (map[int]models.Person, error) {
+	query := `SELECT id, type, COALESCE(external_id, ''), COALESCE(email, ''), COALESCE(phone, ''), date_of_birth,
+	department_id
+	FROM person WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	ctx, span := startQuerySpan(ctx, "lock persons", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, ids)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	persons := map[int]models.Person{}
+	for rows.Next() {
+		var person models.Person
+		if err := rows.Scan(&person.ID, &person.Type, &person.ExternalID, &person.Email, &person.Phone, &person.DateOfBirth,
+			&person.DepartmentID); err != nil {
+			tracing.RecordError(span, err)
+			return nil, err
+		}
+		persons[person.ID] = person
+	}
+	if err := rows.Err(); err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsReturned(span, len(persons))
+	return persons, nil
+}
+
+// getPersonByID reads a person and their courses inside a transaction.
+func (s *PersonService) getPersonByID(ctx context.Context, tx pgx.Tx, id int) (models.Person, error) {
+	query := selectPersons + `
+	WHERE p.id = $1
+	GROUP BY p.id`
+	ctx, span := startQuerySpan(ctx, "get person", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	var person models.Person
+	err := tx.QueryRow(ctx, query, id).Scan(personRowFields(&person)...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Person{}, err
+	}
+	return person, nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListPersonEnrollments returns the sections the person with the given last name is enrolled in,
+// with their courses and terms, earliest term first. A non-zero termID keeps only the enrollments
+// in that term. It wraps models.ErrNotFound when there is no such person. Enrollments are read
+// past the cache.
+func (s *PersonService) ListPersonEnrollments(ctx context.Context, lastName string, termID int) ([]models.Enrollment, error) {
+	var personID int
+	personQuery := `SELECT id FROM person WHERE LOWER(last_name) = LOWER($1)`
+	personCtx, personSpan := startQuerySpan(ctx, "PersonService.ListPersonEnrollments person", personQuery)
+	personCtx, cancelPerson := withStatementTimeout(personCtx, s.statementTimeout)
+	defer cancelPerson()
+	err := s.reads.QueryRow(personCtx, personQuery, lastName).Scan(&personID)
+	tracing.RecordError(personSpan, err)
+	personSpan.End()
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []models.Enrollment{}, fmt.Errorf("[in services.ListPersonEnrollments] no person found with name %s: %w", lastName, models.ErrNotFound)
+		}
+		return []models.Enrollment{}, fmt.Errorf("[in services.ListPersonEnrollments] failed to retrieve person: %w", err)
+	}
+
+	query := `SELECT cs.id, cs.course_id, cs.term_id, cs.number, cs.instructor_id, c.code, c.name, t.name
+	FROM enrollment e
+	JOIN course_section cs ON cs.id = e.section_id
+	JOIN course c ON c.id = cs.course_id
+	JOIN term t ON t.id = cs.term_id
+	WHERE e.person_id = $1 AND ($2::int = 0 OR cs.term_id = $2)
+	ORDER BY t.starts_on asc, c.code asc, cs.number asc`
+	ctx, span := startQuerySpan(ctx, "PersonService.ListPersonEnrollments", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(ctx, query, personID, termID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Enrollment{}, fmt.Errorf("[in services.ListPersonEnrollments] failed to get enrollments: %w", err)
+	}
+	enrollments, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Enrollment, error) {
+		var enrollment models.Enrollment
+		err := row.Scan(append(sectionFields(&enrollment.Section), &enrollment.CourseCode, &enrollment.CourseName,
+			&enrollment.TermName)...)
+		return enrollment, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Enrollment{}, fmt.Errorf("[in services.ListPersonEnrollments] failed to scan enrollments: %w", err)
+	}
+	setRowsReturned(span, len(enrollments))
+
+	return enrollments, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *personTestSuite) TestListPersonEnrollments() {
+	t := s.T()
+
+	instructorID := 2
+	enrollments := []models.Enrollment{
+		{Section: models.Section{ID: 1, CourseID: 1, TermID: 1, Number: 1, InstructorID: &instructorID},
+			CourseCode: "CS101", CourseName: "Programming", TermName: "Fall 2026"},
+		{Section: models.Section{ID: 3, CourseID: 3, TermID: 1, Number: 1},
+			CourseCode: "DES310", CourseName: "UI Design", TermName: "Fall 2026"},
+	}
+	personQuery := regexp.QuoteMeta(`SELECT id FROM person WHERE LOWER(last_name) = LOWER($1)`)
+	enrollmentsQuery := regexp.QuoteMeta(`WHERE e.person_id = $1 AND ($2::int = 0 OR cs.term_id = $2)`)
+	columns := []string{"id", "course_id", "term_id", "number", "instructor_id", "code", "name", "name"}
+
+	testCases := map[string]struct {
+		termID int
+	}{
+		"every term": {},
+		"one term":   {termID: 1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s.dbMock.ExpectQuery(personQuery).WithArgs("Page").
+				WillReturnRows(pgxmock.NewRows([]string{"id"}).AddRow(3))
+			s.dbMock.ExpectQuery(enrollmentsQuery).WithArgs(3, tc.termID).
+				WillReturnRows(pgxmock.NewRows(columns).
+					AddRow(1, 1, 1, 1, &instructorID, "CS101", "Programming", "Fall 2026").
+					AddRow(3, 3, 1, 1, nil, "DES310", "UI Design", "Fall 2026"))
+
+			actualReturn, err := s.service.ListPersonEnrollments(context.Background(), "Page", tc.termID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, enrollments, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("person not found", func(t *testing.T) {
+		s.dbMock.ExpectQuery(personQuery).WithArgs("Nobody").WillReturnError(pgx.ErrNoRows)
+
+		actualReturn, err := s.service.ListPersonEnrollments(context.Background(), "Nobody", 0)
+
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Equal(t, []models.Enrollment{}, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
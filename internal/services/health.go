@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// requiredColumns lists the tables created by db_seed.sql and the columns the services use. Any
+// that are missing mean the schema has not been fully applied yet, or is older than the code.
+var requiredColumns = []struct {
+	table   string
+	columns []string
+}{
+	{"department", []string{"id", "name"}},
+	{"person", []string{"id", "first_name", "last_name", "type", "age", "external_id", "email", "phone",
+		"date_of_birth", "created_at", "updated_at", "department_id"}},
+	{"course", []string{"id", "code", "name", "credits", "description", "level", "active", "department_id", "capacity"}},
+	{"term", []string{"id", "name", "starts_on", "ends_on"}},
+	{"course_section", []string{"id", "course_id", "term_id", "number", "instructor_id"}},
+	{"enrollment", []string{"person_id", "section_id"}},
+	{"waitlist", []string{"id", "person_id", "section_id"}},
+	{"idempotency_key", []string{"key", "request_hash", "status", "content_type", "body", "created_at", "expires_at"}},
+}
+
+type HealthService struct {
+	database Pool
+	timeout  time.Duration
+	draining atomic.Bool
+}
+
+// NewHealthService returns a new HealthService that bounds every dependency check by timeout.
+func NewHealthService(db Pool, timeout time.Duration) *HealthService {
+	return &HealthService{
+		database: db,
+		timeout:  timeout,
+	}
+}
+
+// SetDraining marks the service as shutting down so that readiness checks fail and load balancers
+// stop routing new traffic to it.
+func (s *HealthService) SetDraining(draining bool) {
+	s.draining.Store(draining)
+}
+
+// CheckReadiness checks every dependency and reports whether the service can accept traffic.
+func (s *HealthService) CheckReadiness(ctx context.Context) models.Readiness {
+	components := []models.ComponentHealth{
+		s.checkShutdown(),
+		s.check(ctx, "database", s.pingDatabase),
+		s.check(ctx, "migrations", s.checkMigrations),
+	}
+
+	ready := true
+	for _, component := range components {
+		if component.Status != models.HealthStatusUp {
+			ready = false
+		}
+	}
+
+	return models.Readiness{
+		Ready:      ready,
+		Components: components,
+	}
+}
+
+// PoolStats reports a snapshot of the database connection pool.
+func (s *HealthService) PoolStats(ctx context.Context) models.PoolStats {
+	stat := s.database.Stat()
+	return models.PoolStats{
+		TotalConns:              stat.TotalConns(),
+		AcquiredConns:           stat.AcquiredConns(),
+		IdleConns:               stat.IdleConns(),
+		ConstructingConns:       stat.ConstructingConns(),
+		MaxConns:                stat.MaxConns(),
+		AcquireCount:            stat.AcquireCount(),
+		AcquireDuration:         stat.AcquireDuration(),
+		EmptyAcquireCount:       stat.EmptyAcquireCount(),
+		CanceledAcquireCount:    stat.CanceledAcquireCount(),
+		NewConnsCount:           stat.NewConnsCount(),
+		MaxLifetimeDestroyCount: stat.MaxLifetimeDestroyCount(),
+		MaxIdleDestroyCount:     stat.MaxIdleDestroyCount(),
+	}
+}
+
+func (s *HealthService) checkShutdown() models.ComponentHealth {
+	if s.draining.Load() {
+		return models.ComponentHealth{Name: "shutdown", Status: models.HealthStatusDraining}
+	}
+	return models.ComponentHealth{Name: "shutdown", Status: models.HealthStatusUp}
+}
+
+// check runs checkFunc with the configured timeout and records how long it took.
+func (s *HealthService) check(ctx context.Context, name string, checkFunc func(ctx context.Context) error) models.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checkFunc(ctx)
+	component := models.ComponentHealth{
+		Name:    name,
+		Status:  models.HealthStatusUp,
+		Latency: time.Since(start),
+	}
+	if err != nil {
+		component.Status = models.HealthStatusDown
+		component.Error = err.Error()
+	}
+
+	return component
+}
+
+func (s *HealthService) pingDatabase(ctx context.Context) error {
+	if err := s.database.Ping(ctx); err != nil {
+		return fmt.Errorf("[in services.pingDatabase] failed to ping database: %w", err)
+	}
+	return nil
+}
+
+// checkMigrations reports an error naming any required table or column that does not exist yet.
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	var tables, columns []string
+	for _, required := range requiredColumns {
+		for _, column := range required.columns {
+			tables = append(tables, required.table)
+			columns = append(columns, column)
+		}
+	}
+
+	query := `SELECT r.table_name, r.column_name, to_regclass(r.table_name) IS NOT NULL
+	FROM unnest($1::text[], $2::text[]) WITH ORDINALITY AS r(table_name, column_name, position)
+	WHERE NOT EXISTS (SELECT 1 FROM information_schema.columns c
+		WHERE c.table_schema = current_schema() AND c.table_name = r.table_name AND c.column_name = r.column_name)
+	ORDER BY r.position`
+	rows, err := s.database.Query(ctx, query, tables, columns)
+	if err != nil {
+		return fmt.Errorf("[in services.checkMigrations] failed to check schema: %w", err)
+	}
+	defer rows.Close()
+
+	var missingTables, missingColumns []string
+	for rows.Next() {
+		var table, column string
+		var tableExists bool
+		if err := rows.Scan(&table, &column, &tableExists); err != nil {
+			return fmt.Errorf("[in services.checkMigrations] failed to scan missing column: %w", err)
+		}
+		switch {
+		case tableExists:
+			missingColumns = append(missingColumns, table+"."+column)
+		case !slices.Contains(missingTables, table):
+			missingTables = append(missingTables, table)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("[in services.checkMigrations] failed to scan missing columns: %w", err)
+	}
+
+	var missing []string
+	if len(missingTables) > 0 {
+		missing = append(missing, "missing tables: "+strings.Join(missingTables, ", "))
+	}
+	if len(missingColumns) > 0 {
+		missing = append(missing, "missing columns: "+strings.Join(missingColumns, ", "))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("[in services.checkMigrations] pending migrations, %s", strings.Join(missing, "; "))
+	}
+	return nil
+}
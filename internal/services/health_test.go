@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckReadiness(t *testing.T) {
+	migrationsQuery := `SELECT r.table_name, r.column_name, to_regclass(r.table_name) IS NOT NULL`
+
+	testCases := map[string]struct {
+		draining         bool
+		pingErr          error
+		missingColumns   [][]any
+		expectedReady    bool
+		expectedError    string
+		expectedStatuses map[string]string
+	}{
+		"all components up": {
+			expectedReady: true,
+			expectedStatuses: map[string]string{
+				"shutdown":   models.HealthStatusUp,
+				"database":   models.HealthStatusUp,
+				"migrations": models.HealthStatusUp,
+			},
+		},
+		"database down": {
+			pingErr:       errors.New("connection refused"),
+			expectedReady: false,
+			expectedStatuses: map[string]string{
+				"shutdown":   models.HealthStatusUp,
+				"database":   models.HealthStatusDown,
+				"migrations": models.HealthStatusUp,
+			},
+		},
+		"pending migrations": {
+			missingColumns: [][]any{
+				{"waitlist", "id", false},
+				{"waitlist", "person_id", false},
+				{"waitlist", "section_id", false},
+			},
+			expectedReady: false,
+			expectedError: "[in services.checkMigrations] pending migrations, missing tables: waitlist",
+			expectedStatuses: map[string]string{
+				"shutdown":   models.HealthStatusUp,
+				"database":   models.HealthStatusUp,
+				"migrations": models.HealthStatusDown,
+			},
+		},
+		"older schema": {
+			missingColumns: [][]any{
+				{"person", "email", true},
+				{"course", "capacity", true},
+			},
+			expectedReady: false,
+			expectedError: "[in services.checkMigrations] pending migrations, missing columns: person.email, course.capacity",
+			expectedStatuses: map[string]string{
+				"shutdown":   models.HealthStatusUp,
+				"database":   models.HealthStatusUp,
+				"migrations": models.HealthStatusDown,
+			},
+		},
+		"draining": {
+			draining:      true,
+			expectedReady: false,
+			expectedStatuses: map[string]string{
+				"shutdown":   models.HealthStatusDraining,
+				"database":   models.HealthStatusUp,
+				"migrations": models.HealthStatusUp,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dbMock, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			defer dbMock.Close()
+
+			service := NewHealthService(dbMock, time.Second)
+			service.SetDraining(tc.draining)
+
+			dbMock.ExpectPing().WillReturnError(tc.pingErr)
+			rows := pgxmock.NewRows([]string{"table_name", "column_name", "table_exists"})
+			for _, column := range tc.missingColumns {
+				rows.AddRow(column...)
+			}
+			dbMock.ExpectQuery(regexp.QuoteMeta(migrationsQuery)).
+				WithArgs(pgxmock.AnyArg(), pgxmock.AnyArg()).
+				WillReturnRows(rows)
+
+			readiness := service.CheckReadiness(context.Background())
+
+			assert.Equal(t, tc.expectedReady, readiness.Ready)
+			statuses := map[string]string{}
+			for _, component := range readiness.Components {
+				statuses[component.Name] = component.Status
+				if component.Name == "migrations" {
+					assert.Equal(t, tc.expectedError, component.Error)
+				}
+			}
+			assert.Equal(t, tc.expectedStatuses, statuses)
+
+			assert.NoError(t, dbMock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPoolStats(t *testing.T) {
+	// pgxpool connects lazily, so a pool pointed at nothing still reports its configuration.
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/postgres?pool_max_conns=7")
+	assert.NoError(t, err)
+	defer pool.Close()
+
+	stats := NewHealthService(pool, time.Second).PoolStats(context.Background())
+
+	assert.Equal(t, models.PoolStats{MaxConns: 7}, stats)
+}
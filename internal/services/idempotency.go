@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IdempotencyService stores the responses to requests sent with an idempotency key, so that a
+// retried request can be answered without being run again. Its statements never join a shared
+// transaction: a key must outlive the request that used it whether or not that request's changes
+// were rolled back.
+type IdempotencyService struct {
+	database         DB
+	statementTimeout time.Duration
+}
+
+func NewIdempotencyService(db DB, opts ...Option) *IdempotencyService {
+	options := newServiceOptions(opts)
+	return &IdempotencyService{
+		database:         db,
+		statementTimeout: options.statementTimeout,
+	}
+}
+
+// ReserveIdempotencyKey claims key for the request with the given hash until ttl has passed. If
+// the key is already held it is not claimed, and the record stored for it is returned instead. A
+// key that has expired, or that has been held by the same request without a response for longer
+// than staleAfter, is claimed again, since the request that held it will not finish.
+func (s *IdempotencyService) ReserveIdempotencyKey(ctx context.Context, key string, requestHash string, ttl time.Duration, staleAfter time.Duration) (models.IdempotencyRecord, bool, error) {
+	query := `
+		INSERT INTO idempotency_key (key, request_hash, expires_at)
+		VALUES ($1, $2, now() + make_interval(secs => $3))
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status = NULL, content_type = NULL, body = NULL,
+			created_at = now(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_key.expires_at <= now()
+			OR (idempotency_key.status IS NULL
+				AND idempotency_key.request_hash = EXCLUDED.request_hash
+				AND idempotency_key.created_at <= now() - make_interval(secs => $4))
+		RETURNING key
+	`
+	ctx, span := startQuerySpan(ctx, "IdempotencyService.ReserveIdempotencyKey", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	var reserved string
+	err := s.database.QueryRow(ctx, query, key, requestHash, ttl.Seconds(), staleAfter.Seconds()).Scan(&reserved)
+	if err == nil {
+		return models.IdempotencyRecord{RequestHash: requestHash}, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		tracing.RecordError(span, err)
+		return models.IdempotencyRecord{}, false, fmt.Errorf("[in services.ReserveIdempotencyKey] failed to reserve key: %w", err)
+	}
+
+	query = `SELECT request_hash, status, content_type, body FROM idempotency_key WHERE key = $1`
+	var status *int
+	var contentType *string
+	record := models.IdempotencyRecord{}
+	err = s.database.QueryRow(ctx, query, key).Scan(&record.RequestHash, &status, &contentType, &record.Response.Body)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.IdempotencyRecord{}, false, fmt.Errorf("[in services.ReserveIdempotencyKey] failed to get key: %w", err)
+	}
+	if status != nil {
+		record.Completed = true
+		record.Response.Status = *status
+	}
+	if contentType != nil {
+		record.Response.ContentType = *contentType
+	}
+	return record, false, nil
+}
+
+// CompleteIdempotencyKey stores the response to the request holding key.
+func (s *IdempotencyService) CompleteIdempotencyKey(ctx context.Context, key string, requestHash string, response models.IdempotentResponse) error {
+	query := `
+		UPDATE idempotency_key SET status = $3, content_type = $4, body = $5
+		WHERE key = $1 AND request_hash = $2 AND status IS NULL
+	`
+	ctx, span := startQuerySpan(ctx, "IdempotencyService.CompleteIdempotencyKey", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := s.database.Exec(ctx, query, key, requestHash, response.Status, response.ContentType, response.Body)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.CompleteIdempotencyKey] failed to store response: %w", err)
+	}
+	setRowsAffected(span, result.RowsAffected())
+	return nil
+}
+
+// ReleaseIdempotencyKey gives up key without storing a response, so that the request can be
+// retried.
+func (s *IdempotencyService) ReleaseIdempotencyKey(ctx context.Context, key string, requestHash string) error {
+	query := `DELETE FROM idempotency_key WHERE key = $1 AND request_hash = $2 AND status IS NULL`
+	ctx, span := startQuerySpan(ctx, "IdempotencyService.ReleaseIdempotencyKey", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := s.database.Exec(ctx, query, key, requestHash)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.ReleaseIdempotencyKey] failed to release key: %w", err)
+	}
+	setRowsAffected(span, result.RowsAffected())
+	return nil
+}
+
+// PurgeExpiredIdempotencyKeys deletes the keys whose window has passed, returning how many there
+// were. Expired keys are already free to be used again; purging only keeps the table small.
+func (s *IdempotencyService) PurgeExpiredIdempotencyKeys(ctx context.Context) (int64, error) {
+	query := `DELETE FROM idempotency_key WHERE expires_at <= now()`
+	ctx, span := startQuerySpan(ctx, "IdempotencyService.PurgeExpiredIdempotencyKeys", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := s.database.Exec(ctx, query)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return 0, fmt.Errorf("[in services.PurgeExpiredIdempotencyKeys] failed to delete keys: %w", err)
+	}
+	setRowsAffected(span, result.RowsAffected())
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReserveIdempotencyKey(t *testing.T) {
+	reserveQuery := `INSERT INTO idempotency_key \(key, request_hash, expires_at\).*ON CONFLICT \(key\) DO UPDATE.*RETURNING key`
+	selectQuery := `SELECT request_hash, status, content_type, body FROM idempotency_key WHERE key = \$1`
+	status, contentType := 201, "application/json"
+
+	tests := map[string]struct {
+		mockReserveErr   error
+		mockRows         *pgxmock.Rows
+		expectedRecord   models.IdempotencyRecord
+		expectedReserved bool
+		expectedErr      bool
+	}{
+		"key reserved": {
+			expectedRecord:   models.IdempotencyRecord{RequestHash: "hash"},
+			expectedReserved: true,
+		},
+		"key completed": {
+			mockReserveErr: pgx.ErrNoRows,
+			mockRows: pgxmock.NewRows([]string{"request_hash", "status", "content_type", "body"}).
+				AddRow("hash", &status, &contentType, []byte(`{"id":1}`)),
+			expectedRecord: models.IdempotencyRecord{
+				RequestHash: "hash",
+				Completed:   true,
+				Response:    models.IdempotentResponse{Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)},
+			},
+		},
+		"key in progress": {
+			mockReserveErr: pgx.ErrNoRows,
+			mockRows: pgxmock.NewRows([]string{"request_hash", "status", "content_type", "body"}).
+				AddRow("other", (*int)(nil), (*string)(nil), []byte(nil)),
+			expectedRecord: models.IdempotencyRecord{RequestHash: "other"},
+		},
+		"database error": {
+			mockReserveErr: errors.New("connection refused"),
+			expectedErr:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			service := NewIdempotencyService(mockDB)
+
+			reserve := mockDB.ExpectQuery(reserveQuery).WithArgs("key", "hash", 3600.0, 60.0)
+			if tc.mockReserveErr != nil {
+				reserve.WillReturnError(tc.mockReserveErr)
+			} else {
+				reserve.WillReturnRows(pgxmock.NewRows([]string{"key"}).AddRow("key"))
+			}
+			if tc.mockRows != nil {
+				mockDB.ExpectQuery(selectQuery).WithArgs("key").WillReturnRows(tc.mockRows)
+			}
+
+			record, reserved, err := service.ReserveIdempotencyKey(context.Background(), "key", "hash", time.Hour, time.Minute)
+
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedRecord, record)
+			assert.Equal(t, tc.expectedReserved, reserved)
+			assert.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCompleteAndReleaseIdempotencyKey(t *testing.T) {
+	ctx := context.Background()
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	service := NewIdempotencyService(mockDB)
+	response := models.IdempotentResponse{Status: 201, ContentType: "application/json", Body: []byte(`{"id":1}`)}
+
+	mockDB.ExpectExec(`UPDATE idempotency_key SET status = \$3, content_type = \$4, body = \$5`).
+		WithArgs("a", "hash", 201, "application/json", []byte(`{"id":1}`)).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mockDB.ExpectExec(`DELETE FROM idempotency_key WHERE key = \$1 AND request_hash = \$2 AND status IS NULL`).
+		WithArgs("b", "hash").
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mockDB.ExpectExec(`DELETE FROM idempotency_key WHERE expires_at <= now\(\)`).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+
+	assert.NoError(t, service.CompleteIdempotencyKey(ctx, "a", "hash", response))
+	assert.NoError(t, service.ReleaseIdempotencyKey(ctx, "b", "hash"))
+	purged, err := service.PurgeExpiredIdempotencyKeys(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
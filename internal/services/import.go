@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ImportPersons creates persons in bulk, in a single transaction. If any person refers to a section
+// that does not exist nothing is written, and the result reports the missing sections. Students are
+// given seats in the order they are imported, and put on the waitlist of the sections that are full.
+// A dry run makes the same checks and seating and then rolls back.
+func (s *PersonService) ImportPersons(ctx context.Context, persons []models.Person, dryRun bool) (models.ImportResult, error) {
+	ctx, span := tracer.Start(ctx, "PersonService.ImportPersons")
+	defer span.End()
+	span.SetAttributes(attribute.Int("import.rows", len(persons)), attribute.Bool("import.dry_run", dryRun))
+
+	tx, err := conn(ctx, s.database).Begin(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to begin transaction: %w", err)
+	}
+
+	// Deduplicate each person's sections, so that listing a section twice is not a key violation.
+	imported := make([]models.Person, len(persons))
+	for i, person := range persons {
+		person.Sections = uniqueIDs(person.Sections)
+		imported[i] = person
+	}
+
+	missing, courseOf, err := s.checkSections(ctx, tx, imported)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to check sections: %w", err)
+	}
+	if len(missing) > 0 {
+		rollback(ctx, tx)
+		return models.ImportResult{Persons: imported, MissingSections: missing}, nil
+	}
+
+	seats, err := s.seatsLeft(ctx, tx, slices.Sorted(maps.Keys(courseOf)))
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to count seats: %w", err)
+	}
+	assignSeats(imported, seats)
+	for i, person := range imported {
+		var courses []int
+		for _, sectionID := range person.Sections {
+			courses = append(courses, courseOf[sectionID])
+		}
+		slices.Sort(courses)
+		imported[i].Courses = slices.Compact(courses)
+	}
+	if dryRun || len(imported) == 0 {
+		rollback(ctx, tx)
+		return models.ImportResult{Persons: imported}, nil
+	}
+
+	ids, err := s.allocatePersonIDs(ctx, tx, len(imported))
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to allocate IDs: %w", err)
+	}
+	// COPY cannot return the rows it writes, so the timestamps are set here rather than defaulted.
+	now := time.Now().UTC()
+	personRows := make([][]any, len(imported))
+	var enrollmentRows [][]any
+	for i := range imported {
+		imported[i].ID = ids[i]
+		imported[i].CreatedAt, imported[i].UpdatedAt = now, now
+		person := imported[i]
+		personRows[i] = []any{person.ID, person.FirstName, person.LastName, person.Type, person.Age,
+			nullIfEmpty(person.ExternalID), nullIfEmpty(person.Email), nullIfEmpty(person.Phone),
+			person.DateOfBirth, person.CreatedAt, person.UpdatedAt, person.DepartmentID}
+		for _, sectionID := range person.Sections {
+			enrollmentRows = append(enrollmentRows, []any{person.ID, sectionID})
+		}
+	}
+
+	personColumns := []string{"id", "first_name", "last_name", "type", "age", "external_id", "email", "phone",
+		"date_of_birth", "created_at", "updated_at", "department_id"}
+	err = s.copyRows(ctx, tx, "person", personColumns, personRows)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to insert persons: %w", constraintError(err))
+	}
+	if len(enrollmentRows) > 0 {
+		err = s.copyRows(ctx, tx, "enrollment", []string{"person_id", "section_id"}, enrollmentRows)
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to insert enrollments: %w", err)
+		}
+	}
+	err = s.joinWaitlists(ctx, tx, imported)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to join waitlists: %w", err)
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.ImportResult{}, fmt.Errorf("[in services.ImportPersons] failed to commit transaction: %w", err)
+	}
+	s.cache.Invalidate(ctx, personKeyPrefix)
+
+	return models.ImportResult{Persons: imported}, nil
+}
+
+// checkSections returns, by index, the sections each person refers to that do not exist, and the
+// course of each section that does. Those sections are locked until the transaction ends, like
+// lockSections does, so that they cannot be deleted or filled up before the enrollments are
+// written.
+func (s *PersonService) checkSections(ctx context.Context, tx pgx.Tx, persons []models.Person) (map[int][]int, map[int]int, error) {
+	var referenced []int
+	for _, person := range persons {
+		referenced = append(referenced, person.Sections...)
+	}
+	if len(referenced) == 0 {
+		return nil, nil, nil
+	}
+
+	query := `SELECT id, course_id FROM course_section WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	ctx, span := startQuerySpan(ctx, "lock sections", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, uniqueIDs(referenced))
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, nil, err
+	}
+	courseOf := map[int]int{}
+	var sectionID, courseID int
+	_, err = pgx.ForEachRow(rows, []any{&sectionID, &courseID}, func() error {
+		courseOf[sectionID] = courseID
+		return nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, nil, err
+	}
+	setRowsReturned(span, len(courseOf))
+
+	missing := map[int][]int{}
+	for i, person := range persons {
+		for _, sectionID := range person.Sections {
+			if _, ok := courseOf[sectionID]; !ok {
+				missing[i] = append(missing[i], sectionID)
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil, courseOf, nil
+	}
+	return missing, courseOf, nil
+}
+
+// allocatePersonIDs takes count IDs from the person sequence, so that persons can be written with
+// COPY and still be linked to their sections.
+func (s *PersonService) allocatePersonIDs(ctx context.Context, tx pgx.Tx, count int) ([]int, error) {
+	query := `SELECT nextval(pg_get_serial_sequence('person', 'id')) FROM generate_series(1, $1)`
+	ctx, span := startQuerySpan(ctx, "allocate person ids", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, count)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	if len(ids) != count {
+		return nil, fmt.Errorf("expected %d IDs, got %d", count, len(ids))
+	}
+	return ids, nil
+}
+
+// nullIfEmpty returns nil for an empty string, so that COPY writes NULL for it.
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// copyRows writes rows into table with a single COPY.
+func (s *PersonService) copyRows(ctx context.Context, tx pgx.Tx, table string, columns []string, rows [][]any) error {
+	ctx, span := startQuerySpan(ctx, "copy "+table, fmt.Sprintf("COPY %s FROM STDIN", table))
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{table}, columns, pgx.CopyFromRows(rows))
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	setRowsAffected(span, copied)
+	return nil
+}
+
+// uniqueIDs returns ids without repeats, in order of first appearance.
+func uniqueIDs(ids []int) []int {
+	if ids == nil {
+		return nil
+	}
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *personTestSuite) TestImportPersons() {
+	t := s.T()
+
+	personsIn := []models.Person{
+		{FirstName: "John", LastName: "Doe", Type: "student", Age: 25, Sections: []int{1, 2, 1}},
+		{FirstName: "Jane", LastName: "Roe", Type: "professor", Age: 40},
+	}
+	checkSectionsQuery := `SELECT id, course_id FROM course_section WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	allocateIDsQuery := `SELECT nextval(pg_get_serial_sequence('person', 'id')) FROM generate_series(1, $1)`
+
+	t.Run("persons imported", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(checkSectionsQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "course_id"}).AddRow(1, 10).AddRow(2, 10))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}).AddRow(1, 5).AddRow(2, 0))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(allocateIDsQuery)).
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows([]string{"nextval"}).AddRow(7).AddRow(8))
+		s.dbMock.ExpectCopyFrom(pgx.Identifier{"person"}, personReturnColumns).
+			WillReturnResult(2)
+		s.dbMock.ExpectCopyFrom(pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}).
+			WillReturnResult(1)
+		s.dbMock.ExpectExec(regexp.QuoteMeta(joinWaitlistsQuery)).
+			WithArgs([]int{7}, []int{2}).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		s.dbMock.ExpectCommit()
+
+		result, err := s.service.ImportPersons(context.Background(), personsIn, false)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.MissingSections)
+		if assert.Len(t, result.Persons, 2) {
+			now := result.Persons[0].CreatedAt
+			assert.False(t, now.IsZero(), "the import time should be recorded")
+			assert.Equal(t, []models.Person{
+				{ID: 7, FirstName: "John", LastName: "Doe", Type: "student", Age: 25, Sections: []int{1}, Courses: []int{10}, Waitlisted: []int{2}, CreatedAt: now, UpdatedAt: now},
+				{ID: 8, FirstName: "Jane", LastName: "Roe", Type: "professor", Age: 40, CreatedAt: now, UpdatedAt: now},
+			}, result.Persons)
+		}
+		assert.Equal(t, []int{1, 2, 1}, personsIn[0].Sections, "the input should not be modified")
+		assert.NoError(t, s.dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("missing sections roll back", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(checkSectionsQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "course_id"}).AddRow(1, 10))
+		s.dbMock.ExpectRollback()
+
+		result, err := s.service.ImportPersons(context.Background(), personsIn, false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[int][]int{0: {2}}, result.MissingSections)
+		assert.NoError(t, s.dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("dry run rolls back", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(checkSectionsQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "course_id"}).AddRow(1, 10).AddRow(2, 10))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}))
+		s.dbMock.ExpectRollback()
+
+		result, err := s.service.ImportPersons(context.Background(), personsIn, true)
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.MissingSections)
+		assert.Len(t, result.Persons, 2)
+		assert.NoError(t, s.dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("copy failure rolls back", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(checkSectionsQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "course_id"}).AddRow(1, 10).AddRow(2, 10))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(allocateIDsQuery)).
+			WithArgs(2).
+			WillReturnRows(pgxmock.NewRows([]string{"nextval"}).AddRow(9).AddRow(10))
+		s.dbMock.ExpectCopyFrom(pgx.Identifier{"person"}, personReturnColumns).
+			WillReturnError(assert.AnError)
+		s.dbMock.ExpectRollback()
+
+		_, err := s.service.ImportPersons(context.Background(), personsIn, false)
+
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.NoError(t, s.dbMock.ExpectationsWereMet())
+	})
+}
+
+func TestUniqueIDs(t *testing.T) {
+	tests := map[string]struct {
+		ids      []int
+		expected []int
+	}{
+		"nil":        {ids: nil, expected: nil},
+		"no repeats": {ids: []int{3, 1, 2}, expected: []int{3, 1, 2}},
+		"repeats":    {ids: []int{2, 1, 2, 3, 1}, expected: []int{2, 1, 3}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, uniqueIDs(tc.ids))
+		})
+	}
+}
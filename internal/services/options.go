@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/database"
+	"time"
+)
+
+// Option configures a CourseService, PersonService or DepartmentService.
+type Option func(*serviceOptions)
+
+type serviceOptions struct {
+	statementTimeout time.Duration
+	streamTimeout    time.Duration
+	replicas         *database.ReplicaSet[Replica]
+	cache            *cache.Aside
+}
+
+// WithStatementTimeout bounds every SQL statement a service runs. A statement still running when
+// the timeout expires is cancelled. Zero, the default, leaves statements bounded only by the
+// caller's context.
+func WithStatementTimeout(timeout time.Duration) Option {
+	return func(options *serviceOptions) {
+		options.statementTimeout = timeout
+	}
+}
+
+// WithStreamTimeout bounds a query whose rows are streamed to the caller, from the query starting to
+// its last row being read, so that a slow reader cannot hold a connection indefinitely. Zero, the
+// default, leaves the stream bounded only by the caller's context.
+func WithStreamTimeout(timeout time.Duration) Option {
+	return func(options *serviceOptions) {
+		options.streamTimeout = timeout
+	}
+}
+
+// WithReplicas sends list and get queries to read replicas, falling back to the primary when no
+// replica is healthy, a replica cannot be reached, or the request context was marked with
+// database.WithPrimary.
+func WithReplicas(replicas *database.ReplicaSet[Replica]) Option {
+	return func(options *serviceOptions) {
+		options.replicas = replicas
+	}
+}
+
+// WithCache serves list and get queries through a cache-aside layer. Services sharing the layer
+// invalidate their cached reads after every change they make, including enrollment changes.
+func WithCache(aside *cache.Aside) Option {
+	return func(options *serviceOptions) {
+		options.cache = aside
+	}
+}
+
+func newServiceOptions(opts []Option) serviceOptions {
+	var options serviceOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// withFirstRowTimeout is withStatementTimeout for a query whose rows are handed on as they are
+// read, such as to a client downloading them. timeout bounds the query until stop is called, once
+// its first row or its end has arrived, and streamTimeout bounds the whole query including reading
+// its rows, so that a download gets longer than a single statement but cannot hold its connection
+// forever. Either is ignored unless positive. A query that times out is cancelled with
+// context.DeadlineExceeded as its cause.
+func withFirstRowTimeout(ctx context.Context, timeout, streamTimeout time.Duration) (_ context.Context, stop func(), cancel context.CancelFunc) {
+	ctx, cancelCause := context.WithCancelCause(ctx)
+	cancel = func() { cancelCause(context.Canceled) }
+	var streamTimer *time.Timer
+	if streamTimeout > 0 {
+		streamTimer = time.AfterFunc(streamTimeout, func() { cancelCause(context.DeadlineExceeded) })
+		cancel = func() {
+			streamTimer.Stop()
+			cancelCause(context.Canceled)
+		}
+	}
+	if timeout <= 0 {
+		return ctx, func() {}, cancel
+	}
+	timer := time.AfterFunc(timeout, func() { cancelCause(context.DeadlineExceeded) })
+	return ctx, func() { timer.Stop() }, cancel
+}
+
+// withStatementTimeout derives a context for one statement from ctx, bounded by timeout if it is
+// positive.
+func withStatementTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFirstRowTimeout(t *testing.T) {
+	tests := map[string]struct {
+		timeout       time.Duration
+		streamTimeout time.Duration
+		stop          bool
+		expectedErr   error
+		expectedCause error
+	}{
+		"stopped before the timeout": {
+			timeout: 10 * time.Millisecond,
+			stop:    true,
+		},
+		"timed out": {
+			timeout:       10 * time.Millisecond,
+			expectedErr:   context.Canceled,
+			expectedCause: context.DeadlineExceeded,
+		},
+		"stream timed out after the first row": {
+			timeout:       time.Hour,
+			streamTimeout: 10 * time.Millisecond,
+			stop:          true,
+			expectedErr:   context.Canceled,
+			expectedCause: context.DeadlineExceeded,
+		},
+		"no timeout": {
+			timeout: 0,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, stop, cancel := withFirstRowTimeout(context.Background(), tc.timeout, tc.streamTimeout)
+			defer cancel()
+			if tc.stop {
+				stop()
+			}
+
+			time.Sleep(30 * time.Millisecond)
+
+			assert.Equal(t, tc.expectedErr, ctx.Err())
+			assert.Equal(t, tc.expectedCause, context.Cause(ctx))
+		})
+	}
+}
@@ -2,97 +2,132 @@ package services
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/logging"
 	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"slices"
+	"time"
 
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
 )
 
 type PersonService struct {
-	database *sql.DB
+	database         DB
+	reads            reader
+	cache            *cache.Aside
+	statementTimeout time.Duration
+	streamTimeout    time.Duration
 }
 
 // NewUserService returns a new UserService struct.
-func NewPersonService(db *sql.DB) *PersonService {
+func NewPersonService(db DB, opts ...Option) *PersonService {
+	options := newServiceOptions(opts)
 	return &PersonService{
-		database: db,
+		database:         db,
+		reads:            reader{primary: db, replicas: options.replicas},
+		cache:            options.cache,
+		statementTimeout: options.statementTimeout,
+		streamTimeout:    options.streamTimeout,
 	}
 }
 
 func (s *PersonService) ListPersons(ctx context.Context) ([]models.Person, error) {
+	return cache.Load(ctx, s.cache, personListKey, s.listPersons)
+}
 
-	query := `SELECT p.id as person_id, 
-	p.first_name, 
-	p.last_name,
-	p.type,
-	p.age,
-	COALESCE(Array_AGG(pc.course_id), '{}') as course_ids
-	FROM person p
-	LEFT JOIN person_course pc ON p.id = pc.person_id
-	GROUP BY p.id, p.first_name, p.last_name, p.type, p.age
+func (s *PersonService) listPersons(ctx context.Context) ([]models.Person, error) {
+	var persons []models.Person
+	err := s.scanPersons(ctx, "ListPersons", func(person models.Person) error {
+		persons = append(persons, person)
+		return nil
+	})
+	if err != nil {
+		return []models.Person{}, err
+	}
+	return persons, nil
+}
+
+// StreamPersons calls fn with each person as it is read from the database, in the same order as
+// ListPersons, so that a caller writing them out never holds the whole list. It stops at the first
+// error fn returns. Streamed reads bypass the cache.
+func (s *PersonService) StreamPersons(ctx context.Context, fn func(models.Person) error) error {
+	return s.scanPersons(ctx, "StreamPersons", fn)
+}
+
+// scanPersons runs the query shared by ListPersons and StreamPersons, naming caller in errors and
+// spans.
+func (s *PersonService) scanPersons(ctx context.Context, caller string, fn func(models.Person) error) error {
+
+	query := selectPersons + `
+	GROUP BY p.id
 	ORDER BY person_id asc`
-	rows, err := s.database.QueryContext(
+	ctx, span := startQuerySpan(ctx, "PersonService."+caller, query)
+	defer span.End()
+	ctx, stopTimeout, cancel := withFirstRowTimeout(ctx, s.statementTimeout, s.streamTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(
 		ctx,
 		query,
 	)
 	if err != nil {
-		return []models.Person{}, fmt.Errorf("[in services.ListPersons] failed to get persons: %w", err)
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.%s] failed to get persons: %w", caller, err)
 	}
 	defer rows.Close()
 
-	var persons []models.Person
+	count := 0
 	for rows.Next() {
+		if count == 0 {
+			stopTimeout()
+		}
 		var person models.Person
-		var dbCourseIDs []sql.NullInt64
-		err = rows.Scan(&person.ID, &person.FirstName, &person.LastName, &person.Type, &person.Age, pq.Array(&dbCourseIDs))
+		err = rows.Scan(personRowFields(&person)...)
 		if err != nil {
-			return []models.Person{}, fmt.Errorf("[in services.ListPersons] failed to scan person from row: %w", err)
+			tracing.RecordError(span, err)
+			return fmt.Errorf("[in services.%s] failed to scan person from row: %w", caller, err)
 		}
-		// Convert pg array into []int
-		intCourseIDs := make([]int, len(dbCourseIDs))
-		if len(dbCourseIDs) > 0 && dbCourseIDs[0].Valid {
-			for i, id := range dbCourseIDs {
-				intCourseIDs[i] = int(id.Int64) // Convert int64 to int
-			}
-			person.Courses = intCourseIDs
+		if err = fn(person); err != nil {
+			tracing.RecordError(span, err)
+			return fmt.Errorf("[in services.%s] %w", caller, err)
 		}
-		persons = append(persons, person)
+		count++
 	}
 
 	if err = rows.Err(); err != nil {
-		return []models.Person{}, fmt.Errorf("[in services.ListPersons] failed to scan courses: %w", err)
+		tracing.RecordError(span, err)
+		return fmt.Errorf("[in services.%s] failed to scan courses: %w", caller, err)
 	}
+	setRowsReturned(span, count)
+	logging.Subsystem(ctx, logging.SubsystemServices).Debug("listed persons", "count", count)
 
-	return persons, nil
+	return nil
 
 }
 
 func (s *PersonService) GetPersonByName(ctx context.Context, name string) (models.Person, error) {
+	return cache.Load(ctx, s.cache, personKey(name), func(ctx context.Context) (models.Person, error) {
+		return s.getPersonByName(ctx, name)
+	})
+}
+
+func (s *PersonService) getPersonByName(ctx context.Context, name string) (models.Person, error) {
 	var person models.Person
-	var dbCourseIDs []sql.NullInt64
-	query := `SELECT p.id as person_id, 
-	p.first_name, 
-	p.last_name,
-	p.type,
-	p.age,
-	COALESCE(Array_AGG(pc.course_id), '{}') as course_ids
-	FROM person p
-	LEFT JOIN person_course pc ON p.id = pc.person_id
+	query := selectPersons + `
 	WHERE LOWER(p.last_name) = LOWER($1)
-	GROUP BY p.id, p.first_name, p.last_name, p.type, p.age`
-
-	err := s.database.QueryRowContext(ctx, query, name).Scan(&person.ID, &person.FirstName,
-		&person.LastName, &person.Type, &person.Age, pq.Array(&dbCourseIDs))
-	intCourseIDs := make([]int, len(dbCourseIDs))
-	if len(dbCourseIDs) > 0 && dbCourseIDs[0].Valid {
-		for i, id := range dbCourseIDs {
-			intCourseIDs[i] = int(id.Int64) // Convert int64 to int
-		}
-		person.Courses = intCourseIDs
-	}
+	GROUP BY p.id`
+	ctx, span := startQuerySpan(ctx, "PersonService.GetPersonByName", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := s.reads.QueryRow(ctx, query, name).Scan(personRowFields(&person)...)
 	if err != nil {
-		if err == sql.ErrNoRows {
+		tracing.RecordError(span, err)
+		if errors.Is(err, pgx.ErrNoRows) {
 			return models.Person{}, fmt.Errorf("[in services.GetPersonByName] no person found with name: %s", name)
 		}
 		return models.Person{}, fmt.Errorf("[in services.GetPersonByName] failed to retrieve person: %w", err)
@@ -101,184 +136,428 @@ func (s *PersonService) GetPersonByName(ctx context.Context, name string) (model
 }
 
 func (s *PersonService) UpdatePerson(ctx context.Context, lastName string, updatedPerson models.Person) (models.Person, error) {
-	var person models.Person
-	var updatedCourseIDs []int
+	var (
+		person       models.Person
+		previousType string
+	)
 
-	tx, err := s.database.BeginTx(ctx, nil)
+	ctx, span := tracer.Start(ctx, "PersonService.UpdatePerson")
+	defer span.End()
+
+	tx, err := conn(ctx, s.database).Begin(ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return models.Person{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
+	// previous locks the person and keeps their type from before the update, which RETURNING
+	// reports alongside the new one.
 	query := `
+	WITH previous AS (
+		SELECT id, type FROM person WHERE LOWER(last_name) = LOWER($10) FOR UPDATE
+	)
 	UPDATE person
 	SET first_name = $1,
 	last_name = $2,
 	type = $3,
-	age = $4
-	WHERE LOWER(last_name) = LOWER($5)
-	RETURNING id, first_name, last_name, type, age;
+	age = $4,
+	external_id = NULLIF($5, ''),
+	email = NULLIF($6, ''),
+	phone = NULLIF($7, ''),
+	date_of_birth = $8,
+	department_id = $9,
+	updated_at = now()
+	FROM previous
+	WHERE person.id = previous.id
+	RETURNING person.id, first_name, last_name, person.type,
+	COALESCE(date_part('year', age(date_of_birth))::int, age),
+	COALESCE(external_id, ''), COALESCE(email, ''), COALESCE(phone, ''),
+	date_of_birth, created_at, updated_at, department_id, previous.type;
 	`
 
-	err = s.database.QueryRow(query, updatedPerson.FirstName, updatedPerson.LastName,
-		updatedPerson.Type, updatedPerson.Age, lastName).Scan(
-		&person.ID,
-		&person.FirstName,
-		&person.LastName,
-		&person.Type,
-		&person.Age,
-	)
+	queryCtx, querySpan := startQuerySpan(ctx, "update person", query)
+	queryCtx, cancelQuery := withStatementTimeout(queryCtx, s.statementTimeout)
+	defer cancelQuery()
+	err = tx.QueryRow(queryCtx, query, updatedPerson.FirstName, updatedPerson.LastName,
+		updatedPerson.Type, updatedPerson.Age, updatedPerson.ExternalID, updatedPerson.Email,
+		updatedPerson.Phone, updatedPerson.DateOfBirth, updatedPerson.DepartmentID, lastName).Scan(append(personFields(&person), &previousType)...)
+	tracing.RecordError(querySpan, err)
+	querySpan.End()
 
 	if err != nil {
-		tx.Rollback()
-		return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to update person: %w", err)
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to update person: %w", constraintError(err))
 	}
 
-	if len(updatedPerson.Courses) > 0 {
+	// Only students count against capacity, so a change of type re-seats the person in the sections
+	// they hold even when their sections are not being changed: a new student may not fit, and a
+	// former one frees their seats for the waitlist.
+	if len(updatedPerson.Sections) > 0 || person.Type != previousType {
 
-		deleteCoursesQuery := `DELETE FROM person_course WHERE person_id = $1`
-		_, err = tx.Exec(deleteCoursesQuery, person.ID)
+		deleteEnrollmentsQuery := `DELETE FROM enrollment WHERE person_id = $1 RETURNING section_id`
+		dropped, err := s.deleteEnrollments(ctx, tx, deleteEnrollmentsQuery, person.ID)
 		if err != nil {
-			tx.Rollback()
-			return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to delete existing courses: %w", err)
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to delete existing enrollments: %w", err)
 		}
-		insertCoursesQuery := `INSERT INTO person_course (person_id, course_id) VALUES ($1, $2)`
-		for _, courseID := range updatedPerson.Courses {
-			_, err := tx.Exec(insertCoursesQuery, person.ID, courseID)
+		sections := updatedPerson.Sections
+		if len(sections) > 0 {
+			leaveWaitlistsQuery := `DELETE FROM waitlist WHERE person_id = $1 AND section_id <> ALL($2)`
+			err = s.exec(ctx, tx, "leave person waitlists", leaveWaitlistsQuery, person.ID, sections)
 			if err != nil {
-				tx.Rollback()
-				return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to insert new courses: %w", err)
+				tracing.RecordError(span, err)
+				rollback(ctx, tx)
+				return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to leave waitlists: %w", err)
 			}
+		} else {
+			sections = dropped
+		}
+		// The sections left are locked along with the ones joined, so that all are locked in order.
+		err = s.lockSections(ctx, tx, append(dropped, sections...))
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to lock sections: %w", err)
+		}
+		enrolled := person
+		enrolled.Sections = sections
+		err = s.enroll(ctx, tx, enrolled)
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to insert new enrollments: %w", constraintError(err))
+		}
+		_, err = promoteWaitlisted(ctx, tx, s.statementTimeout, dropped)
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to promote waitlisted students: %w", err)
 		}
 
 	}
 
-	// Get course IDs from person_course
-	selectCoursesQuery := `SELECT course_id FROM person_course WHERE person_id = $1`
-	rows, err := tx.Query(selectCoursesQuery, person.ID)
+	person.Sections, person.Courses, err = s.selectEnrollments(ctx, tx, person.ID)
 	if err != nil {
-		tx.Rollback()
-		return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to retrieve updated courses: %w", err)
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to retrieve updated enrollments: %w", err)
 	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var courseID int
-		if err := rows.Scan(&courseID); err != nil {
-			tx.Rollback()
-			return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to scan course ID: %w", err)
-		}
-		updatedCourseIDs = append(updatedCourseIDs, courseID)
+	person.Waitlisted, err = s.selectWaitlisted(ctx, tx, person.ID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to retrieve waitlists: %w", err)
 	}
-	person.Courses = updatedCourseIDs
-	err = tx.Commit()
+	err = tx.Commit(ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return models.Person{}, fmt.Errorf("[in services.UpdatePerson] failed to commit transaction: %w", err)
 	}
+	s.cache.Invalidate(ctx, personKeyPrefix)
 
 	return person, nil
 
 }
-func (s *PersonService) CreatePerson(ctx context.Context, person models.Person) (models.Person, error) {
-	tx, err := s.database.BeginTx(ctx, nil)
+
+// CreatePerson inserts a person. Unless allowDuplicate is set, a person who looks like one that
+// already exists, having the same normalized name and an age within duplicateAgeTolerance, is not
+// created; a *models.DuplicatePersonError listing the look-alikes is returned instead.
+func (s *PersonService) CreatePerson(ctx context.Context, person models.Person, allowDuplicate bool) (models.Person, error) {
+	ctx, span := tracer.Start(ctx, "PersonService.CreatePerson")
+	defer span.End()
+
+	tx, err := conn(ctx, s.database).Begin(ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return models.Person{}, fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	defer func() {
 		if r := recover(); r != nil {
-			tx.Rollback()
+			rollback(ctx, tx)
 		}
 	}()
 
-	query := `INSERT INTO person (first_name, last_name, type, age) 
-	          VALUES ($1, $2, $3, $4) RETURNING id, first_name, last_name, type, age`
+	if !allowDuplicate {
+		candidates, err := s.findDuplicates(ctx, tx, person)
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to check for duplicates: %w", err)
+		}
+		if len(candidates) > 0 {
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.CreatePerson] %w", &models.DuplicatePersonError{Candidates: candidates})
+		}
+	}
+
+	query := `INSERT INTO person (first_name, last_name, type, age, external_id, email, phone, date_of_birth, department_id) 
+	          VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9)
+	          RETURNING id, first_name, last_name, type,
+	          COALESCE(date_part('year', age(date_of_birth))::int, age),
+	          COALESCE(external_id, ''), COALESCE(email, ''), COALESCE(phone, ''),
+	          date_of_birth, created_at, updated_at, department_id`
 	var createdPerson models.Person
-	err = tx.QueryRowContext(ctx, query, person.FirstName, person.LastName, person.Type, person.Age).Scan(
-		&createdPerson.ID,
-		&createdPerson.FirstName,
-		&createdPerson.LastName,
-		&createdPerson.Type,
-		&createdPerson.Age,
-	)
+	queryCtx, querySpan := startQuerySpan(ctx, "insert person", query)
+	queryCtx, cancelQuery := withStatementTimeout(queryCtx, s.statementTimeout)
+	defer cancelQuery()
+	err = tx.QueryRow(queryCtx, query, person.FirstName, person.LastName, person.Type, person.Age, person.ExternalID,
+		person.Email, person.Phone, person.DateOfBirth, person.DepartmentID).Scan(personFields(&createdPerson)...)
+	tracing.RecordError(querySpan, err)
+	querySpan.End()
 	if err != nil {
-		tx.Rollback()
-		return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to insert person: %w", err)
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to insert person: %w", constraintError(err))
 	}
 
-	if len(person.Courses) > 0 {
-		insertCoursesQuery := `INSERT INTO person_course (person_id, course_id) VALUES ($1, $2)`
-		for _, courseID := range person.Courses {
-			_, err := tx.ExecContext(ctx, insertCoursesQuery, createdPerson.ID, courseID)
-			if err != nil {
-				tx.Rollback()
-				return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to insert courses: %w", err)
-			}
+	if len(person.Sections) > 0 {
+		err = s.lockSections(ctx, tx, person.Sections)
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to lock sections: %w", err)
+		}
+		enrolled := createdPerson
+		enrolled.Sections = person.Sections
+		err = s.enroll(ctx, tx, enrolled)
+		if err != nil {
+			tracing.RecordError(span, err)
+			rollback(ctx, tx)
+			return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to insert enrollments: %w", constraintError(err))
 		}
 	}
 
-	selectCoursesQuery := `SELECT course_id FROM person_course WHERE person_id = $1`
-	rows, err := tx.QueryContext(ctx, selectCoursesQuery, createdPerson.ID)
+	createdPerson.Sections, createdPerson.Courses, err = s.selectEnrollments(ctx, tx, createdPerson.ID)
 	if err != nil {
-		tx.Rollback()
-		return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to retrieve courses: %w", err)
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to retrieve enrollments: %w", err)
 	}
-	defer rows.Close()
-
-	var courses []int
-	for rows.Next() {
-		var courseID int
-		if err := rows.Scan(&courseID); err != nil {
-			tx.Rollback()
-			return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to scan course ID: %w", err)
-		}
-		courses = append(courses, courseID)
+	createdPerson.Waitlisted, err = s.selectWaitlisted(ctx, tx, createdPerson.ID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to retrieve waitlists: %w", err)
 	}
 
-	createdPerson.Courses = courses
-
-	err = tx.Commit()
+	err = tx.Commit(ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return models.Person{}, fmt.Errorf("[in services.CreatePerson] failed to commit transaction: %w", err)
 	}
+	s.cache.Invalidate(ctx, personKeyPrefix)
 
 	return createdPerson, nil
 }
 
 func (s *PersonService) DeletePerson(ctx context.Context, lastName string) error {
-	tx, err := s.database.BeginTx(ctx, nil)
+	ctx, span := tracer.Start(ctx, "PersonService.DeletePerson")
+	defer span.End()
+
+	tx, err := conn(ctx, s.database).Begin(ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return fmt.Errorf("[in services.DeletePerson] failed to begin transaction: %w", err)
 	}
 
-	deleteCoursesQuery := `DELETE FROM person_course WHERE person_id IN 
-                          (SELECT id FROM person WHERE LOWER(last_name) = LOWER($1))`
-	_, err = tx.ExecContext(ctx, deleteCoursesQuery, lastName)
+	deleteEnrollmentsQuery := `DELETE FROM enrollment WHERE person_id IN 
+                          (SELECT id FROM person WHERE LOWER(last_name) = LOWER($1))
+                          RETURNING section_id`
+	dropped, err := s.deleteEnrollments(ctx, tx, deleteEnrollmentsQuery, lastName)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("[in services.DeletePerson] failed to delete courses: %w", err)
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return fmt.Errorf("[in services.DeletePerson] failed to delete enrollments: %w", err)
 	}
 
 	deletePersonQuery := `DELETE FROM person WHERE LOWER(last_name) = LOWER($1)`
-	result, err := tx.ExecContext(ctx, deletePersonQuery, lastName)
+	queryCtx, querySpan := startQuerySpan(ctx, "delete person", deletePersonQuery)
+	queryCtx, cancelQuery := withStatementTimeout(queryCtx, s.statementTimeout)
+	defer cancelQuery()
+	result, err := tx.Exec(queryCtx, deletePersonQuery, lastName)
 	if err != nil {
-		tx.Rollback()
+		tracing.RecordError(querySpan, err)
+		querySpan.End()
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
 		return fmt.Errorf("[in services.DeletePerson] failed to delete person: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("[in services.DeletePerson] failed to check rows affected: %w", err)
-	}
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(querySpan, rowsAffected)
+	querySpan.End()
 	if rowsAffected == 0 {
-		tx.Rollback()
+		rollback(ctx, tx)
 		return fmt.Errorf("[in services.DeletePerson] no person found with last name: %s", lastName)
 	}
 
-	err = tx.Commit()
+	// The seats the person held go to the students waiting for them.
+	err = s.lockSections(ctx, tx, dropped)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return fmt.Errorf("[in services.DeletePerson] failed to lock sections: %w", err)
+	}
+	_, err = promoteWaitlisted(ctx, tx, s.statementTimeout, dropped)
+	if err != nil {
+		tracing.RecordError(span, err)
+		rollback(ctx, tx)
+		return fmt.Errorf("[in services.DeletePerson] failed to promote waitlisted students: %w", err)
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
+		tracing.RecordError(span, err)
 		return fmt.Errorf("[in services.DeletePerson] failed to commit transaction: %w", err)
 	}
+	// The foreign key took the person off any section they taught.
+	s.cache.Invalidate(ctx, personKeyPrefix, courseKeyPrefix)
+
+	return nil
+}
+
+// selectPersons selects persons with their course, section and waitlisted section IDs, in the
+// columns personRowFields scans. Queries add their WHERE clause and GROUP BY p.id.
+const selectPersons = `SELECT p.id as person_id,
+	p.first_name,
+	p.last_name,
+	p.type,
+	COALESCE(date_part('year', age(p.date_of_birth))::int, p.age) as age,
+	COALESCE(p.external_id, '') as external_id,
+	COALESCE(p.email, '') as email,
+	COALESCE(p.phone, '') as phone,
+	p.date_of_birth,
+	p.created_at,
+	p.updated_at,
+	p.department_id,
+	COALESCE(ARRAY_AGG(DISTINCT cs.course_id) FILTER (WHERE cs.course_id IS NOT NULL), '{}') as course_ids,
+	COALESCE(ARRAY_AGG(e.section_id ORDER BY e.section_id) FILTER (WHERE e.section_id IS NOT NULL), '{}') as section_ids,
+	ARRAY(SELECT w.section_id FROM waitlist w WHERE w.person_id = p.id ORDER BY w.id) as waitlisted
+	FROM person p
+	LEFT JOIN enrollment e ON p.id = e.person_id
+	LEFT JOIN course_section cs ON cs.id = e.section_id`
+
+// personRowFields returns where to scan the columns selected by selectPersons: those of
+// personFields, then the course, section and waitlisted section IDs.
+func personRowFields(person *models.Person) []any {
+	return append(personFields(person), &person.Courses, &person.Sections, &person.Waitlisted)
+}
+
+// personFields returns where to scan the columns every person query selects, in order: id,
+// first_name, last_name, type, age, external_id, email, phone, date_of_birth, created_at,
+// updated_at and department_id. The age is derived from the date of birth when there is one.
+func personFields(person *models.Person) []any {
+	return []any{&person.ID, &person.FirstName, &person.LastName, &person.Type, &person.Age,
+		&person.ExternalID, &person.Email, &person.Phone, &person.DateOfBirth, &person.CreatedAt,
+		&person.UpdatedAt, &person.DepartmentID}
+}
+
+// rollback aborts the transaction. Failures are logged rather than returned so that the error that
+// caused the rollback is the one reported to the caller.
+func rollback(ctx context.Context, tx pgx.Tx) {
+	if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+		logging.Subsystem(ctx, logging.SubsystemServices).Error("failed to roll back transaction", "error", err)
+	}
+}
+
+// exec runs a statement inside the given transaction under its own span.
+func (s *PersonService) exec(ctx context.Context, tx pgx.Tx, name string, query string, args ...any) error {
+	ctx, span := startQuerySpan(ctx, name, query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := tx.Exec(ctx, query, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	setRowsAffected(span, result.RowsAffected())
+	return nil
+}
+
+// deleteEnrollments deletes the enrollments matched by query, which must return their section_id,
+// and returns the sections they were in.
+func (s *PersonService) deleteEnrollments(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]int, error) {
+	ctx, span := startQuerySpan(ctx, "delete person enrollments", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	sectionIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsAffected(span, int64(len(sectionIDs)))
+	return uniqueIDs(sectionIDs), nil
+}
+
+// insertSections enrolls the person in every section in a single COPY rather than one INSERT per
+// section.
+func (s *PersonService) insertSections(ctx context.Context, tx pgx.Tx, personID int, sectionIDs []int) error {
+	ctx, span := startQuerySpan(ctx, "copy person enrollments", "COPY enrollment (person_id, section_id) FROM STDIN")
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows := make([][]any, len(sectionIDs))
+	for i, sectionID := range sectionIDs {
+		rows[i] = []any{personID, sectionID}
+	}
 
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}, pgx.CopyFromRows(rows))
+	if err != nil {
+		tracing.RecordError(span, err)
+		return err
+	}
+	setRowsAffected(span, copied)
 	return nil
 }
+
+// selectEnrollments returns the IDs of every section the person is enrolled in and of the courses
+// of those sections, each listed once, both in ascending order.
+func (s *PersonService) selectEnrollments(ctx context.Context, tx pgx.Tx, personID int) ([]int, []int, error) {
+	selectEnrollmentsQuery := `SELECT e.section_id, cs.course_id FROM enrollment e
+	JOIN course_section cs ON cs.id = e.section_id
+	WHERE e.person_id = $1
+	ORDER BY e.section_id`
+	ctx, span := startQuerySpan(ctx, "select person enrollments", selectEnrollmentsQuery)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, selectEnrollmentsQuery, personID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var sections, courses []int
+	for rows.Next() {
+		var sectionID, courseID int
+		if err := rows.Scan(&sectionID, &courseID); err != nil {
+			tracing.RecordError(span, err)
+			return nil, nil, fmt.Errorf("failed to scan enrollment: %w", err)
+		}
+		sections = append(sections, sectionID)
+		courses = append(courses, courseID)
+	}
+	if err := rows.Err(); err != nil {
+		tracing.RecordError(span, err)
+		return nil, nil, err
+	}
+	setRowsReturned(span, len(sections))
+
+	slices.Sort(courses)
+	return sections, slices.Compact(courses), nil
+}
@@ -2,24 +2,51 @@ package services
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"testing"
+	"time"
 
 	"go-api-tech-challenge/internal/models"
 
-	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
 
+// personReturnColumns are the columns returned by inserting or updating a person.
+var personReturnColumns = []string{"id", "first_name", "last_name", "type", "age", "external_id", "email", "phone",
+	"date_of_birth", "created_at", "updated_at", "department_id"}
+
+// updatePersonColumns are the columns returned by updating a person: personReturnColumns, then the
+// type the person had before the update.
+var updatePersonColumns = append(slices.Clone(personReturnColumns), "previous_type")
+
+// updatePersonQuery is the statement UpdatePerson runs to update a person.
+const updatePersonQuery = `
+	UPDATE person
+	SET first_name = $1,
+		last_name = $2,
+		type = $3,
+		age = $4,
+		external_id = NULLIF($5, ''),
+		email = NULLIF($6, ''),
+		phone = NULLIF($7, ''),
+		date_of_birth = $8,
+		department_id = $9,
+		updated_at = now()
+	FROM previous
+	WHERE person.id = previous.id
+`
+
 type personTestSuite struct {
 	suite.Suite
 	service *PersonService
-	dbMock  sqlmock.Sqlmock
+	dbMock  pgxmock.PgxPoolIface
 }
 
 func TestPersonTestSuite(t *testing.T) {
@@ -27,11 +54,11 @@ func TestPersonTestSuite(t *testing.T) {
 }
 
 func (s *personTestSuite) SetupSuite() {
-	db, mock, err := sqlmock.New()
+	mock, err := pgxmock.NewPool()
 	assert.NoError(s.T(), err)
 
 	s.dbMock = mock
-	s.service = NewPersonService(db)
+	s.service = NewPersonService(mock)
 }
 
 func (s *personTestSuite) TearDownSuite() {
@@ -39,44 +66,72 @@ func (s *personTestSuite) TearDownSuite() {
 	assert.NoError(s.T(), err)
 }
 
+// expectEnrollment expects the person to be given a seat in every one of the sections, which have
+// no capacity, and their enrollments and waitlists to be read back.
+func (s *personTestSuite) expectEnrollment(personID int, sections []int) {
+	s.dbMock.ExpectExec(regexp.QuoteMeta(lockSectionsQuery)).
+		WithArgs(sections).
+		WillReturnResult(pgxmock.NewResult("SELECT", int64(len(sections))))
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+		WithArgs(sections).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}))
+	s.dbMock.ExpectCopyFrom(pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}).
+		WillReturnResult(int64(len(sections)))
+
+	rows := pgxmock.NewRows([]string{"section_id", "course_id"})
+	for _, sectionID := range sections {
+		rows.AddRow(sectionID, sectionID)
+	}
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT e.section_id, cs.course_id FROM enrollment e`)).
+		WithArgs(personID).
+		WillReturnRows(rows)
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(selectWaitlistedQuery)).
+		WithArgs(personID).
+		WillReturnRows(pgxmock.NewRows([]string{"section_id"}))
+}
+
 func (s *personTestSuite) TestListPersons() {
 	t := s.T()
 
 	persons := []models.Person{
 		{
-			ID:        1,
-			FirstName: "John",
-			LastName:  "Doe",
-			Type:      "student",
-			Age:       25,
-			Courses:   []int{1, 2},
+			ID:         1,
+			FirstName:  "John",
+			LastName:   "Doe",
+			Type:       "student",
+			Age:        25,
+			Sections:   []int{1, 2},
+			Courses:    []int{1, 2},
+			Waitlisted: []int{4},
 		},
 		{
-			ID:        2,
-			FirstName: "Jane",
-			LastName:  "Smith",
-			Type:      "professor",
-			Age:       45,
-			Courses:   []int{3},
+			ID:         2,
+			FirstName:  "Jane",
+			LastName:   "Smith",
+			Type:       "professor",
+			Age:        45,
+			Sections:   []int{3},
+			Courses:    []int{3},
+			Waitlisted: []int{},
 		},
 	}
 
 	testCases := map[string]struct {
-		mockReturn     *sqlmock.Rows
+		mockReturn     *pgxmock.Rows
 		mockReturnErr  error
 		expectedReturn []models.Person
 		expectedError  error
 	}{
 		"Return slice of persons": {
-			mockReturn: sqlmock.NewRows([]string{"person_id", "first_name", "last_name", "type", "age", "course_ids"}).
-				AddRow(1, "John", "Doe", "student", 25, pq.Array([]int64{1, 2})).
-				AddRow(2, "Jane", "Smith", "professor", 45, pq.Array([]int64{3})),
+			mockReturn: pgxmock.NewRows([]string{"person_id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}).
+				AddRow(1, "John", "Doe", "student", 25, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{1, 2}, []int{1, 2}, []int{4}).
+				AddRow(2, "Jane", "Smith", "professor", 45, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{3}, []int{3}, []int{}),
 			mockReturnErr:  nil,
 			expectedReturn: persons,
 			expectedError:  nil,
 		},
 		"Error getting persons": {
-			mockReturn:     sqlmock.NewRows([]string{}),
+			mockReturn:     pgxmock.NewRows([]string{}),
 			mockReturnErr:  errors.New("test error"),
 			expectedReturn: []models.Person{},
 			expectedError:  fmt.Errorf("[in services.ListPersons] failed to get persons: %w", errors.New("test error")),
@@ -85,10 +140,13 @@ func (s *personTestSuite) TestListPersons() {
 
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
-			exp := `SELECT p.id as person_id, p.first_name, p.last_name, p.type, p.age, COALESCE(Array_AGG(pc.course_id), '{}') as course_ids
+			exp := `SELECT p.id as person_id, p.first_name, p.last_name, p.type, COALESCE(date_part('year', age(p.date_of_birth))::int, p.age) as age, COALESCE(p.external_id, '') as external_id, COALESCE(p.email, '') as email, COALESCE(p.phone, '') as phone, p.date_of_birth, p.created_at, p.updated_at, p.department_id, COALESCE(ARRAY_AGG(DISTINCT cs.course_id) FILTER (WHERE cs.course_id IS NOT NULL), '{}') as course_ids,
+				COALESCE(ARRAY_AGG(e.section_id ORDER BY e.section_id) FILTER (WHERE e.section_id IS NOT NULL), '{}') as section_ids,
+				ARRAY(SELECT w.section_id FROM waitlist w WHERE w.person_id = p.id ORDER BY w.id) as waitlisted
 				FROM person p
-				LEFT JOIN person_course pc ON p.id = pc.person_id
-				GROUP BY p.id, p.first_name, p.last_name, p.type, p.age
+				LEFT JOIN enrollment e ON p.id = e.person_id
+				LEFT JOIN course_section cs ON cs.id = e.section_id
+				GROUP BY p.id
 				ORDER BY person_id asc`
 			s.dbMock.
 				ExpectQuery(regexp.QuoteMeta(exp)).
@@ -105,43 +163,90 @@ func (s *personTestSuite) TestListPersons() {
 		})
 	}
 }
+func (s *personTestSuite) TestStreamPersons() {
+	t := s.T()
+	errStop := errors.New("client went away")
+
+	testCases := map[string]struct {
+		stopAfter        int
+		expectedStreamed []string
+		expectedError    error
+	}{
+		"streams every person in order": {
+			expectedStreamed: []string{"Doe", "Smith"},
+		},
+		"stops when the callback fails": {
+			stopAfter:        1,
+			expectedStreamed: []string{"Doe"},
+			expectedError:    fmt.Errorf("[in services.StreamPersons] %w", errStop),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s.dbMock.
+				ExpectQuery(`SELECT p.id as person_id, .* ORDER BY person_id asc`).
+				WillReturnRows(pgxmock.NewRows([]string{"person_id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}).
+					AddRow(1, "John", "Doe", "student", 25, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{1, 2}, []int{1, 2}, []int{4}).
+					AddRow(2, "Jane", "Smith", "professor", 45, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{3}, []int{3}, []int{}))
+
+			var streamed []string
+			err := s.service.StreamPersons(context.Background(), func(person models.Person) error {
+				streamed = append(streamed, person.LastName)
+				if len(streamed) == tc.stopAfter {
+					return errStop
+				}
+				return nil
+			})
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedStreamed, streamed)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
 func (s *personTestSuite) TestGetPersonByName() {
 	t := s.T()
 
 	person := models.Person{
-		ID:        1,
-		FirstName: "John",
-		LastName:  "Doe",
-		Type:      "student",
-		Age:       25,
-		Courses:   []int{1, 2},
+		ID:         1,
+		FirstName:  "John",
+		LastName:   "Doe",
+		Type:       "student",
+		Age:        25,
+		Sections:   []int{1, 2},
+		Courses:    []int{1, 2},
+		Waitlisted: []int{4},
 	}
 
 	testCases := map[string]struct {
 		name           string
-		mockReturn     *sqlmock.Rows
+		mockReturn     *pgxmock.Rows
 		mockReturnErr  error
 		expectedReturn models.Person
 		expectedError  error
 	}{
 		"person found": {
 			name: "Doe",
-			mockReturn: sqlmock.NewRows([]string{"person_id", "first_name", "last_name", "type", "age", "course_ids"}).
-				AddRow(1, "John", "Doe", "student", 25, pq.Array([]int64{1, 2})),
+			mockReturn: pgxmock.NewRows([]string{"person_id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}).
+				AddRow(1, "John", "Doe", "student", 25, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{1, 2}, []int{1, 2}, []int{4}),
 			mockReturnErr:  nil,
 			expectedReturn: person,
 			expectedError:  nil,
 		},
 		"person not found": {
 			name:           "Smith",
-			mockReturn:     sqlmock.NewRows([]string{}), // No rows returned
-			mockReturnErr:  sql.ErrNoRows,               // Simulate no rows found
+			mockReturn:     pgxmock.NewRows([]string{}), // No rows returned
+			mockReturnErr:  pgx.ErrNoRows,               // Simulate no rows found
 			expectedReturn: models.Person{},
 			expectedError:  fmt.Errorf("[in services.GetPersonByName] no person found with name: %s", "Smith"),
 		},
 		"query error": {
 			name:           "Doe",
-			mockReturn:     sqlmock.NewRows([]string{}), // Return empty rows but simulate an error
+			mockReturn:     pgxmock.NewRows([]string{}), // Return empty rows but simulate an error
 			mockReturnErr:  errors.New("test error"),
 			expectedReturn: models.Person{},
 			expectedError:  fmt.Errorf("[in services.GetPersonByName] failed to retrieve person: %w", errors.New("test error")),
@@ -155,12 +260,22 @@ func (s *personTestSuite) TestGetPersonByName() {
 				p.first_name, 
 				p.last_name,
 				p.type,
-				p.age,
-				COALESCE(Array_AGG(pc.course_id), '{}') as course_ids
+				COALESCE(date_part('year', age(p.date_of_birth))::int, p.age) as age,
+				COALESCE(p.external_id, '') as external_id,
+				COALESCE(p.email, '') as email,
+				COALESCE(p.phone, '') as phone,
+				p.date_of_birth,
+				p.created_at,
+				p.updated_at,
+				p.department_id,
+				COALESCE(ARRAY_AGG(DISTINCT cs.course_id) FILTER (WHERE cs.course_id IS NOT NULL), '{}') as course_ids,
+				COALESCE(ARRAY_AGG(e.section_id ORDER BY e.section_id) FILTER (WHERE e.section_id IS NOT NULL), '{}') as section_ids,
+				ARRAY(SELECT w.section_id FROM waitlist w WHERE w.person_id = p.id ORDER BY w.id) as waitlisted
 				FROM person p
-				LEFT JOIN person_course pc ON p.id = pc.person_id
+				LEFT JOIN enrollment e ON p.id = e.person_id
+				LEFT JOIN course_section cs ON cs.id = e.section_id
 				WHERE LOWER(p.last_name) = LOWER($1)
-				GROUP BY p.id, p.first_name, p.last_name, p.type, p.age`
+				GROUP BY p.id`
 
 			query := s.dbMock.
 				ExpectQuery(regexp.QuoteMeta(exp)).
@@ -192,52 +307,67 @@ func (s *personTestSuite) TestUpdatePerson() {
 		LastName:  "Smith",
 		Type:      "student",
 		Age:       25,
-		Courses:   []int{1, 2},
+		Sections:  []int{1, 2},
 	}
 
 	personOut := models.Person{
-		ID:        1,
-		FirstName: "John",
-		LastName:  "Smith",
-		Type:      "student",
-		Age:       25,
-		Courses:   []int{1, 2},
+		ID:         1,
+		FirstName:  "John",
+		LastName:   "Smith",
+		Type:       "student",
+		Age:        25,
+		Sections:   []int{1},
+		Courses:    []int{1},
+		Waitlisted: []int{2},
 	}
 
 	s.dbMock.ExpectBegin()
 
-	updatePersonQuery := `
-		UPDATE person
-		SET first_name = $1,
-			last_name = $2,
-			type = $3,
-			age = $4
-		WHERE LOWER(last_name) = LOWER($5)
-		RETURNING id, first_name, last_name, type, age;
-	`
 	s.dbMock.ExpectQuery(regexp.QuoteMeta(updatePersonQuery)).
-		WithArgs(personIn.FirstName, personIn.LastName, personIn.Type, personIn.Age, lastName).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "type", "age"}).
-			AddRow(personOut.ID, personOut.FirstName, personOut.LastName, personOut.Type, personOut.Age))
+		WithArgs(personIn.FirstName, personIn.LastName, personIn.Type, personIn.Age, personIn.ExternalID,
+			personIn.Email, personIn.Phone, personIn.DateOfBirth, personIn.DepartmentID, lastName).
+		WillReturnRows(pgxmock.NewRows(updatePersonColumns).
+			AddRow(personOut.ID, personOut.FirstName, personOut.LastName, personOut.Type, personOut.Age, personOut.ExternalID, "", "", nil, time.Time{}, time.Time{}, nil, "student"))
 
-	deleteCoursesQuery := `DELETE FROM person_course WHERE person_id = $1`
-	s.dbMock.ExpectExec(regexp.QuoteMeta(deleteCoursesQuery)).
+	deleteEnrollmentsQuery := `DELETE FROM enrollment WHERE person_id = $1 RETURNING section_id`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(deleteEnrollmentsQuery)).
 		WithArgs(personOut.ID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	insertCoursesQuery := `INSERT INTO person_course (person_id, course_id) VALUES ($1, $2)`
-	for _, courseID := range personIn.Courses {
-		s.dbMock.ExpectExec(regexp.QuoteMeta(insertCoursesQuery)).
-			WithArgs(personOut.ID, courseID).
-			WillReturnResult(sqlmock.NewResult(1, 1))
-	}
-
-	selectCoursesQuery := `SELECT course_id FROM person_course WHERE person_id = $1`
-	s.dbMock.ExpectQuery(regexp.QuoteMeta(selectCoursesQuery)).
+		WillReturnRows(pgxmock.NewRows([]string{"section_id"}).AddRow(1).AddRow(3))
+
+	leaveWaitlistsQuery := `DELETE FROM waitlist WHERE person_id = $1 AND section_id <> ALL($2)`
+	s.dbMock.ExpectExec(regexp.QuoteMeta(leaveWaitlistsQuery)).
+		WithArgs(personOut.ID, personIn.Sections).
+		WillReturnResult(pgxmock.NewResult("DELETE", 0))
+
+	s.dbMock.ExpectExec(regexp.QuoteMeta(lockSectionsQuery)).
+		WithArgs([]int{1, 3, 2}).
+		WillReturnResult(pgxmock.NewResult("SELECT", 3))
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+		WithArgs(personIn.Sections).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}).AddRow(1, 1).AddRow(2, 0))
+
+	s.dbMock.ExpectCopyFrom(pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}).
+		WillReturnResult(1)
+	s.dbMock.ExpectExec(regexp.QuoteMeta(joinWaitlistsQuery)).
+		WithArgs([]int{personOut.ID}, []int{2}).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
+	// The seat left in section 3 goes to the first student waiting for it.
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+		WithArgs([]int{1, 3}).
+		WillReturnRows(pgxmock.NewRows([]string{"person_id", "section_id"}).AddRow(8, 3))
+	s.dbMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+		WithArgs(WaitlistPromotionChannel, `{"person_id":8,"section_id":3}`).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	selectEnrollmentsQuery := `SELECT e.section_id, cs.course_id FROM enrollment e`
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(selectEnrollmentsQuery)).
+		WithArgs(personOut.ID).
+		WillReturnRows(pgxmock.NewRows([]string{"section_id", "course_id"}).
+			AddRow(1, 1))
+	s.dbMock.ExpectQuery(regexp.QuoteMeta(selectWaitlistedQuery)).
 		WithArgs(personOut.ID).
-		WillReturnRows(sqlmock.NewRows([]string{"course_id"}).
-			AddRow(1).
-			AddRow(2))
+		WillReturnRows(pgxmock.NewRows([]string{"section_id"}).AddRow(2))
 
 	s.dbMock.ExpectCommit()
 
@@ -250,6 +380,93 @@ func (s *personTestSuite) TestUpdatePerson() {
 	assert.NoError(t, err)
 }
 
+func (s *personTestSuite) TestUpdatePersonType() {
+	t := s.T()
+
+	deleteEnrollmentsQuery := `DELETE FROM enrollment WHERE person_id = $1 RETURNING section_id`
+	selectEnrollmentsQuery := `SELECT e.section_id, cs.course_id FROM enrollment e`
+
+	// expectUpdate expects the person to be updated from previousType to their type and the
+	// enrollments in held to be dropped and the sections locked, as for every change of type.
+	expectUpdate := func(person models.Person, previousType string, held []int) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(updatePersonQuery)).
+			WithArgs(person.FirstName, person.LastName, person.Type, person.Age, person.ExternalID,
+				person.Email, person.Phone, person.DateOfBirth, person.DepartmentID, person.LastName).
+			WillReturnRows(pgxmock.NewRows(updatePersonColumns).
+				AddRow(1, person.FirstName, person.LastName, person.Type, person.Age, "", "", "", nil, time.Time{}, time.Time{}, nil, previousType))
+		droppedRows := pgxmock.NewRows([]string{"section_id"})
+		for _, sectionID := range held {
+			droppedRows.AddRow(sectionID)
+		}
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(deleteEnrollmentsQuery)).
+			WithArgs(1).
+			WillReturnRows(droppedRows)
+		s.dbMock.ExpectExec(regexp.QuoteMeta(lockSectionsQuery)).
+			WithArgs(held).
+			WillReturnResult(pgxmock.NewResult("SELECT", int64(len(held))))
+	}
+
+	t.Run("professor waitlisted for the full sections they taught", func(t *testing.T) {
+		person := models.Person{FirstName: "Steve", LastName: "Jobs", Type: "student", Age: 56}
+		expectUpdate(person, "professor", []int{2})
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+			WithArgs([]int{2}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}).AddRow(2, 0))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(joinWaitlistsQuery)).
+			WithArgs([]int{1}, []int{2}).
+			WillReturnResult(pgxmock.NewResult("INSERT", 1))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+			WithArgs([]int{2}).
+			WillReturnRows(pgxmock.NewRows([]string{"person_id", "section_id"}))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(selectEnrollmentsQuery)).
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"section_id", "course_id"}))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(selectWaitlistedQuery)).
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"section_id"}).AddRow(2))
+		s.dbMock.ExpectCommit()
+
+		result, err := s.service.UpdatePerson(context.Background(), person.LastName, person)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2}, result.Waitlisted)
+		assert.Empty(t, result.Sections)
+		assert.NoError(t, s.dbMock.ExpectationsWereMet())
+	})
+
+	t.Run("student becoming a professor frees their seats", func(t *testing.T) {
+		person := models.Person{FirstName: "Steve", LastName: "Jobs", Type: "professor", Age: 56}
+		expectUpdate(person, "student", []int{3})
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+			WithArgs([]int{3}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}).AddRow(3, 1))
+		s.dbMock.ExpectCopyFrom(pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}).
+			WillReturnResult(1)
+		// The seat the person no longer takes up goes to the first student waiting for it.
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+			WithArgs([]int{3}).
+			WillReturnRows(pgxmock.NewRows([]string{"person_id", "section_id"}).AddRow(8, 3))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+			WithArgs(WaitlistPromotionChannel, `{"person_id":8,"section_id":3}`).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(selectEnrollmentsQuery)).
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"section_id", "course_id"}).AddRow(3, 2))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(selectWaitlistedQuery)).
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows([]string{"section_id"}))
+		s.dbMock.ExpectCommit()
+
+		result, err := s.service.UpdatePerson(context.Background(), person.LastName, person)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{3}, result.Sections)
+		assert.Equal(t, []int{2}, result.Courses)
+		assert.NoError(t, s.dbMock.ExpectationsWereMet())
+	})
+}
+
 func (s *personTestSuite) TestCreatePerson() {
 	t := s.T()
 
@@ -258,48 +475,49 @@ func (s *personTestSuite) TestCreatePerson() {
 		LastName:  "Doe",
 		Type:      "student",
 		Age:       25,
-		Courses:   []int{1, 2},
+		Sections:  []int{1, 2},
 	}
 
 	personOut := models.Person{
-		ID:        1,
-		FirstName: "John",
-		LastName:  "Doe",
-		Type:      "student",
-		Age:       25,
-		Courses:   []int{1, 2},
+		ID:         1,
+		FirstName:  "John",
+		LastName:   "Doe",
+		Type:       "student",
+		Age:        25,
+		Sections:   []int{1, 2},
+		Courses:    []int{1, 2},
+		Waitlisted: []int{},
 	}
 
+	lockQuery := `SELECT pg_advisory_xact_lock(hashtext($1))`
+	duplicatesQuery := `WHERE regexp_replace(lower(p.first_name || p.last_name), '[^[:alnum:]]', '', 'g') = $1`
+	insertPersonQuery := `
+		INSERT INTO person (first_name, last_name, type, age, external_id, email, phone, date_of_birth, department_id) 
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), NULLIF($6, ''), NULLIF($7, ''), $8, $9)
+	`
+	personColumns := []string{"id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}
+
 	t.Run("person created successfully", func(t *testing.T) {
 		s.dbMock.ExpectBegin()
 
-		insertPersonQuery := `
-			INSERT INTO person (first_name, last_name, type, age) 
-			VALUES ($1, $2, $3, $4) 
-			RETURNING id, first_name, last_name, type, age
-		`
+		s.dbMock.ExpectExec(regexp.QuoteMeta(lockQuery)).
+			WithArgs("person:johndoe").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(duplicatesQuery)).
+			WithArgs("johndoe", 24, 26, "", "", (*time.Time)(nil)).
+			WillReturnRows(pgxmock.NewRows(personColumns))
+
 		s.dbMock.ExpectQuery(regexp.QuoteMeta(insertPersonQuery)).
-			WithArgs(personIn.FirstName, personIn.LastName, personIn.Type, personIn.Age).
-			WillReturnRows(sqlmock.NewRows([]string{"id", "first_name", "last_name", "type", "age"}).
-				AddRow(personOut.ID, personOut.FirstName, personOut.LastName, personOut.Type, personOut.Age))
-
-		insertCourseQuery := `INSERT INTO person_course (person_id, course_id) VALUES ($1, $2)`
-		for _, courseID := range personIn.Courses {
-			s.dbMock.ExpectExec(regexp.QuoteMeta(insertCourseQuery)).
-				WithArgs(personOut.ID, courseID).
-				WillReturnResult(sqlmock.NewResult(1, 1))
-		}
+			WithArgs(personIn.FirstName, personIn.LastName, personIn.Type, personIn.Age, personIn.ExternalID,
+				personIn.Email, personIn.Phone, personIn.DateOfBirth, personIn.DepartmentID).
+			WillReturnRows(pgxmock.NewRows(personReturnColumns).
+				AddRow(personOut.ID, personOut.FirstName, personOut.LastName, personOut.Type, personOut.Age, personOut.ExternalID, "", "", nil, time.Time{}, time.Time{}, nil))
 
-		selectCoursesQuery := `SELECT course_id FROM person_course WHERE person_id = $1`
-		s.dbMock.ExpectQuery(regexp.QuoteMeta(selectCoursesQuery)).
-			WithArgs(personOut.ID).
-			WillReturnRows(sqlmock.NewRows([]string{"course_id"}).
-				AddRow(1).
-				AddRow(2))
+		s.expectEnrollment(personOut.ID, personIn.Sections)
 
 		s.dbMock.ExpectCommit()
 
-		actualReturn, err := s.service.CreatePerson(context.Background(), personIn)
+		actualReturn, err := s.service.CreatePerson(context.Background(), personIn, false)
 
 		assert.NoError(t, err)
 		assert.Equal(t, personOut, actualReturn)
@@ -307,6 +525,292 @@ func (s *personTestSuite) TestCreatePerson() {
 		err = s.dbMock.ExpectationsWereMet()
 		assert.NoError(t, err)
 	})
+
+	t.Run("possible duplicate", func(t *testing.T) {
+		candidate := models.Person{ID: 7, FirstName: "john", LastName: "Doe", Type: "student", Age: 26, Sections: []int{3}, Courses: []int{3}, Waitlisted: []int{}}
+
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectExec(regexp.QuoteMeta(lockQuery)).
+			WithArgs("person:johndoe").
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(duplicatesQuery)).
+			WithArgs("johndoe", 24, 26, "", "", (*time.Time)(nil)).
+			WillReturnRows(pgxmock.NewRows(personColumns).
+				AddRow(candidate.ID, candidate.FirstName, candidate.LastName, candidate.Type, candidate.Age, "", "", "", nil, time.Time{}, time.Time{}, nil, candidate.Courses, candidate.Sections, candidate.Waitlisted))
+		s.dbMock.ExpectRollback()
+
+		_, err := s.service.CreatePerson(context.Background(), personIn, false)
+
+		var duplicate *models.DuplicatePersonError
+		assert.ErrorAs(t, err, &duplicate)
+		assert.Equal(t, []models.Person{candidate}, duplicate.Candidates)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("duplicate allowed", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(insertPersonQuery)).
+			WithArgs(personIn.FirstName, personIn.LastName, personIn.Type, personIn.Age, personIn.ExternalID,
+				personIn.Email, personIn.Phone, personIn.DateOfBirth, personIn.DepartmentID).
+			WillReturnRows(pgxmock.NewRows(personReturnColumns).
+				AddRow(personOut.ID, personOut.FirstName, personOut.LastName, personOut.Type, personOut.Age, personOut.ExternalID, "", "", nil, time.Time{}, time.Time{}, nil))
+		s.expectEnrollment(personOut.ID, personIn.Sections)
+		s.dbMock.ExpectCommit()
+
+		actualReturn, err := s.service.CreatePerson(context.Background(), personIn, true)
+
+		assert.NoError(t, err)
+		assert.Equal(t, personOut, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("external id taken", func(t *testing.T) {
+		numbered := personIn
+		numbered.ExternalID = "S-1"
+
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(insertPersonQuery)).
+			WithArgs(numbered.FirstName, numbered.LastName, numbered.Type, numbered.Age, numbered.ExternalID,
+				numbered.Email, numbered.Phone, numbered.DateOfBirth, numbered.DepartmentID).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "person_external_id"})
+		s.dbMock.ExpectRollback()
+
+		_, err := s.service.CreatePerson(context.Background(), numbered, true)
+
+		assert.ErrorIs(t, err, models.ErrExternalIDTaken)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("email taken", func(t *testing.T) {
+		contactable := personIn
+		contactable.Email = "john@example.com"
+
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(insertPersonQuery)).
+			WithArgs(contactable.FirstName, contactable.LastName, contactable.Type, contactable.Age, contactable.ExternalID,
+				contactable.Email, contactable.Phone, contactable.DateOfBirth, contactable.DepartmentID).
+			WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "person_email"})
+		s.dbMock.ExpectRollback()
+
+		_, err := s.service.CreatePerson(context.Background(), contactable, true)
+
+		assert.ErrorIs(t, err, models.ErrEmailTaken)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
+
+func (s *personTestSuite) TestMergePersons() {
+	t := s.T()
+
+	lockQuery := `SELECT id, type, COALESCE(external_id, ''), COALESCE(email, ''), COALESCE(phone, ''), date_of_birth,
+		department_id
+		FROM person WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	copyDetailsQuery := `UPDATE person SET external_id = COALESCE(external_id, NULLIF($2, '')),`
+	lockColumns := []string{"id", "type", "external_id", "email", "phone", "date_of_birth", "department_id"}
+	deleteEnrollmentsQuery := `DELETE FROM enrollment WHERE person_id = $1 RETURNING section_id`
+	selectEnrollmentsQuery := `SELECT e.section_id, cs.course_id FROM enrollment e`
+	mergeWaitlistsQuery := `DELETE FROM waitlist w USING waitlist earlier`
+	moveWaitlistsQuery := `UPDATE waitlist SET person_id = $1 WHERE person_id = $2`
+	leaveWaitlistsQuery := `DELETE FROM waitlist
+		WHERE person_id = $1 AND section_id IN (SELECT section_id FROM enrollment WHERE person_id = $1)`
+	moveSectionsQuery := `UPDATE course_section SET instructor_id = $1 WHERE instructor_id = $2`
+	personColumns := []string{"id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}
+	dateOfBirth := time.Date(1955, time.October, 28, 0, 0, 0, 0, time.UTC)
+	departmentID := 1
+
+	// expectRest expects the rest of the merge to go through once the enrollments have been moved,
+	// with the duplicate's details given and the survivor read back.
+	expectRest := func(survivor models.Person, details []any) {
+		s.dbMock.ExpectExec(regexp.QuoteMeta(moveSectionsQuery)).
+			WithArgs(1, 2).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM person WHERE id = $1`)).
+			WithArgs(2).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(copyDetailsQuery)).
+			WithArgs(details...).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(`WHERE p.id = $1`)).
+			WithArgs(1).
+			WillReturnRows(pgxmock.NewRows(personColumns).
+				AddRow(survivor.ID, survivor.FirstName, survivor.LastName, survivor.Type, survivor.Age, survivor.ExternalID, "", "", nil, time.Time{}, time.Time{}, nil, survivor.Courses, survivor.Sections, survivor.Waitlisted))
+		s.dbMock.ExpectCommit()
+	}
+	// expectMoveStart expects the duplicate's enrollments to be dropped, the survivor's to be read,
+	// the sections of both to be locked and their waitlists to be merged.
+	expectMoveStart := func(dropped []int, held []int, locked []int) {
+		droppedRows := pgxmock.NewRows([]string{"section_id"})
+		for _, sectionID := range dropped {
+			droppedRows.AddRow(sectionID)
+		}
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(deleteEnrollmentsQuery)).
+			WithArgs(2).
+			WillReturnRows(droppedRows)
+		heldRows := pgxmock.NewRows([]string{"section_id", "course_id"})
+		for _, sectionID := range held {
+			heldRows.AddRow(sectionID, sectionID)
+		}
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(selectEnrollmentsQuery)).
+			WithArgs(1).
+			WillReturnRows(heldRows)
+		s.dbMock.ExpectExec(regexp.QuoteMeta(lockSectionsQuery)).
+			WithArgs(locked).
+			WillReturnResult(pgxmock.NewResult("SELECT", int64(len(locked))))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(mergeWaitlistsQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(moveWaitlistsQuery)).
+			WithArgs(1, 2).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 0))
+	}
+
+	t.Run("persons merged", func(t *testing.T) {
+		survivor := models.Person{ID: 1, FirstName: "Bill", LastName: "Gates", Type: "student", Age: 67, ExternalID: "S-2", Sections: []int{1, 2, 3}, Courses: []int{1, 2, 3}, Waitlisted: []int{}}
+
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows(lockColumns).
+				AddRow(1, "student", "", "bill@example.com", "", nil, nil).
+				AddRow(2, "student", "S-2", "", "555 0100", &dateOfBirth, &departmentID))
+		expectMoveStart([]int{3}, []int{1, 2}, []int{1, 2, 3})
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(seatsLeftQuery)).
+			WithArgs([]int{3}).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "seats"}).AddRow(3, 1))
+		s.dbMock.ExpectCopyFrom(pgx.Identifier{"enrollment"}, []string{"person_id", "section_id"}).
+			WillReturnResult(1)
+		s.dbMock.ExpectExec(regexp.QuoteMeta(leaveWaitlistsQuery)).
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+			WithArgs([]int{3}).
+			WillReturnRows(pgxmock.NewRows([]string{"person_id", "section_id"}))
+		expectRest(survivor, []any{1, "S-2", "", "555 0100", &dateOfBirth, &departmentID})
+
+		actualReturn, err := s.service.MergePersons(context.Background(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, survivor, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	for name, types := range map[string][2]string{
+		"professor merged into a student": {"student", "professor"},
+		"student merged into a professor": {"professor", "student"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s.dbMock.ExpectBegin()
+			s.dbMock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+				WithArgs([]int{1, 2}).
+				WillReturnRows(pgxmock.NewRows(lockColumns).
+					AddRow(1, types[0], "", "", "", nil, nil).
+					AddRow(2, types[1], "", "", "", nil, nil))
+			s.dbMock.ExpectRollback()
+
+			_, err := s.service.MergePersons(context.Background(), 1, 2)
+
+			assert.ErrorIs(t, err, models.ErrPersonTypesDiffer)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("seat held by both given to the waitlist", func(t *testing.T) {
+		survivor := models.Person{ID: 1, FirstName: "Bill", LastName: "Gates", Type: "student", Age: 67, Sections: []int{1}, Courses: []int{1}, Waitlisted: []int{}}
+
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows(lockColumns).
+				AddRow(1, "student", "", "", "", nil, nil).
+				AddRow(2, "student", "", "", "", nil, nil))
+		expectMoveStart([]int{1}, []int{1}, []int{1})
+		s.dbMock.ExpectExec(regexp.QuoteMeta(leaveWaitlistsQuery)).
+			WithArgs(1).
+			WillReturnResult(pgxmock.NewResult("DELETE", 0))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+			WithArgs([]int{1}).
+			WillReturnRows(pgxmock.NewRows([]string{"person_id", "section_id"}).AddRow(5, 1))
+		s.dbMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+			WithArgs(WaitlistPromotionChannel, `{"person_id":5,"section_id":1}`).
+			WillReturnResult(pgxmock.NewResult("SELECT", 1))
+		expectRest(survivor, []any{1, "", "", "", (*time.Time)(nil), (*int)(nil)})
+
+		actualReturn, err := s.service.MergePersons(context.Background(), 1, 2)
+
+		assert.NoError(t, err)
+		assert.Equal(t, survivor, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("person not found", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows(lockColumns).
+				AddRow(1, "student", "S-1", "", "", nil, nil))
+		s.dbMock.ExpectRollback()
+
+		_, err := s.service.MergePersons(context.Background(), 1, 2)
+
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+
+	t.Run("error moving enrollments", func(t *testing.T) {
+		s.dbMock.ExpectBegin()
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(lockQuery)).
+			WithArgs([]int{1, 2}).
+			WillReturnRows(pgxmock.NewRows(lockColumns).
+				AddRow(1, "student", "S-1", "", "", nil, nil).
+				AddRow(2, "student", "", "", "", nil, nil))
+		s.dbMock.ExpectQuery(regexp.QuoteMeta(deleteEnrollmentsQuery)).
+			WithArgs(2).
+			WillReturnError(errors.New("delete error"))
+		s.dbMock.ExpectRollback()
+
+		_, err := s.service.MergePersons(context.Background(), 1, 2)
+
+		assert.EqualError(t, err, "[in services.MergePersons] failed to delete duplicate enrollments: delete error")
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
+
+func TestNormalizedName(t *testing.T) {
+	tests := map[string]struct {
+		firstName string
+		lastName  string
+		expected  string
+	}{
+		"plain":           {firstName: "Bill", lastName: "Gates", expected: "billgates"},
+		"spacing":         {firstName: " bill ", lastName: "GATES ", expected: "billgates"},
+		"punctuation":     {firstName: "Jean-Luc", lastName: "O'Brien", expected: "jeanlucobrien"},
+		"accented":        {firstName: "Zoë", lastName: "Ñúñez", expected: "zoëñúñez"},
+		"digits are kept": {firstName: "R2", lastName: "D2", expected: "r2d2"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, normalizedName(tc.firstName, tc.lastName))
+		})
+	}
 }
 
 func (s *personTestSuite) TestDeletePerson() {
@@ -315,60 +819,70 @@ func (s *personTestSuite) TestDeletePerson() {
 	lastName := "Doe"
 
 	testCases := map[string]struct {
-		mockBeginErr         error
-		mockDeleteCoursesErr error
-		mockDeletePersonErr  error
-		mockRowsAffected     int64
-		mockCommitErr        error
-		expectedError        error
+		mockBeginErr             error
+		mockDeleteEnrollmentsErr error
+		mockDeletePersonErr      error
+		mockRowsAffected         int64
+		mockPromoted             bool
+		mockCommitErr            error
+		expectedError            error
 	}{
 		"successful deletion": {
-			mockBeginErr:         nil,
-			mockDeleteCoursesErr: nil,
-			mockDeletePersonErr:  nil,
-			mockRowsAffected:     1,
-			mockCommitErr:        nil,
-			expectedError:        nil,
+			mockBeginErr:             nil,
+			mockDeleteEnrollmentsErr: nil,
+			mockDeletePersonErr:      nil,
+			mockRowsAffected:         1,
+			mockCommitErr:            nil,
+			expectedError:            nil,
+		},
+		"seat given to waitlisted student": {
+			mockBeginErr:             nil,
+			mockDeleteEnrollmentsErr: nil,
+			mockDeletePersonErr:      nil,
+			mockRowsAffected:         1,
+			mockPromoted:             true,
+			mockCommitErr:            nil,
+			expectedError:            nil,
 		},
 		"begin transaction error": {
-			mockBeginErr:         errors.New("transaction begin error"),
-			mockDeleteCoursesErr: nil,
-			mockDeletePersonErr:  nil,
-			mockRowsAffected:     0,
-			mockCommitErr:        nil,
-			expectedError:        fmt.Errorf("[in services.DeletePerson] failed to begin transaction: %w", errors.New("transaction begin error")),
+			mockBeginErr:             errors.New("transaction begin error"),
+			mockDeleteEnrollmentsErr: nil,
+			mockDeletePersonErr:      nil,
+			mockRowsAffected:         0,
+			mockCommitErr:            nil,
+			expectedError:            fmt.Errorf("[in services.DeletePerson] failed to begin transaction: %w", errors.New("transaction begin error")),
 		},
-		"error deleting courses": {
-			mockBeginErr:         nil,
-			mockDeleteCoursesErr: errors.New("delete courses error"),
-			mockDeletePersonErr:  nil,
-			mockRowsAffected:     0,
-			mockCommitErr:        nil,
-			expectedError:        fmt.Errorf("[in services.DeletePerson] failed to delete courses: %w", errors.New("delete courses error")),
+		"error deleting enrollments": {
+			mockBeginErr:             nil,
+			mockDeleteEnrollmentsErr: errors.New("delete enrollments error"),
+			mockDeletePersonErr:      nil,
+			mockRowsAffected:         0,
+			mockCommitErr:            nil,
+			expectedError:            fmt.Errorf("[in services.DeletePerson] failed to delete enrollments: %w", errors.New("delete enrollments error")),
 		},
 		"error deleting person": {
-			mockBeginErr:         nil,
-			mockDeleteCoursesErr: nil,
-			mockDeletePersonErr:  errors.New("delete person error"),
-			mockRowsAffected:     0,
-			mockCommitErr:        nil,
-			expectedError:        fmt.Errorf("[in services.DeletePerson] failed to delete person: %w", errors.New("delete person error")),
+			mockBeginErr:             nil,
+			mockDeleteEnrollmentsErr: nil,
+			mockDeletePersonErr:      errors.New("delete person error"),
+			mockRowsAffected:         0,
+			mockCommitErr:            nil,
+			expectedError:            fmt.Errorf("[in services.DeletePerson] failed to delete person: %w", errors.New("delete person error")),
 		},
 		"no person found": {
-			mockBeginErr:         nil,
-			mockDeleteCoursesErr: nil,
-			mockDeletePersonErr:  nil,
-			mockRowsAffected:     0,
-			mockCommitErr:        nil,
-			expectedError:        fmt.Errorf("[in services.DeletePerson] no person found with last name: %s", lastName),
+			mockBeginErr:             nil,
+			mockDeleteEnrollmentsErr: nil,
+			mockDeletePersonErr:      nil,
+			mockRowsAffected:         0,
+			mockCommitErr:            nil,
+			expectedError:            fmt.Errorf("[in services.DeletePerson] no person found with last name: %s", lastName),
 		},
 		"commit transaction error": {
-			mockBeginErr:         nil,
-			mockDeleteCoursesErr: nil,
-			mockDeletePersonErr:  nil,
-			mockRowsAffected:     1,
-			mockCommitErr:        errors.New("commit transaction error"),
-			expectedError:        fmt.Errorf("[in services.DeletePerson] failed to commit transaction: %w", errors.New("commit transaction error")),
+			mockBeginErr:             nil,
+			mockDeleteEnrollmentsErr: nil,
+			mockDeletePersonErr:      nil,
+			mockRowsAffected:         1,
+			mockCommitErr:            errors.New("commit transaction error"),
+			expectedError:            fmt.Errorf("[in services.DeletePerson] failed to commit transaction: %w", errors.New("commit transaction error")),
 		},
 	}
 
@@ -381,21 +895,21 @@ func (s *personTestSuite) TestDeletePerson() {
 			}
 
 			if tc.mockBeginErr == nil {
-				deleteCoursesQuery := `
-					DELETE FROM person_course WHERE person_id IN 
+				deleteEnrollmentsQuery := `
+					DELETE FROM enrollment WHERE person_id IN 
 					(SELECT id FROM person WHERE LOWER(last_name) = LOWER($1))
 				`
-				if tc.mockDeleteCoursesErr != nil {
-					s.dbMock.ExpectExec(regexp.QuoteMeta(deleteCoursesQuery)).
+				if tc.mockDeleteEnrollmentsErr != nil {
+					s.dbMock.ExpectQuery(regexp.QuoteMeta(deleteEnrollmentsQuery)).
 						WithArgs(lastName).
-						WillReturnError(tc.mockDeleteCoursesErr)
+						WillReturnError(tc.mockDeleteEnrollmentsErr)
 				} else {
-					s.dbMock.ExpectExec(regexp.QuoteMeta(deleteCoursesQuery)).
+					s.dbMock.ExpectQuery(regexp.QuoteMeta(deleteEnrollmentsQuery)).
 						WithArgs(lastName).
-						WillReturnResult(sqlmock.NewResult(1, 1)) // Simulate course deletion
+						WillReturnRows(pgxmock.NewRows([]string{"section_id"}).AddRow(1)) // Simulate enrollment deletion
 				}
 
-				if tc.mockDeleteCoursesErr == nil {
+				if tc.mockDeleteEnrollmentsErr == nil {
 					deletePersonQuery := `DELETE FROM person WHERE LOWER(last_name) = LOWER($1)`
 					if tc.mockDeletePersonErr != nil {
 						s.dbMock.ExpectExec(regexp.QuoteMeta(deletePersonQuery)).
@@ -404,10 +918,26 @@ func (s *personTestSuite) TestDeletePerson() {
 					} else {
 						s.dbMock.ExpectExec(regexp.QuoteMeta(deletePersonQuery)).
 							WithArgs(lastName).
-							WillReturnResult(sqlmock.NewResult(1, tc.mockRowsAffected)) // Mock rows affected
+							WillReturnResult(pgxmock.NewResult("DELETE", tc.mockRowsAffected)) // Mock rows affected
 					}
 
 					if tc.mockDeletePersonErr == nil && tc.mockRowsAffected > 0 {
+						s.dbMock.ExpectExec(regexp.QuoteMeta(lockSectionsQuery)).
+							WithArgs([]int{1}).
+							WillReturnResult(pgxmock.NewResult("SELECT", 1))
+						promoted := pgxmock.NewRows([]string{"person_id", "section_id"})
+						if tc.mockPromoted {
+							promoted.AddRow(5, 1)
+						}
+						s.dbMock.ExpectQuery(regexp.QuoteMeta(promoteQuery)).
+							WithArgs([]int{1}).
+							WillReturnRows(promoted)
+						if tc.mockPromoted {
+							s.dbMock.ExpectExec(regexp.QuoteMeta(`SELECT pg_notify($1, $2)`)).
+								WithArgs(WaitlistPromotionChannel, `{"person_id":5,"section_id":1}`).
+								WillReturnResult(pgxmock.NewResult("SELECT", 1))
+						}
+
 						// Mock commit
 						if tc.mockCommitErr != nil {
 							s.dbMock.ExpectCommit().WillReturnError(tc.mockCommitErr)
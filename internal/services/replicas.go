@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/logging"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// reader runs read-only queries on a replica when one is available and on the primary otherwise.
+type reader struct {
+	primary  DB
+	replicas *database.ReplicaSet[Replica]
+}
+
+// pick returns the database a read should go to, and the replica chosen, if any.
+func (r reader) pick(ctx context.Context) (DB, Replica) {
+	if tx, ok := database.SharedTxFrom(ctx); ok {
+		return tx, nil
+	}
+	if database.PrimaryRequired(ctx) {
+		return r.primary, nil
+	}
+	replica, ok := r.replicas.Next()
+	if !ok {
+		return r.primary, nil
+	}
+	return replica, replica
+}
+
+// Query runs a read-only query, retrying it on the primary if the replica cannot be reached.
+func (r reader) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	db, replica := r.pick(ctx)
+	rows, err := db.Query(ctx, sql, args...)
+	if replica != nil && r.unreachable(ctx, replica, err) {
+		return r.primary.Query(ctx, sql, args...)
+	}
+	return rows, err
+}
+
+// QueryRow runs a read-only query returning at most one row. As with pgx, errors are deferred
+// until Scan, which is also when a failed replica falls back to the primary.
+func (r reader) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	db, replica := r.pick(ctx)
+	return fallbackRow{ctx: ctx, reader: r, db: db, replica: replica, sql: sql, args: args}
+}
+
+type fallbackRow struct {
+	ctx     context.Context
+	reader  reader
+	db      DB
+	replica Replica
+	sql     string
+	args    []any
+}
+
+func (row fallbackRow) Scan(dest ...any) error {
+	err := row.db.QueryRow(row.ctx, row.sql, row.args...).Scan(dest...)
+	if row.replica != nil && row.reader.unreachable(row.ctx, row.replica, err) {
+		return row.reader.primary.QueryRow(row.ctx, row.sql, row.args...).Scan(dest...)
+	}
+	return err
+}
+
+// unreachable reports whether err means the replica, rather than the query, failed, and if so takes
+// the replica out of rotation. Errors caused by the caller's own context are not held against it.
+func (r reader) unreachable(ctx context.Context, replica Replica, err error) bool {
+	if err == nil || ctx.Err() != nil || errors.Is(err, pgx.ErrNoRows) || !database.Retryable(err) {
+		return false
+	}
+	logging.Subsystem(ctx, logging.SubsystemServices).Warn("Read replica failed, retrying on primary", "err", err)
+	r.replicas.MarkDown(replica, err)
+	return true
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"regexp"
+	"syscall"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCourseServiceReplicaRouting(t *testing.T) {
+	query := regexp.QuoteMeta("SELECT id, code, name, credits, description, level, active, department_id, capacity FROM course WHERE id = $1")
+	course := models.Course{ID: 1, Code: "CS220", Name: "Databases", Credits: 3, Level: "undergraduate", Active: true}
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	testCases := map[string]struct {
+		ctx            context.Context
+		setup          func(primary, replica pgxmock.PgxPoolIface)
+		expectedReturn models.Course
+		expectedError  error
+	}{
+		"read from replica": {
+			ctx: context.Background(),
+			setup: func(primary, replica pgxmock.PgxPoolIface) {
+				replica.ExpectQuery(query).WithArgs(1).
+					WillReturnRows(testutil.MustStructsToRows([]models.Course{course}))
+			},
+			expectedReturn: course,
+		},
+		"read your writes from primary": {
+			ctx: database.WithPrimary(context.Background()),
+			setup: func(primary, replica pgxmock.PgxPoolIface) {
+				primary.ExpectQuery(query).WithArgs(1).
+					WillReturnRows(testutil.MustStructsToRows([]models.Course{course}))
+			},
+			expectedReturn: course,
+		},
+		"unreachable replica falls back to primary": {
+			ctx: context.Background(),
+			setup: func(primary, replica pgxmock.PgxPoolIface) {
+				replica.ExpectQuery(query).WithArgs(1).WillReturnError(refused)
+				primary.ExpectQuery(query).WithArgs(1).
+					WillReturnRows(testutil.MustStructsToRows([]models.Course{course}))
+			},
+			expectedReturn: course,
+		},
+		"missing row is not a replica failure": {
+			ctx: context.Background(),
+			setup: func(primary, replica pgxmock.PgxPoolIface) {
+				replica.ExpectQuery(query).WithArgs(1).WillReturnError(pgx.ErrNoRows)
+			},
+			expectedReturn: models.Course{},
+			expectedError:  fmt.Errorf("[in services.GetCourseByIDByID] no course found with id %d: %w", 1, models.ErrNotFound),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			primary, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			replica, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			replicas := database.NewReplicaSet(
+				slog.New(slog.NewTextHandler(io.Discard, nil)),
+				database.Replica[Replica]{Name: "replica", DB: replica},
+			)
+			service := NewCourseService(primary, WithReplicas(replicas))
+
+			tc.setup(primary, replica)
+
+			actualReturn, err := service.GetCourseByID(tc.ctx, 1)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+			assert.NoError(t, primary.ExpectationsWereMet())
+			assert.NoError(t, replica.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestPersonServiceReplicaFallback(t *testing.T) {
+	primary, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	replica, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	replicas := database.NewReplicaSet(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		database.Replica[Replica]{Name: "replica", DB: replica},
+	)
+	service := NewPersonService(primary, WithReplicas(replicas))
+
+	columns := []string{"person_id", "first_name", "last_name", "type", "age", "external_id", "email", "phone", "date_of_birth", "created_at", "updated_at", "department_id", "course_ids", "section_ids", "waitlisted"}
+	replica.ExpectQuery("FROM person p").WillReturnError(errors.New("unexpected EOF"))
+	primary.ExpectQuery("FROM person p").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(1, "John", "Doe", "student", 25, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{1}, []int{1}, []int{}))
+	// The failed replica is out of rotation, so the next read goes straight to the primary.
+	primary.ExpectQuery("FROM person p").
+		WillReturnRows(pgxmock.NewRows(columns).AddRow(1, "John", "Doe", "student", 25, "", "", "", nil, time.Time{}, time.Time{}, nil, []int{1}, []int{1}, []int{}))
+
+	for range 2 {
+		persons, err := service.ListPersons(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, persons, 1)
+	}
+
+	assert.NoError(t, primary.ExpectationsWereMet())
+	assert.NoError(t, replica.ExpectationsWereMet())
+}
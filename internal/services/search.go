@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SearchService searches persons and courses with Postgres full-text search, which matches whole
+// words and prefixes of them, and pg_trgm trigram similarity, which matches misspellings.
+type SearchService struct {
+	database         DB
+	reads            reader
+	statementTimeout time.Duration
+}
+
+func NewSearchService(db DB, opts ...Option) *SearchService {
+	options := newServiceOptions(opts)
+	return &SearchService{
+		database:         db,
+		reads:            reader{primary: db, replicas: options.replicas},
+		statementTimeout: options.statementTimeout,
+	}
+}
+
+// Available reports whether the pg_trgm extension Search relies on is installed.
+func (s *SearchService) Available(ctx context.Context) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')`
+	ctx, span := startQuerySpan(ctx, "SearchService.Available", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	var available bool
+	if err := s.database.QueryRow(ctx, query).Scan(&available); err != nil {
+		tracing.RecordError(span, err)
+		return false, fmt.Errorf("[in services.Available] failed to check for pg_trgm: %w", err)
+	}
+	return available, nil
+}
+
+// Search returns up to limit persons and courses matching text, best match first. A person matches
+// on their full name and a course on its name.
+func (s *SearchService) Search(ctx context.Context, text string, limit int) ([]models.SearchResult, error) {
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+	// Every term must match, the last one as a prefix so that results appear as the user types.
+	prefixQuery := strings.Join(terms, " & ") + ":*"
+
+	query := `SELECT type, id, title, highlight, rank FROM (
+	SELECT 'person' AS type, p.id, p.first_name || ' ' || p.last_name AS title,
+	ts_headline('simple', p.first_name || ' ' || p.last_name, to_tsquery('simple', $2), $4) AS highlight,
+	ts_rank(to_tsvector('simple', p.first_name || ' ' || p.last_name), to_tsquery('simple', $2))
+	+ word_similarity($1, p.first_name || ' ' || p.last_name) AS rank
+	FROM person p
+	WHERE to_tsvector('simple', p.first_name || ' ' || p.last_name) @@ to_tsquery('simple', $2)
+	OR $1 <% (p.first_name || ' ' || p.last_name)
+	UNION ALL
+	SELECT 'course', c.id, c.name,
+	ts_headline('simple', c.name, to_tsquery('simple', $2), $4),
+	ts_rank(to_tsvector('simple', c.name), to_tsquery('simple', $2)) + word_similarity($1, c.name)
+	FROM course c
+	WHERE to_tsvector('simple', c.name) @@ to_tsquery('simple', $2)
+	OR $1 <% c.name
+	) results
+	ORDER BY rank DESC, type, id
+	LIMIT $3`
+	ctx, span := startQuerySpan(ctx, "SearchService.Search", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	headlineOptions := fmt.Sprintf("StartSel=%s, StopSel=%s, HighlightAll=true", models.HighlightStart, models.HighlightStop)
+	rows, err := s.reads.Query(ctx, query, strings.Join(terms, " "), prefixQuery, limit, headlineOptions)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.SearchResult{}, fmt.Errorf("[in services.Search] failed to search: %w", err)
+	}
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.SearchResult, error) {
+		var result models.SearchResult
+		err := row.Scan(&result.Type, &result.ID, &result.Title, &result.Highlight, &result.Rank)
+		return result, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.SearchResult{}, fmt.Errorf("[in services.Search] failed to scan result: %w", err)
+	}
+	setRowsReturned(span, len(results))
+	return results, nil
+}
+
+// searchTerms splits text into lower-case words of letters and digits. Everything else separates
+// words, so nothing the user types can change the meaning of a tsquery built from the terms.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"go-api-tech-challenge/internal/models"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+type personLister interface {
+	ListPersons(ctx context.Context) ([]models.Person, error)
+}
+
+type courseLister interface {
+	ListCourses(ctx context.Context, filter models.CourseFilter) ([]models.Course, error)
+}
+
+// SubstringSearchService searches persons and courses without help from the database, for
+// backends that lack Postgres full-text search or pg_trgm. A result must contain every word of the
+// query, ignoring case; misspellings do not match. It reads the full lists, which are cached, so it
+// suits small data sets.
+type SubstringSearchService struct {
+	persons personLister
+	courses courseLister
+}
+
+func NewSubstringSearchService(persons personLister, courses courseLister) *SubstringSearchService {
+	return &SubstringSearchService{persons: persons, courses: courses}
+}
+
+// Search returns up to limit persons and courses containing every word of text, ranked by how much
+// of the title the words cover.
+func (s *SubstringSearchService) Search(ctx context.Context, text string, limit int) ([]models.SearchResult, error) {
+	ctx, span := tracer.Start(ctx, "SubstringSearchService.Search")
+	defer span.End()
+
+	terms := searchTerms(text)
+	if len(terms) == 0 {
+		return []models.SearchResult{}, nil
+	}
+
+	persons, err := s.persons.ListPersons(ctx)
+	if err != nil {
+		return []models.SearchResult{}, fmt.Errorf("[in services.Search] failed to list persons: %w", err)
+	}
+	courses, err := s.courses.ListCourses(ctx, models.CourseFilter{})
+	if err != nil {
+		return []models.SearchResult{}, fmt.Errorf("[in services.Search] failed to list courses: %w", err)
+	}
+
+	results := []models.SearchResult{}
+	for _, person := range persons {
+		if result, ok := matchSubstrings(terms, person.FirstName+" "+person.LastName); ok {
+			result.Type, result.ID = models.SearchTypePerson, person.ID
+			results = append(results, result)
+		}
+	}
+	for _, course := range courses {
+		if result, ok := matchSubstrings(terms, course.Name); ok {
+			result.Type, result.ID = models.SearchTypeCourse, course.ID
+			results = append(results, result)
+		}
+	}
+
+	// Ties are broken the same way as SearchService breaks them.
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		if results[i].Type != results[j].Type {
+			return results[i].Type < results[j].Type
+		}
+		return results[i].ID < results[j].ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	setRowsReturned(span, len(results))
+	return results, nil
+}
+
+// matchSubstrings reports whether title contains every one of terms, which must be lower case. A
+// match is ranked by the fraction of the title's letters and digits that the terms cover, and
+// highlighted at every place a term occurs.
+func matchSubstrings(terms []string, title string) (models.SearchResult, bool) {
+	runes := []rune(title)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	marked := make([]bool, len(runes))
+	for _, term := range terms {
+		found := false
+		termRunes := []rune(term)
+		for i := 0; i+len(termRunes) <= len(lower); i++ {
+			if string(lower[i:i+len(termRunes)]) == term {
+				found = true
+				for j := i; j < i+len(termRunes); j++ {
+					marked[j] = true
+				}
+			}
+		}
+		if !found {
+			return models.SearchResult{}, false
+		}
+	}
+
+	var highlight strings.Builder
+	covered, letters := 0, 0
+	for i, r := range runes {
+		if i == 0 || marked[i] != marked[i-1] {
+			switch {
+			case marked[i]:
+				highlight.WriteString(models.HighlightStart)
+			case i > 0:
+				highlight.WriteString(models.HighlightStop)
+			}
+		}
+		highlight.WriteRune(r)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letters++
+			if marked[i] {
+				covered++
+			}
+		}
+	}
+	if len(runes) > 0 && marked[len(runes)-1] {
+		highlight.WriteString(models.HighlightStop)
+	}
+
+	return models.SearchResult{
+		Title:     title,
+		Highlight: highlight.String(),
+		Rank:      float64(covered) / float64(letters),
+	}, true
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchServiceSearch(t *testing.T) {
+	searchQuery := regexp.QuoteMeta(`SELECT type, id, title, highlight, rank FROM (`)
+	headlineOptions := "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+	columns := []string{"type", "id", "title", "highlight", "rank"}
+
+	tests := map[string]struct {
+		text            string
+		expectQuery     bool
+		mockArgs        []any
+		mockRows        *pgxmock.Rows
+		mockErr         error
+		expectedResults []models.SearchResult
+		expectedErr     error
+	}{
+		"results ranked by the database": {
+			text:        "Bill  GAT",
+			expectQuery: true,
+			mockArgs:    []any{"bill gat", "bill & gat:*", 10, headlineOptions},
+			mockRows: pgxmock.NewRows(columns).
+				AddRow("person", 4, "Bill Gates", "<mark>Bill</mark> <mark>Gates</mark>", 1.2),
+			expectedResults: []models.SearchResult{
+				{Type: "person", ID: 4, Title: "Bill Gates", Highlight: "<mark>Bill</mark> <mark>Gates</mark>", Rank: 1.2},
+			},
+		},
+		"punctuation cannot reach the tsquery": {
+			text:            "o'brien & !",
+			expectQuery:     true,
+			mockArgs:        []any{"o brien", "o & brien:*", 10, headlineOptions},
+			mockRows:        pgxmock.NewRows(columns),
+			expectedResults: []models.SearchResult{},
+		},
+		"no words": {
+			text:            "&|!",
+			expectedResults: []models.SearchResult{},
+		},
+		"query error": {
+			text:            "bill",
+			expectQuery:     true,
+			mockArgs:        []any{"bill", "bill:*", 10, headlineOptions},
+			mockErr:         errors.New("test error"),
+			expectedResults: []models.SearchResult{},
+			expectedErr:     fmt.Errorf("[in services.Search] failed to search: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockDB, err := pgxmock.NewPool()
+			assert.NoError(t, err)
+			service := NewSearchService(mockDB)
+
+			if tc.expectQuery {
+				expectation := mockDB.ExpectQuery(searchQuery).WithArgs(tc.mockArgs...)
+				if tc.mockErr != nil {
+					expectation.WillReturnError(tc.mockErr)
+				} else {
+					expectation.WillReturnRows(tc.mockRows)
+				}
+			}
+
+			results, err := service.Search(context.Background(), tc.text, 10)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedResults, results)
+			assert.NoError(t, mockDB.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestSearchServiceAvailable(t *testing.T) {
+	mockDB, err := pgxmock.NewPool()
+	assert.NoError(t, err)
+	service := NewSearchService(mockDB)
+
+	mockDB.ExpectQuery(regexp.QuoteMeta(`SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_trgm')`)).
+		WillReturnRows(pgxmock.NewRows([]string{"exists"}).AddRow(false))
+
+	available, err := service.Available(context.Background())
+
+	assert.NoError(t, err)
+	assert.False(t, available)
+	assert.NoError(t, mockDB.ExpectationsWereMet())
+}
+
+type stubLister struct {
+	persons []models.Person
+	courses []models.Course
+	err     error
+}
+
+func (l stubLister) ListPersons(ctx context.Context) ([]models.Person, error) {
+	return l.persons, l.err
+}
+
+func (l stubLister) ListCourses(ctx context.Context, filter models.CourseFilter) ([]models.Course, error) {
+	return l.courses, l.err
+}
+
+func TestSubstringSearchService(t *testing.T) {
+	lists := stubLister{
+		persons: []models.Person{
+			{ID: 1, FirstName: "Steve", LastName: "Jobs"},
+			{ID: 4, FirstName: "Bill", LastName: "Gates"},
+			{ID: 6, FirstName: "Billie", LastName: "Holiday"},
+		},
+		courses: []models.Course{
+			{ID: 1, Name: "Programming"},
+			{ID: 3, Name: "UI Design"},
+		},
+	}
+
+	tests := map[string]struct {
+		lists           stubLister
+		text            string
+		limit           int
+		expectedResults []models.SearchResult
+		expectedErr     error
+	}{
+		"best coverage first": {
+			lists: lists,
+			text:  "BILL",
+			limit: 10,
+			expectedResults: []models.SearchResult{
+				{Type: "person", ID: 4, Title: "Bill Gates", Highlight: "<mark>Bill</mark> Gates", Rank: 4.0 / 9},
+				{Type: "person", ID: 6, Title: "Billie Holiday", Highlight: "<mark>Bill</mark>ie Holiday", Rank: 4.0 / 13},
+			},
+		},
+		"every word must match": {
+			lists: lists,
+			text:  "bill gat",
+			limit: 10,
+			expectedResults: []models.SearchResult{
+				{Type: "person", ID: 4, Title: "Bill Gates", Highlight: "<mark>Bill</mark> <mark>Gat</mark>es", Rank: 7.0 / 9},
+			},
+		},
+		"persons and courses": {
+			lists: lists,
+			text:  "s",
+			limit: 2,
+			expectedResults: []models.SearchResult{
+				{Type: "person", ID: 1, Title: "Steve Jobs", Highlight: "<mark>S</mark>teve Job<mark>s</mark>", Rank: 2.0 / 9},
+				{Type: "course", ID: 3, Title: "UI Design", Highlight: "UI De<mark>s</mark>ign", Rank: 1.0 / 8},
+			},
+		},
+		"no match": {
+			lists:           lists,
+			text:            "gtaes",
+			limit:           10,
+			expectedResults: []models.SearchResult{},
+		},
+		"list error": {
+			lists:           stubLister{err: errors.New("test error")},
+			text:            "bill",
+			limit:           10,
+			expectedResults: []models.SearchResult{},
+			expectedErr:     fmt.Errorf("[in services.Search] failed to list persons: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			service := NewSubstringSearchService(tc.lists, tc.lists)
+
+			results, err := service.Search(context.Background(), tc.text, tc.limit)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expectedResults, results)
+		})
+	}
+}
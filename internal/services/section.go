@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ListCourseSections returns the sections of the course with the given ID, by term and number. A
+// non-zero termID keeps only the sections in that term. It wraps models.ErrNotFound when there is
+// no such course.
+func (s *CourseService) ListCourseSections(ctx context.Context, courseID int, termID int) ([]models.Section, error) {
+	if _, err := s.GetCourseByID(ctx, courseID); err != nil {
+		return []models.Section{}, fmt.Errorf("[in services.ListCourseSections] %w", err)
+	}
+
+	sections, err := cache.Load(ctx, s.cache, courseSectionsKey(courseID), func(ctx context.Context) ([]models.Section, error) {
+		return s.listCourseSections(ctx, courseID)
+	})
+	if err != nil || termID == 0 {
+		return sections, err
+	}
+
+	inTerm := []models.Section{}
+	for _, section := range sections {
+		if section.TermID == termID {
+			inTerm = append(inTerm, section)
+		}
+	}
+	return inTerm, nil
+}
+
+func (s *CourseService) listCourseSections(ctx context.Context, courseID int) ([]models.Section, error) {
+	query := `SELECT cs.id, cs.course_id, cs.term_id, cs.number, cs.instructor_id FROM course_section cs
+	JOIN term t ON t.id = cs.term_id
+	WHERE cs.course_id = $1
+	ORDER BY t.starts_on asc, cs.term_id asc, cs.number asc`
+	ctx, span := startQuerySpan(ctx, "CourseService.ListCourseSections", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(ctx, query, courseID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Section{}, fmt.Errorf("[in services.ListCourseSections] failed to get sections: %w", err)
+	}
+	sections, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Section, error) {
+		var section models.Section
+		err := row.Scan(sectionFields(&section)...)
+		return section, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Section{}, fmt.Errorf("[in services.ListCourseSections] failed to scan sections: %w", err)
+	}
+	setRowsReturned(span, len(sections))
+
+	return sections, nil
+}
+
+// CreateSection adds a section to a course. It wraps models.ErrNotFound when there is no such
+// course, models.ErrTermNotFound when there is no such term, models.ErrSectionTaken when the
+// course already has a section with the same number in the term, and
+// models.ErrInstructorNotProfessor when the instructor is set and is not a professor.
+func (s *CourseService) CreateSection(ctx context.Context, section models.Section) (models.Section, error) {
+	if _, err := s.GetCourseByID(ctx, section.CourseID); err != nil {
+		return models.Section{}, fmt.Errorf("[in services.CreateSection] %w", err)
+	}
+
+	// The instructor is checked in the same statement, so that they cannot stop being a professor
+	// in between.
+	query := `INSERT INTO course_section (course_id, term_id, number, instructor_id)
+	SELECT $1, $2, $3, $4
+	WHERE $4::int IS NULL OR EXISTS (SELECT 1 FROM person WHERE id = $4 AND type = 'professor')
+	RETURNING id`
+	ctx, span := startQuerySpan(ctx, "CourseService.CreateSection", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := conn(ctx, s.database).QueryRow(ctx, query, section.CourseID, section.TermID, section.Number,
+		section.InstructorID).Scan(&section.ID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Section{}, fmt.Errorf("[in services.CreateSection] %w", models.ErrInstructorNotProfessor)
+		}
+		return models.Section{}, fmt.Errorf("[in services.CreateSection] failed to create section: %w", constraintError(err))
+	}
+	s.cache.Invalidate(ctx, courseKeyPrefix)
+	return section, nil
+}
+
+// sectionFields returns where to scan the columns every section query selects, in order: id,
+// course_id, term_id, number and instructor_id.
+func sectionFields(section *models.Section) []any {
+	return []any{&section.ID, &section.CourseID, &section.TermID, &section.Number, &section.InstructorID}
+}
+
+// lockCourseSections locks every section of the course until the transaction ends, in ID order like
+// lockSections, and returns their IDs.
+func (s *CourseService) lockCourseSections(ctx context.Context, tx pgx.Tx, courseID int) ([]int, error) {
+	query := `SELECT id FROM course_section WHERE course_id = $1 ORDER BY id FOR UPDATE`
+	ctx, span := startQuerySpan(ctx, "lock course sections", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, courseID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	sectionIDs, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsReturned(span, len(sectionIDs))
+	return sectionIDs, nil
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *testSuit) TestListCourseSections() {
+	t := s.T()
+
+	course := models.Course{ID: 1, Code: "CS101", Name: "Programming", Credits: 4, Level: "undergraduate", Active: true}
+	instructorID := 2
+	sections := []models.Section{
+		{ID: 1, CourseID: 1, TermID: 1, Number: 1, InstructorID: &instructorID},
+		{ID: 4, CourseID: 1, TermID: 1, Number: 2},
+		{ID: 5, CourseID: 1, TermID: 2, Number: 1, InstructorID: &instructorID},
+	}
+	getQuery := regexp.QuoteMeta(`FROM course WHERE id = $1`)
+	sectionsQuery := regexp.QuoteMeta(`SELECT cs.id, cs.course_id, cs.term_id, cs.number, cs.instructor_id FROM course_section cs`)
+
+	testCases := map[string]struct {
+		termID         int
+		expectedReturn []models.Section
+	}{
+		"every term": {
+			expectedReturn: sections,
+		},
+		"one term": {
+			termID:         2,
+			expectedReturn: sections[2:],
+		},
+		"term without sections": {
+			termID:         3,
+			expectedReturn: []models.Section{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s.dbMock.ExpectQuery(getQuery).WithArgs(course.ID).
+				WillReturnRows(testutil.MustStructsToRows([]models.Course{course}))
+			s.dbMock.ExpectQuery(sectionsQuery).WithArgs(course.ID).
+				WillReturnRows(testutil.MustStructsToRows(sections))
+
+			actualReturn, err := s.service.ListCourseSections(context.Background(), course.ID, tc.termID)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("course not found", func(t *testing.T) {
+		s.dbMock.ExpectQuery(getQuery).WithArgs(9).WillReturnError(pgx.ErrNoRows)
+
+		actualReturn, err := s.service.ListCourseSections(context.Background(), 9, 0)
+
+		assert.ErrorIs(t, err, models.ErrNotFound)
+		assert.Equal(t, []models.Section{}, actualReturn)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
+
+func (s *testSuit) TestCreateSection() {
+	t := s.T()
+
+	course := models.Course{ID: 1, Code: "CS101", Name: "Programming", Credits: 4, Level: "undergraduate", Active: true}
+	instructorID := 2
+	section := models.Section{CourseID: 1, TermID: 2, Number: 1, InstructorID: &instructorID}
+	created := section
+	created.ID = 4
+	getQuery := regexp.QuoteMeta(`FROM course WHERE id = $1`)
+	insertQuery := regexp.QuoteMeta(`INSERT INTO course_section (course_id, term_id, number, instructor_id)`)
+
+	testCases := map[string]struct {
+		mockRows       *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Section
+		expectedError  error
+	}{
+		"section created successfully": {
+			mockRows:       pgxmock.NewRows([]string{"id"}).AddRow(created.ID),
+			expectedReturn: created,
+		},
+		"instructor not a professor": {
+			mockReturnErr: pgx.ErrNoRows,
+			expectedError: models.ErrInstructorNotProfessor,
+		},
+		"term missing": {
+			mockReturnErr: &pgconn.PgError{Code: "23503", ConstraintName: "course_section_term"},
+			expectedError: models.ErrTermNotFound,
+		},
+		"number taken": {
+			mockReturnErr: &pgconn.PgError{Code: "23505", ConstraintName: "course_section_number"},
+			expectedError: models.ErrSectionTaken,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s.dbMock.ExpectQuery(getQuery).WithArgs(course.ID).
+				WillReturnRows(testutil.MustStructsToRows([]models.Course{course}))
+			mock := s.dbMock.ExpectQuery(insertQuery).
+				WithArgs(section.CourseID, section.TermID, section.Number, section.InstructorID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockRows)
+			}
+
+			actualReturn, err := s.service.CreateSection(context.Background(), section)
+
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+
+	t.Run("course not found", func(t *testing.T) {
+		s.dbMock.ExpectQuery(getQuery).WithArgs(1).WillReturnError(pgx.ErrNoRows)
+
+		_, err := s.service.CreateSection(context.Background(), section)
+
+		assert.ErrorIs(t, err, models.ErrNotFound)
+
+		err = s.dbMock.ExpectationsWereMet()
+		assert.NoError(t, err)
+	})
+}
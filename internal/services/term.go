@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-api-tech-challenge/internal/cache"
+	"go-api-tech-challenge/internal/logging"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type TermService struct {
+	database         DB
+	reads            reader
+	cache            *cache.Aside
+	statementTimeout time.Duration
+}
+
+func NewTermService(db DB, opts ...Option) *TermService {
+	options := newServiceOptions(opts)
+	return &TermService{
+		database:         db,
+		reads:            reader{primary: db, replicas: options.replicas},
+		cache:            options.cache,
+		statementTimeout: options.statementTimeout,
+	}
+}
+
+// ListTerms returns every term, earliest first.
+func (s *TermService) ListTerms(ctx context.Context) ([]models.Term, error) {
+	return cache.Load(ctx, s.cache, termListKey, s.listTerms)
+}
+
+func (s *TermService) listTerms(ctx context.Context) ([]models.Term, error) {
+	query := `SELECT id, name, starts_on, ends_on FROM term ORDER BY starts_on asc, id asc`
+	ctx, span := startQuerySpan(ctx, "TermService.ListTerms", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := s.reads.Query(ctx, query)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Term{}, fmt.Errorf("[in services.ListTerms] failed to get terms: %w", err)
+	}
+	terms, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.Term, error) {
+		var term models.Term
+		err := row.Scan(termFields(&term)...)
+		return term, err
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return []models.Term{}, fmt.Errorf("[in services.ListTerms] failed to scan terms: %w", err)
+	}
+	setRowsReturned(span, len(terms))
+	logging.Subsystem(ctx, logging.SubsystemServices).Debug("listed terms", "count", len(terms))
+
+	return terms, nil
+}
+
+// GetTermByID returns the term with the given ID. It wraps models.ErrNotFound when there is none.
+func (s *TermService) GetTermByID(ctx context.Context, id int) (models.Term, error) {
+	return cache.Load(ctx, s.cache, termKey(id), func(ctx context.Context) (models.Term, error) {
+		return s.getTermByID(ctx, id)
+	})
+}
+
+func (s *TermService) getTermByID(ctx context.Context, id int) (models.Term, error) {
+	var term models.Term
+	query := "SELECT id, name, starts_on, ends_on FROM term WHERE id = $1"
+	ctx, span := startQuerySpan(ctx, "TermService.GetTermByID", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := s.reads.QueryRow(ctx, query, id).Scan(termFields(&term)...)
+	if err != nil {
+		tracing.RecordError(span, err)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Term{}, fmt.Errorf("[in services.GetTermByID] no term found with id %d: %w", id, models.ErrNotFound)
+		}
+		return models.Term{}, fmt.Errorf("[in services.GetTermByID] failed to retrieve term: %w", err)
+	}
+
+	return term, nil
+}
+
+func (s *TermService) CreateTerm(ctx context.Context, term models.Term) (models.Term, error) {
+	query := `INSERT INTO term (name, starts_on, ends_on) VALUES ($1, $2, $3) RETURNING id`
+	ctx, span := startQuerySpan(ctx, "TermService.CreateTerm", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	err := conn(ctx, s.database).QueryRow(ctx, query, term.Name, term.StartsOn, term.EndsOn).Scan(&term.ID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Term{}, fmt.Errorf("[in services.CreateTerm] failed to create term: %w", constraintError(err))
+	}
+	s.cache.Invalidate(ctx, termKeyPrefix)
+	return term, nil
+}
+
+// UpdateTerm renames or moves the term with the given ID. It wraps models.ErrNotFound when there is
+// none.
+func (s *TermService) UpdateTerm(ctx context.Context, id int, term models.Term) (models.Term, error) {
+	query := `UPDATE term SET name = $1, starts_on = $2, ends_on = $3 WHERE id = $4`
+	ctx, span := startQuerySpan(ctx, "TermService.UpdateTerm", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := conn(ctx, s.database).Exec(ctx, query, term.Name, term.StartsOn, term.EndsOn, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return models.Term{}, fmt.Errorf("[in services.UpdateTerm] failed to update term: %w", constraintError(err))
+	}
+
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(span, rowsAffected)
+	if rowsAffected == 0 {
+		return models.Term{}, fmt.Errorf("[in services.UpdateTerm] no term found with id %d: %w", id, models.ErrNotFound)
+	}
+	// Cached section lists are ordered by their term's start date.
+	s.cache.Invalidate(ctx, termKeyPrefix, courseKeyPrefix)
+
+	term.ID = id
+	return term, nil
+}
+
+// DeleteTerm deletes the term with the given ID. It wraps models.ErrNotFound when there is none and
+// models.ErrTermInUse when the term still has sections.
+func (s *TermService) DeleteTerm(ctx context.Context, id int) error {
+	query := `DELETE FROM term WHERE id = $1`
+	ctx, span := startQuerySpan(ctx, "TermService.DeleteTerm", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	result, err := conn(ctx, s.database).Exec(ctx, query, id)
+	if err != nil {
+		tracing.RecordError(span, err)
+		// The only foreign key to term is the one from its sections.
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return fmt.Errorf("[in services.DeleteTerm] failed to delete term: %w", models.ErrTermInUse)
+		}
+		return fmt.Errorf("[in services.DeleteTerm] failed to delete term: %w", err)
+	}
+
+	rowsAffected := result.RowsAffected()
+	setRowsAffected(span, rowsAffected)
+	if rowsAffected == 0 {
+		return fmt.Errorf("[in services.DeleteTerm] no term found with id %d: %w", id, models.ErrNotFound)
+	}
+	s.cache.Invalidate(ctx, termKeyPrefix)
+
+	return nil
+}
+
+// termFields returns where to scan the columns every term query selects, in order: id, name,
+// starts_on and ends_on.
+func termFields(term *models.Term) []any {
+	return []any{&term.ID, &term.Name, &term.StartsOn, &term.EndsOn}
+}
@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/testutil"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type termTestSuite struct {
+	suite.Suite
+	service *TermService
+	dbMock  pgxmock.PgxPoolIface
+}
+
+func TestTermService(t *testing.T) {
+	suite.Run(t, new(termTestSuite))
+}
+
+func (s *termTestSuite) SetupSuite() {
+	mock, err := pgxmock.NewPool()
+	assert.NoError(s.T(), err)
+
+	s.dbMock = mock
+	s.service = NewTermService(mock)
+}
+
+func (s *termTestSuite) TearDownSuite() {
+	err := s.dbMock.ExpectationsWereMet()
+	assert.NoError(s.T(), err)
+}
+
+var (
+	fall2026 = models.Term{ID: 1, Name: "Fall 2026",
+		StartsOn: time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC),
+		EndsOn:   time.Date(2026, time.December, 18, 0, 0, 0, 0, time.UTC)}
+	spring2027 = models.Term{ID: 2, Name: "Spring 2027",
+		StartsOn: time.Date(2027, time.January, 18, 0, 0, 0, 0, time.UTC),
+		EndsOn:   time.Date(2027, time.May, 14, 0, 0, 0, 0, time.UTC)}
+)
+
+func (s *termTestSuite) TestListTerms() {
+	t := s.T()
+
+	terms := []models.Term{fall2026, spring2027}
+
+	testCases := map[string]struct {
+		mockReturn     *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn []models.Term
+		expectedError  error
+	}{
+		"Return slice of terms": {
+			mockReturn:     testutil.MustStructsToRows(terms),
+			expectedReturn: terms,
+		},
+		"Error getting terms": {
+			mockReturnErr:  errors.New("test"),
+			expectedReturn: []models.Term{},
+			expectedError:  fmt.Errorf("[in services.ListTerms] failed to get terms: %w", errors.New("test")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, starts_on, ends_on FROM term ORDER BY starts_on asc, id asc`))
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockReturn)
+			}
+
+			actualReturn, err := s.service.ListTerms(context.Background())
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *termTestSuite) TestGetTermByID() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		inputID        int
+		mockRows       *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Term
+		expectedError  error
+	}{
+		"term found": {
+			inputID:        1,
+			mockRows:       testutil.MustStructsToRows([]models.Term{fall2026}),
+			expectedReturn: fall2026,
+		},
+		"term not found": {
+			inputID:       9,
+			mockReturnErr: pgx.ErrNoRows,
+			expectedError: fmt.Errorf("[in services.GetTermByID] no term found with id %d: %w", 9, models.ErrNotFound),
+		},
+		"Error retrieving term": {
+			inputID:       1,
+			mockReturnErr: errors.New("test error"),
+			expectedError: fmt.Errorf("[in services.GetTermByID] failed to retrieve term: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(`SELECT id, name, starts_on, ends_on FROM term WHERE id = $1`)).
+				WithArgs(tc.inputID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockRows)
+			}
+
+			actualReturn, err := s.service.GetTermByID(context.Background(), tc.inputID)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *termTestSuite) TestCreateTerm() {
+	t := s.T()
+
+	term := spring2027
+	term.ID = 0
+
+	testCases := map[string]struct {
+		mockRows       *pgxmock.Rows
+		mockReturnErr  error
+		expectedReturn models.Term
+		expectedError  error
+	}{
+		"term created successfully": {
+			mockRows:       pgxmock.NewRows([]string{"id"}).AddRow(spring2027.ID),
+			expectedReturn: spring2027,
+		},
+		"name taken": {
+			mockReturnErr: &pgconn.PgError{Code: "23505", ConstraintName: "term_name"},
+			expectedError: fmt.Errorf("[in services.CreateTerm] failed to create term: %w", models.ErrTermNameTaken),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO term (name, starts_on, ends_on) VALUES ($1, $2, $3) RETURNING id`)).
+				WithArgs(term.Name, term.StartsOn, term.EndsOn)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnRows(tc.mockRows)
+			}
+
+			actualReturn, err := s.service.CreateTerm(context.Background(), term)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *termTestSuite) TestUpdateTerm() {
+	t := s.T()
+
+	term := fall2026
+	term.ID = 0
+	term.Name = "Autumn 2026"
+	updated := term
+	updated.ID = 1
+
+	testCases := map[string]struct {
+		inputID        int
+		mockReturn     pgconn.CommandTag
+		mockReturnErr  error
+		expectedReturn models.Term
+		expectedError  error
+	}{
+		"term updated successfully": {
+			inputID:        1,
+			mockReturn:     pgxmock.NewResult("UPDATE", 1),
+			expectedReturn: updated,
+		},
+		"term not found": {
+			inputID:       9,
+			mockReturn:    pgxmock.NewResult("UPDATE", 0),
+			expectedError: fmt.Errorf("[in services.UpdateTerm] no term found with id %d: %w", 9, models.ErrNotFound),
+		},
+		"name taken": {
+			inputID:       1,
+			mockReturnErr: &pgconn.PgError{Code: "23505", ConstraintName: "term_name"},
+			expectedError: fmt.Errorf("[in services.UpdateTerm] failed to update term: %w", models.ErrTermNameTaken),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectExec(regexp.QuoteMeta(`UPDATE term SET name = $1, starts_on = $2, ends_on = $3 WHERE id = $4`)).
+				WithArgs(term.Name, term.StartsOn, term.EndsOn, tc.inputID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnResult(tc.mockReturn)
+			}
+
+			actualReturn, err := s.service.UpdateTerm(context.Background(), tc.inputID, term)
+
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedReturn, actualReturn)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func (s *termTestSuite) TestDeleteTerm() {
+	t := s.T()
+
+	testCases := map[string]struct {
+		inputID       int
+		mockReturn    pgconn.CommandTag
+		mockReturnErr error
+		expectedError error
+	}{
+		"term deleted successfully": {
+			inputID:    2,
+			mockReturn: pgxmock.NewResult("DELETE", 1),
+		},
+		"term not found": {
+			inputID:       9,
+			mockReturn:    pgxmock.NewResult("DELETE", 0),
+			expectedError: fmt.Errorf("[in services.DeleteTerm] no term found with id %d: %w", 9, models.ErrNotFound),
+		},
+		"term has sections": {
+			inputID:       1,
+			mockReturnErr: &pgconn.PgError{Code: "23503", ConstraintName: "course_section_term"},
+			expectedError: fmt.Errorf("[in services.DeleteTerm] failed to delete term: %w", models.ErrTermInUse),
+		},
+		"Error deleting term": {
+			inputID:       2,
+			mockReturnErr: errors.New("test error"),
+			expectedError: fmt.Errorf("[in services.DeleteTerm] failed to delete term: %w", errors.New("test error")),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			mock := s.dbMock.ExpectExec(regexp.QuoteMeta(`DELETE FROM term WHERE id = $1`)).
+				WithArgs(tc.inputID)
+			if tc.mockReturnErr != nil {
+				mock.WillReturnError(tc.mockReturnErr)
+			} else {
+				mock.WillReturnResult(tc.mockReturn)
+			}
+
+			err := s.service.DeleteTerm(context.Background(), tc.inputID)
+
+			assert.Equal(t, tc.expectedError, err)
+
+			err = s.dbMock.ExpectationsWereMet()
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("go-api-tech-challenge/internal/services")
+
+// startQuerySpan starts a client span for a single SQL statement, recording the statement text.
+func startQuerySpan(ctx context.Context, name string, query string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.statement", query),
+		),
+	)
+}
+
+// setRowsReturned records the number of rows scanned from a query on the span.
+func setRowsReturned(span trace.Span, count int) {
+	span.SetAttributes(attribute.Int("db.rows_returned", count))
+}
+
+// setRowsAffected records the number of rows changed by a statement on the span.
+func setRowsAffected(span trace.Span, count int64) {
+	span.SetAttributes(attribute.Int64("db.rows_affected", count))
+}
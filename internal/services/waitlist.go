@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"go-api-tech-challenge/internal/database"
+	"go-api-tech-challenge/internal/models"
+	"go-api-tech-challenge/internal/tracing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// WaitlistPromotionChannel is the Postgres notification channel on which every student given a
+// seat from a waitlist is published, as a models.WaitlistPromotion in JSON. Notifications are only
+// delivered once the transaction that promoted the student commits.
+const WaitlistPromotionChannel = "waitlist_promotion"
+
+// lockSections locks the given sections until the transaction ends. Seats are only counted and
+// handed out under these locks, so concurrent enrollments cannot oversubscribe a section. The
+// sections are locked in ID order, so that transactions wanting the same sections queue up rather
+// than deadlock.
+func (s *PersonService) lockSections(ctx context.Context, tx pgx.Tx, sectionIDs []int) error {
+	if len(sectionIDs) == 0 {
+		return nil
+	}
+	query := `SELECT id FROM course_section WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	return s.exec(ctx, tx, "lock sections", query, uniqueIDs(sectionIDs))
+}
+
+// seatsLeft returns how many more students each of the given sections has room for, leaving out
+// the sections whose course has no capacity. The sections must be locked, so that the seats stay
+// free until the transaction ends.
+func (s *PersonService) seatsLeft(ctx context.Context, tx pgx.Tx, sectionIDs []int) (map[int]int, error) {
+	if len(sectionIDs) == 0 {
+		return nil, nil
+	}
+	query := `SELECT cs.id, c.capacity - COUNT(p.id)
+	FROM course_section cs
+	JOIN course c ON c.id = cs.course_id
+	LEFT JOIN enrollment e ON e.section_id = cs.id
+	LEFT JOIN person p ON p.id = e.person_id AND p.type = 'student'
+	WHERE cs.id = ANY($1) AND c.capacity IS NOT NULL
+	GROUP BY cs.id, c.capacity`
+	ctx, span := startQuerySpan(ctx, "count seats left", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, uniqueIDs(sectionIDs))
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	seats := map[int]int{}
+	var sectionID, left int
+	_, err = pgx.ForEachRow(rows, []any{&sectionID, &left}, func() error {
+		seats[sectionID] = left
+		return nil
+	})
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsReturned(span, len(seats))
+	return seats, nil
+}
+
+// assignSeats gives each student a seat in their sections, in order, while the section has seats
+// left, and moves the sections that are full to the student's Waitlisted. Professors are not
+// counted against capacity and keep all their sections. seats is used up as seats are given.
+func assignSeats(persons []models.Person, seats map[int]int) {
+	for i, person := range persons {
+		if person.Type != "student" {
+			continue
+		}
+		var seated, waitlisted []int
+		for _, sectionID := range person.Sections {
+			left, limited := seats[sectionID]
+			switch {
+			case !limited:
+				seated = append(seated, sectionID)
+			case left > 0:
+				seats[sectionID]--
+				seated = append(seated, sectionID)
+			default:
+				waitlisted = append(waitlisted, sectionID)
+			}
+		}
+		persons[i].Sections, persons[i].Waitlisted = seated, waitlisted
+	}
+}
+
+// joinWaitlists puts every person at the end of the waitlists of their Waitlisted sections, in
+// order. A person already waiting for a section keeps their place.
+func (s *PersonService) joinWaitlists(ctx context.Context, tx pgx.Tx, persons []models.Person) error {
+	var personIDs, sectionIDs []int
+	for _, person := range persons {
+		for _, sectionID := range person.Waitlisted {
+			personIDs = append(personIDs, person.ID)
+			sectionIDs = append(sectionIDs, sectionID)
+		}
+	}
+	if len(personIDs) == 0 {
+		return nil
+	}
+	query := `INSERT INTO waitlist (person_id, section_id)
+	SELECT person_id, section_id FROM unnest($1::int[], $2::int[]) WITH ORDINALITY AS joined (person_id, section_id, place)
+	ORDER BY place
+	ON CONFLICT DO NOTHING`
+	return s.exec(ctx, tx, "join waitlists", query, personIDs, sectionIDs)
+}
+
+// enroll enrolls the person in the sections they ask for that have seats left, and puts them on the
+// waitlist of the others. The sections must have been locked with lockSections.
+func (s *PersonService) enroll(ctx context.Context, tx pgx.Tx, person models.Person) error {
+	seats, err := s.seatsLeft(ctx, tx, person.Sections)
+	if err != nil {
+		return fmt.Errorf("failed to count seats: %w", err)
+	}
+	persons := []models.Person{person}
+	assignSeats(persons, seats)
+
+	if len(persons[0].Sections) > 0 {
+		if err := s.insertSections(ctx, tx, person.ID, persons[0].Sections); err != nil {
+			return err
+		}
+	}
+	if err := s.joinWaitlists(ctx, tx, persons); err != nil {
+		return fmt.Errorf("failed to join waitlists: %w", err)
+	}
+	return nil
+}
+
+// selectWaitlisted returns the IDs of the sections the person is waiting for, in the order they
+// joined the waitlists.
+func (s *PersonService) selectWaitlisted(ctx context.Context, tx pgx.Tx, personID int) ([]int, error) {
+	query := `SELECT section_id FROM waitlist WHERE person_id = $1 ORDER BY id`
+	ctx, span := startQuerySpan(ctx, "select person waitlists", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, s.statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, personID)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	waitlisted, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsReturned(span, len(waitlisted))
+	return waitlisted, nil
+}
+
+// promoteWaitlisted hands the free seats of the given sections to the students waiting for them,
+// first come first served, and publishes each promotion on WaitlistPromotionChannel. A section whose
+// course has no capacity lets its whole waitlist in. The sections must be locked, so that the seats
+// counted are still free when they are taken.
+func promoteWaitlisted(ctx context.Context, tx pgx.Tx, statementTimeout time.Duration, sectionIDs []int) ([]models.WaitlistPromotion, error) {
+	if len(sectionIDs) == 0 {
+		return nil, nil
+	}
+	promotions, err := queryPromotions(ctx, tx, statementTimeout, uniqueIDs(sectionIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, promotion := range promotions {
+		payload, err := json.Marshal(promotion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode promotion: %w", err)
+		}
+		if err := database.Notify(ctx, tx, WaitlistPromotionChannel, string(payload)); err != nil {
+			return nil, err
+		}
+	}
+	return promotions, nil
+}
+
+// queryPromotions moves the students given a seat from the waitlists of the sections to their
+// enrollments, in a single statement, and returns them.
+func queryPromotions(ctx context.Context, tx pgx.Tx, statementTimeout time.Duration, sectionIDs []int) ([]models.WaitlistPromotion, error) {
+	query := `WITH queue AS (
+		SELECT w.id,
+		row_number() OVER (PARTITION BY w.section_id ORDER BY w.id) AS place,
+		c.capacity - (SELECT COUNT(*) FROM enrollment e JOIN person p ON p.id = e.person_id
+			WHERE e.section_id = w.section_id AND p.type = 'student') AS seats
+		FROM waitlist w
+		JOIN course_section cs ON cs.id = w.section_id
+		JOIN course c ON c.id = cs.course_id
+		WHERE w.section_id = ANY($1)
+	), promoted AS (
+		DELETE FROM waitlist w USING queue q
+		WHERE w.id = q.id AND (q.seats IS NULL OR q.place <= q.seats)
+		RETURNING w.id, w.person_id, w.section_id
+	)
+	INSERT INTO enrollment (person_id, section_id)
+	SELECT person_id, section_id FROM promoted ORDER BY id
+	ON CONFLICT DO NOTHING
+	RETURNING person_id, section_id`
+	ctx, span := startQuerySpan(ctx, "promote waitlisted students", query)
+	defer span.End()
+	ctx, cancel := withStatementTimeout(ctx, statementTimeout)
+	defer cancel()
+
+	rows, err := tx.Query(ctx, query, sectionIDs)
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	promotions, err := pgx.CollectRows(rows, pgx.RowToStructByPos[models.WaitlistPromotion])
+	if err != nil {
+		tracing.RecordError(span, err)
+		return nil, err
+	}
+	setRowsAffected(span, int64(len(promotions)))
+	return promotions, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+
+	"go-api-tech-challenge/internal/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// The statements that hand out seats and manage waitlists, shared by the tests of the services
+// that enroll persons.
+var (
+	lockSectionsQuery     = `SELECT id FROM course_section WHERE id = ANY($1) ORDER BY id FOR UPDATE`
+	seatsLeftQuery        = `SELECT cs.id, c.capacity - COUNT(p.id)`
+	joinWaitlistsQuery    = `INSERT INTO waitlist (person_id, section_id)`
+	selectWaitlistedQuery = `SELECT section_id FROM waitlist WHERE person_id = $1 ORDER BY id`
+	promoteQuery          = `INSERT INTO enrollment (person_id, section_id)
+		SELECT person_id, section_id FROM promoted ORDER BY id
+		ON CONFLICT DO NOTHING
+		RETURNING person_id, section_id`
+)
+
+func TestAssignSeats(t *testing.T) {
+	tests := map[string]struct {
+		persons       []models.Person
+		seats         map[int]int
+		expected      []models.Person
+		expectedSeats map[int]int
+	}{
+		"sections without capacity": {
+			persons:       []models.Person{{Type: "student", Sections: []int{1, 2}}},
+			seats:         map[int]int{},
+			expected:      []models.Person{{Type: "student", Sections: []int{1, 2}}},
+			expectedSeats: map[int]int{},
+		},
+		"full section waitlisted": {
+			persons:       []models.Person{{Type: "student", Sections: []int{1, 2}}},
+			seats:         map[int]int{1: 0, 2: 3},
+			expected:      []models.Person{{Type: "student", Sections: []int{2}, Waitlisted: []int{1}}},
+			expectedSeats: map[int]int{1: 0, 2: 2},
+		},
+		"first come first seated": {
+			persons: []models.Person{
+				{ID: 1, Type: "student", Sections: []int{1}},
+				{ID: 2, Type: "student", Sections: []int{1}},
+			},
+			seats: map[int]int{1: 1},
+			expected: []models.Person{
+				{ID: 1, Type: "student", Sections: []int{1}},
+				{ID: 2, Type: "student", Waitlisted: []int{1}},
+			},
+			expectedSeats: map[int]int{1: 0},
+		},
+		"professors not counted": {
+			persons:       []models.Person{{Type: "professor", Sections: []int{1}}},
+			seats:         map[int]int{1: 0},
+			expected:      []models.Person{{Type: "professor", Sections: []int{1}}},
+			expectedSeats: map[int]int{1: 0},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assignSeats(tc.persons, tc.seats)
+
+			assert.Equal(t, tc.expected, tc.persons)
+			assert.Equal(t, tc.expectedSeats, tc.seats)
+		})
+	}
+}
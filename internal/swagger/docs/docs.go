@@ -15,19 +15,139 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api/batch": {
+            "post": {
+                "description": "Runs several create, update, delete or get requests in one round trip, returning each one's status and body in order. In atomic mode, the default, the operations share one database transaction: the batch stops at the first operation that fails, everything before it is rolled back, and the operations after it are answered with 424. In best_effort mode every operation runs, and commits, on its own.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "batch"
+                ],
+                "summary": "Run a batch of operations",
+                "parameters": [
+                    {
+                        "description": "Operations to run, in order",
+                        "name": "operations",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.inputBatchOperation"
+                            }
+                        }
+                    },
+                    {
+                        "enum": [
+                            "atomic",
+                            "best_effort"
+                        ],
+                        "type": "string",
+                        "description": "atomic (default) or best_effort",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseBatch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
         "/api/course": {
             "get": {
-                "description": "List all courses",
+                "description": "List courses as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter. Filters combine, and a course must match all of them",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
                 ],
                 "tags": [
                     "courses"
                 ],
-                "summary": "List all courses",
+                "summary": "List courses",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "undergraduate",
+                            "graduate"
+                        ],
+                        "type": "string",
+                        "description": "only courses at this level",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "only active courses, or only inactive ones",
+                        "name": "active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only courses worth at least this many credits",
+                        "name": "min_credits",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only courses worth at most this many credits",
+                        "name": "max_credits",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only courses whose code starts with this, such as CS",
+                        "name": "code_prefix",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -35,6 +155,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.responseCourses"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -64,6 +190,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/handlers.inputCourse"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -73,6 +205,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.responseCourse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the code is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "the department does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -84,7 +234,7 @@ const docTemplate = `{
         },
         "/api/course/{ID}": {
             "get": {
-                "description": "Gets course associated with given ID",
+                "description": "Gets course associated with given ID, or with given code such as CS101",
                 "consumes": [
                     "application/json"
                 ],
@@ -97,8 +247,8 @@ const docTemplate = `{
                 "summary": "Get Course",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "ID of course to retrieve",
+                        "type": "string",
+                        "description": "ID or code of course to retrieve",
                         "name": "ID",
                         "in": "path",
                         "required": true
@@ -111,6 +261,18 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.responseCourse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -156,6 +318,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.responseCourse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the code is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "the department does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -201,9 +381,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/health-check": {
+        "/api/course/{ID}/sections": {
             "get": {
-                "description": "Health check response",
+                "description": "List the sections of a course, ordered by term and number",
                 "consumes": [
                     "application/json"
                 ],
@@ -211,22 +391,130 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "health-check"
+                    "courses"
+                ],
+                "summary": "List course sections",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of course",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only list the sections of this term",
+                        "name": "term",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Health check response",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.responseMsg"
+                            "$ref": "#/definitions/handlers.responseSections"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds a section of a course in a term, optionally taught by a professor",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "courses"
+                ],
+                "summary": "Create Section",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of course",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Section Object",
+                        "name": "section",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.inputSection"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseSection"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the course already has a section with this number in the term",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "the term does not exist or the instructor is not a professor",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
                         }
                     }
                 }
             }
         },
-        "/api/person": {
+        "/api/department": {
             "get": {
-                "description": "List all persons",
+                "description": "List all departments",
                 "consumes": [
                     "application/json"
                 ],
@@ -234,14 +522,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "person"
+                    "departments"
                 ],
-                "summary": "List all Persons",
+                "summary": "List departments",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.responsePersons"
+                            "$ref": "#/definitions/handlers.responseDepartments"
                         }
                     },
                     "500": {
@@ -253,7 +541,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Creates person",
+                "description": "Creates new department",
                 "consumes": [
                     "application/json"
                 ],
@@ -261,25 +549,43 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "person"
+                    "departments"
                 ],
-                "summary": "Creates Person",
+                "summary": "Create Department",
                 "parameters": [
                     {
-                        "description": "Person Object",
-                        "name": "person",
+                        "description": "Department Object",
+                        "name": "department",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.inputPerson"
+                            "$ref": "#/definitions/handlers.inputDepartment"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handlers.responsePerson"
+                            "$ref": "#/definitions/handlers.responseDepartment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
                         }
                     },
                     "500": {
@@ -291,9 +597,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/person/{name}": {
+        "/api/department/{ID}": {
             "get": {
-                "description": "Gets Person by Name",
+                "description": "Gets department associated with given ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -301,14 +607,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "person"
+                    "departments"
                 ],
-                "summary": "Gets Person",
+                "summary": "Get Department",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "last name of person to retrieve",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "ID of department to retrieve",
+                        "name": "ID",
                         "in": "path",
                         "required": true
                     }
@@ -317,7 +623,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.responsePerson"
+                            "$ref": "#/definitions/handlers.responseDepartment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
                         }
                     },
                     "500": {
@@ -329,7 +647,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Updates person",
+                "description": "Renames department associated with given ID",
                 "consumes": [
                     "application/json"
                 ],
@@ -337,24 +655,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "person"
+                    "departments"
                 ],
-                "summary": "Update Person",
+                "summary": "Update Department",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "last name of person to update",
-                        "name": "name",
+                        "type": "integer",
+                        "description": "ID of department to update",
+                        "name": "ID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Person Object",
-                        "name": "person",
+                        "description": "Department Object",
+                        "name": "department",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.inputPerson"
+                            "$ref": "#/definitions/handlers.inputDepartment"
                         }
                     }
                 ],
@@ -362,7 +680,662 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handlers.responsePerson"
+                            "$ref": "#/definitions/handlers.responseDepartment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes department associated with given ID. Its courses and professors are kept, without a department",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "Deletes Department",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of department to delete",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseMsg"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/department/{ID}/courses": {
+            "get": {
+                "description": "List the courses of a department as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "List department courses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of department",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseCourses"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/department/{ID}/professors": {
+            "get": {
+                "description": "List the professors of a department as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "departments"
+                ],
+                "summary": "List department professors",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of department",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePersons"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/health/cache": {
+            "get": {
+                "description": "Reports cumulative read cache hits, misses, errors and invalidations",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health-check"
+                ],
+                "summary": "Read cache statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseCacheStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/health/live": {
+            "get": {
+                "description": "Reports that the process is up",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health-check"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseMsg"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/health/pool": {
+            "get": {
+                "description": "Reports current and cumulative database connection pool statistics",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health-check"
+                ],
+                "summary": "Database pool statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePoolStats"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/health/ready": {
+            "get": {
+                "description": "Reports per-component status and latency of service dependencies",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health-check"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseReadiness"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseReadiness"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/person": {
+            "get": {
+                "description": "List all persons as JSON, NDJSON or CSV, chosen by the Accept header or the format parameter",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "List all Persons",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "response format, overriding the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePersons"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates person",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Creates Person",
+                "parameters": [
+                    {
+                        "description": "Person Object",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.inputPerson"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "create the person even if they look like one who already exists",
+                        "name": "allow_duplicate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePerson"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the person may already exist, or the external ID is in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseDuplicates"
+                        }
+                    },
+                    "422": {
+                        "description": "the department or a section does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/person/import": {
+            "post": {
+                "description": "Creates persons in bulk from CSV or NDJSON, sent as the body or as the ` + "`" + `file` + "`" + ` part of a multipart form. CSV needs a header row naming first_name, last_name, type and age, and optionally sections (course section IDs separated by semicolons) and department_id; id and courses columns are ignored, so an export can be imported again. Every row is validated and its sections checked before anything is written, and nothing is written unless every row is valid.",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Import Persons",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "CSV or NDJSON file, when sending a multipart form",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "validate the import without writing it",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry run found no problems",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseImport"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "an external ID is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "rows with problems, or a department that does not exist; nothing was written",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseImport"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/person/{id}/merge": {
+            "post": {
+                "description": "Merges a duplicate person of the same type into this one, moving their enrollments and deleting the duplicate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Merge Persons",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of the person to keep",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Person to merge in",
+                        "name": "merge",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.inputMerge"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePerson"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/person/{name}": {
+            "get": {
+                "description": "Gets Person by Name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Gets Person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "last name of person to retrieve",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePerson"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Updates person",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "Update Person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "last name of person to update",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Person Object",
+                        "name": "person",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.inputPerson"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responsePerson"
+                        }
+                    },
+                    "409": {
+                        "description": "the external ID is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "422": {
+                        "description": "the department or a section does not exist",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
                         }
                     },
                     "500": {
@@ -382,14 +1355,356 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "person"
+                    "person"
+                ],
+                "summary": "Deletes Person",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "last name of person to delete",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseMsg"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/person/{name}/enrollments": {
+            "get": {
+                "description": "List the sections a person is enrolled in, with their course and term, ordered by term and course",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "person"
+                ],
+                "summary": "List person enrollments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "last name of person",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only list the enrollments of this term",
+                        "name": "term",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseEnrollments"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/search": {
+            "get": {
+                "description": "Finds persons by full name and courses by name, matching partial words and, on Postgres, misspellings. Matches in each title are highlighted with \u003cmark\u003e tags",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "search"
+                ],
+                "summary": "Search persons and courses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "words to search for",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "description": "maximum number of results, 20 by default",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseSearch"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/term": {
+            "get": {
+                "description": "List all terms",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terms"
+                ],
+                "summary": "List terms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseTerms"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates new term",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terms"
                 ],
-                "summary": "Deletes Person",
+                "summary": "Create Term",
                 "parameters": [
+                    {
+                        "description": "Term Object",
+                        "name": "term",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.inputTerm"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "last name of person to delete",
-                        "name": "name",
+                        "description": "key making the request safe to retry; a retry gets the first response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseTerm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/term/{ID}": {
+            "get": {
+                "description": "Gets term associated with given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terms"
+                ],
+                "summary": "Get Term",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of term to retrieve",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseTerm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Renames or moves term associated with given ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terms"
+                ],
+                "summary": "Update Term",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of term to update",
+                        "name": "ID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Term Object",
+                        "name": "term",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.inputTerm"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseTerm"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the name is already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes term associated with given ID, unless it still has sections",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "terms"
+                ],
+                "summary": "Deletes Term",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of term to delete",
+                        "name": "ID",
                         "in": "path",
                         "required": true
                     }
@@ -401,6 +1716,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.responseMsg"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
+                    "409": {
+                        "description": "the term has sections",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.responseErr"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -409,18 +1742,275 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.inputCourse": {
+        }
+    },
+    "definitions": {
+        "handlers.inputBatchOperation": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "object"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/api/person"
+                }
+            }
+        },
+        "handlers.inputCourse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "capacity": {
+                    "description": "Capacity is how many students each section of the course holds; without one, there is no limit.",
+                    "type": "integer",
+                    "example": 30
+                },
+                "code": {
+                    "type": "string",
+                    "example": "CS101"
+                },
+                "credits": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "department_id": {
+                    "description": "DepartmentID is the department teaching the course, if any.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string",
+                    "example": "Writing, testing and debugging programs."
+                },
+                "level": {
+                    "type": "string",
+                    "enum": [
+                        "undergraduate",
+                        "graduate"
+                    ],
+                    "example": "undergraduate"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Programming"
+                }
+            }
+        },
+        "handlers.inputDepartment": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Computer Science"
+                }
+            }
+        },
+        "handlers.inputMerge": {
+            "type": "object",
+            "properties": {
+                "duplicate_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.inputPerson": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "type": "integer"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "example": "2001-12-10"
+                },
+                "department_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "email": {
+                    "type": "string",
+                    "example": "ada@example.com"
+                },
+                "external_id": {
+                    "type": "string",
+                    "example": "S-000123"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "+44 20 7946 0000"
+                },
+                "sections": {
+                    "description": "Sections are the course sections to enroll the person in.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.inputSection": {
+            "type": "object",
+            "properties": {
+                "instructor_id": {
+                    "description": "InstructorID is the professor teaching the section, if one has been assigned.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "term_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handlers.inputTerm": {
+            "type": "object",
+            "properties": {
+                "ends_on": {
+                    "type": "string",
+                    "example": "2026-12-18"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Fall 2026"
+                },
+                "starts_on": {
+                    "type": "string",
+                    "example": "2026-09-01"
+                }
+            }
+        },
+        "handlers.outputBatchResult": {
+            "type": "object",
+            "properties": {
+                "body": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 201
+                }
+            }
+        },
+        "handlers.outputComponent": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.outputCourse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "capacity": {
+                    "type": "integer",
+                    "example": 30
+                },
+                "code": {
+                    "type": "string",
+                    "example": "CS101"
+                },
+                "credits": {
+                    "type": "integer"
+                },
+                "department_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "level": {
+                    "type": "string",
+                    "example": "undergraduate"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.outputDepartment": {
             "type": "object",
             "properties": {
+                "id": {
+                    "type": "integer"
+                },
                 "name": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Computer Science"
                 }
             }
         },
-        "handlers.inputPerson": {
+        "handlers.outputEnrollment": {
+            "type": "object",
+            "properties": {
+                "course_code": {
+                    "type": "string",
+                    "example": "CS101"
+                },
+                "course_id": {
+                    "type": "integer"
+                },
+                "course_name": {
+                    "type": "string",
+                    "example": "Programming"
+                },
+                "instructor_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "section_id": {
+                    "type": "integer"
+                },
+                "section_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "term_id": {
+                    "type": "integer"
+                },
+                "term_name": {
+                    "type": "string",
+                    "example": "Fall 2026"
+                }
+            }
+        },
+        "handlers.outputPerson": {
             "type": "object",
             "properties": {
                 "age": {
@@ -432,51 +2022,134 @@ const docTemplate = `{
                         "type": "integer"
                     }
                 },
+                "created_at": {
+                    "type": "string"
+                },
+                "date_of_birth": {
+                    "type": "string",
+                    "example": "2001-12-10"
+                },
+                "department_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "email": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
                 "first_name": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer"
+                },
                 "last_name": {
                     "type": "string"
                 },
+                "phone": {
+                    "type": "string"
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
                 "type": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "waitlisted": {
+                    "description": "Waitlisted are the sections the person is waiting for a seat in, in the order they joined.",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
                 }
             }
         },
-        "handlers.outputCourse": {
+        "handlers.outputRowError": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer"
+                },
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.problem"
+                    }
+                }
+            }
+        },
+        "handlers.outputSearchResult": {
             "type": "object",
             "properties": {
+                "highlight": {
+                    "type": "string",
+                    "example": "\u003cmark\u003eBill\u003c/mark\u003e Gates"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "name": {
+                "rank": {
+                    "type": "number"
+                },
+                "title": {
                     "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "person",
+                        "course"
+                    ]
                 }
             }
         },
-        "handlers.outputPerson": {
+        "handlers.outputSection": {
             "type": "object",
             "properties": {
-                "age": {
+                "course_id": {
                     "type": "integer"
                 },
-                "courses": {
-                    "type": "array",
-                    "items": {
-                        "type": "integer"
-                    }
+                "id": {
+                    "type": "integer"
                 },
-                "first_name": {
-                    "type": "string"
+                "instructor_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "term_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.outputTerm": {
+            "type": "object",
+            "properties": {
+                "ends_on": {
+                    "type": "string",
+                    "example": "2026-12-18"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "last_name": {
-                    "type": "string"
+                "name": {
+                    "type": "string",
+                    "example": "Fall 2026"
                 },
-                "type": {
-                    "type": "string"
+                "starts_on": {
+                    "type": "string",
+                    "example": "2026-09-01"
                 }
             }
         },
@@ -491,6 +2164,50 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.responseBatch": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "example": "atomic"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputBatchResult"
+                    }
+                },
+                "rolled_back": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.responseCacheStats": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "integer"
+                },
+                "evictions": {
+                    "type": "integer"
+                },
+                "hit_ratio": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "invalidations": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.responseCourse": {
             "type": "object",
             "properties": {
@@ -510,12 +2227,59 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.responseDepartment": {
+            "type": "object",
+            "properties": {
+                "department": {
+                    "$ref": "#/definitions/handlers.outputDepartment"
+                }
+            }
+        },
+        "handlers.responseDepartments": {
+            "type": "object",
+            "properties": {
+                "departments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputDepartment"
+                    }
+                }
+            }
+        },
+        "handlers.responseDuplicates": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputPerson"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.responseEnrollments": {
+            "type": "object",
+            "properties": {
+                "enrollments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputEnrollment"
+                    }
+                }
+            }
+        },
         "handlers.responseErr": {
             "type": "object",
             "properties": {
                 "error": {
                     "type": "string"
                 },
+                "request_id": {
+                    "type": "string"
+                },
                 "validation_errors": {
                     "type": "array",
                     "items": {
@@ -524,6 +2288,32 @@ const docTemplate = `{
                 }
             }
         },
+        "handlers.responseImport": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputRowError"
+                    }
+                },
+                "imported": {
+                    "type": "integer"
+                },
+                "persons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputPerson"
+                    }
+                },
+                "rows": {
+                    "type": "integer"
+                }
+            }
+        },
         "handlers.responseMsg": {
             "type": "object",
             "properties": {
@@ -550,6 +2340,113 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "handlers.responsePoolStats": {
+            "type": "object",
+            "properties": {
+                "acquire_count": {
+                    "type": "integer"
+                },
+                "acquire_duration_ms": {
+                    "type": "number"
+                },
+                "acquired_conns": {
+                    "type": "integer"
+                },
+                "canceled_acquire_count": {
+                    "type": "integer"
+                },
+                "constructing_conns": {
+                    "type": "integer"
+                },
+                "empty_acquire_count": {
+                    "type": "integer"
+                },
+                "idle_conns": {
+                    "type": "integer"
+                },
+                "max_conns": {
+                    "type": "integer"
+                },
+                "max_idle_destroy_count": {
+                    "type": "integer"
+                },
+                "max_lifetime_destroy_count": {
+                    "type": "integer"
+                },
+                "new_conns_count": {
+                    "type": "integer"
+                },
+                "total_conns": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.responseReadiness": {
+            "type": "object",
+            "properties": {
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputComponent"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.responseSearch": {
+            "type": "object",
+            "properties": {
+                "query": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputSearchResult"
+                    }
+                }
+            }
+        },
+        "handlers.responseSection": {
+            "type": "object",
+            "properties": {
+                "section": {
+                    "$ref": "#/definitions/handlers.outputSection"
+                }
+            }
+        },
+        "handlers.responseSections": {
+            "type": "object",
+            "properties": {
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputSection"
+                    }
+                }
+            }
+        },
+        "handlers.responseTerm": {
+            "type": "object",
+            "properties": {
+                "term": {
+                    "$ref": "#/definitions/handlers.outputTerm"
+                }
+            }
+        },
+        "handlers.responseTerms": {
+            "type": "object",
+            "properties": {
+                "terms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.outputTerm"
+                    }
+                }
+            }
         }
     }
 }`
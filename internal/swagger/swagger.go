@@ -10,7 +10,7 @@ import (
 	httpSwagger "github.com/swaggo/http-swagger"
 )
 
-func RunSwagger(r *chi.Mux, logger *httplog.Logger, host string) {
+func RunSwagger(r *chi.Mux, logger *httplog.Logger, scheme string, host string) {
 	// docs
 	docs.SwaggerInfo.Title = "Go API Tech Challenge"
 	docs.SwaggerInfo.Description = "Microservice for tech challenge"
@@ -19,10 +19,10 @@ func RunSwagger(r *chi.Mux, logger *httplog.Logger, host string) {
 	docs.SwaggerInfo.Host = host
 	docs.SwaggerInfo.BasePath = ""
 
-	docs.SwaggerInfo.Schemes = []string{"http"}
+	docs.SwaggerInfo.Schemes = []string{scheme}
 
 	// handler
-	baseURL := "http://" + host
+	baseURL := scheme + "://" + host
 
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL(baseURL+"/swagger/doc.json"),
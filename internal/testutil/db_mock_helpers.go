@@ -1,17 +1,16 @@
 package testutil
 
 import (
-	"database/sql/driver"
 	"fmt"
 	"reflect"
 	"strings"
 
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pashagolub/pgxmock/v4"
 )
 
-// MustStructsToRows converts a slice of structs to sqlmock.Rows using reflect.
+// MustStructsToRows converts a slice of structs to pgxmock.Rows using reflect.
 // It can also be used when only a single struct is needed by wrapping in a slice.
-func MustStructsToRows[T any](slice []T) *sqlmock.Rows {
+func MustStructsToRows[T any](slice []T) *pgxmock.Rows {
 	v := reflect.ValueOf(slice)
 	if v.Kind() != reflect.Slice {
 		panic(fmt.Sprintf("expected a slice but got %T", slice))
@@ -38,10 +37,10 @@ func MustStructsToRows[T any](slice []T) *sqlmock.Rows {
 		columns[i] = colNameSnake
 	}
 
-	rows := sqlmock.NewRows(columns)
+	rows := pgxmock.NewRows(columns)
 
 	for i := 0; i < v.Len(); i++ {
-		var values []driver.Value
+		var values []any
 		elem := v.Index(i)
 		for j := 0; j < elem.NumField(); j++ {
 			values = append(values, elem.Field(j).Interface())
@@ -52,8 +51,8 @@ func MustStructsToRows[T any](slice []T) *sqlmock.Rows {
 	return rows
 }
 
-// MustStructToEmptyRow converts a struct into an *sqlmock.Rows object with headers but no rows.
-func MustStructToEmptyRow[T any](obj T) *sqlmock.Rows {
+// MustStructToEmptyRow converts a struct into an *pgxmock.Rows object with headers but no rows.
+func MustStructToEmptyRow[T any](obj T) *pgxmock.Rows {
 	v := reflect.ValueOf(obj)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -71,7 +70,7 @@ func MustStructToEmptyRow[T any](obj T) *sqlmock.Rows {
 		columns[i] = colNameSnake
 	}
 
-	return sqlmock.NewRows(columns)
+	return pgxmock.NewRows(columns)
 }
 
 // toSnake converts PascalCase to snake_case with special handling for abbreviations
@@ -1,10 +1,9 @@
 package testutil
 
 import (
-	"database/sql/driver"
 	"testing"
 
-	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,7 +16,7 @@ func TestMustStructsToRows(t *testing.T) {
 
 	tests := map[string]struct {
 		input        []TestStruct
-		expectedRows *sqlmock.Rows
+		expectedRows *pgxmock.Rows
 		willPanic    bool
 	}{
 		"valid slice of structs": {
@@ -25,7 +24,7 @@ func TestMustStructsToRows(t *testing.T) {
 				{ID: 1, Name: "Alice"},
 				{ID: 2, Name: "Bob"},
 			},
-			expectedRows: sqlmock.NewRows([]string{"id", "name"}).AddRows([][]driver.Value{
+			expectedRows: pgxmock.NewRows([]string{"id", "name"}).AddRows([][]any{
 				{1, "Alice"},
 				{2, "Bob"},
 			}...),
@@ -33,7 +32,7 @@ func TestMustStructsToRows(t *testing.T) {
 		},
 		"empty slice": {
 			input:        []TestStruct{},
-			expectedRows: sqlmock.NewRows([]string{}),
+			expectedRows: pgxmock.NewRows([]string{}),
 			willPanic:    true,
 		},
 	}
@@ -54,7 +53,7 @@ func TestMustStructsToRows(t *testing.T) {
 func TestMustStructToEmptyRow(t *testing.T) {
 	tests := map[string]struct {
 		input        TestStruct
-		expectedRows *sqlmock.Rows
+		expectedRows *pgxmock.Rows
 		willPanic    bool
 	}{
 		"valid slice of structs": {
@@ -62,7 +61,7 @@ func TestMustStructToEmptyRow(t *testing.T) {
 				ID:   1,
 				Name: "Alice",
 			},
-			expectedRows: sqlmock.NewRows([]string{"id", "name"}),
+			expectedRows: pgxmock.NewRows([]string{"id", "name"}),
 			willPanic:    false,
 		},
 	}
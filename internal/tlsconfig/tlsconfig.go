@@ -0,0 +1,225 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Supported client certificate policies.
+const (
+	ClientAuthNone          = "none"
+	ClientAuthRequest       = "request"
+	ClientAuthVerifyIfGiven = "verify_if_given"
+	ClientAuthRequire       = "require"
+)
+
+// Options configures the server's TLS.
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile holds the PEM certificates used to verify client certificates.
+	ClientCAFile string
+	ClientAuth   string
+	// MinVersion is "1.2" or "1.3".
+	MinVersion string
+	// ReloadInterval is how often the files are checked for changes. Zero disables reloading.
+	ReloadInterval time.Duration
+	// HTTP2 advertises h2 during ALPN.
+	HTTP2 bool
+}
+
+// state is the loaded configuration and the modification times of the files it was loaded from.
+type state struct {
+	config   *tls.Config
+	modTimes map[string]time.Time
+}
+
+// Reloader serves a TLS configuration built from certificate files, re-reading them when they change
+// on disk so that rotated certificates are picked up without a restart.
+type Reloader struct {
+	opts   Options
+	logger *slog.Logger
+	now    func() time.Time
+
+	current atomic.Pointer[state]
+
+	// mu serializes reloads.
+	mu        sync.Mutex
+	lastCheck atomic.Int64
+}
+
+// New loads the files named in opts and returns a Reloader serving them.
+func New(opts Options, logger *slog.Logger) (*Reloader, error) {
+	r := &Reloader{opts: opts, logger: logger, now: time.Now}
+	if err := r.Reload(); err != nil {
+		return nil, fmt.Errorf("[in tlsconfig.New] %w", err)
+	}
+	return r, nil
+}
+
+// Config returns a tls.Config for an http.Server. Each handshake is served from the most recently
+// loaded files.
+func (r *Reloader) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: r.current.Load().config.MinVersion,
+		NextProtos: nextProtos(r.opts.HTTP2),
+		GetCertificate: func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return &r.config().Certificates[0], nil
+		},
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.config(), nil
+		},
+	}
+}
+
+// Reload re-reads the certificate files. On failure the previously loaded files stay in use.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	modTimes, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+	config, err := r.load()
+	if err != nil {
+		return err
+	}
+
+	r.current.Store(&state{config: config, modTimes: modTimes})
+	r.lastCheck.Store(r.now().UnixNano())
+	return nil
+}
+
+// config returns the current configuration, first reloading it if the files have changed since
+// they were last checked.
+func (r *Reloader) config() *tls.Config {
+	if r.opts.ReloadInterval > 0 {
+		last := r.lastCheck.Load()
+		now := r.now().UnixNano()
+		if time.Duration(now-last) >= r.opts.ReloadInterval && r.lastCheck.CompareAndSwap(last, now) {
+			r.reloadIfChanged()
+		}
+	}
+	return r.current.Load().config
+}
+
+func (r *Reloader) reloadIfChanged() {
+	modTimes, err := r.modTimes()
+	if err != nil {
+		r.logger.Error("Error checking TLS files, keeping current certificate", "err", err)
+		return
+	}
+
+	changed := false
+	for path, modTime := range modTimes {
+		if !modTime.Equal(r.current.Load().modTimes[path]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := r.Reload(); err != nil {
+		r.logger.Error("Error reloading TLS files, keeping current certificate", "err", err)
+		return
+	}
+	r.logger.Info("TLS certificate reloaded", "cert", r.opts.CertFile)
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.opts.CertFile, r.opts.KeyFile}
+	if r.opts.ClientCAFile != "" {
+		files = append(files, r.opts.ClientCAFile)
+	}
+	return files
+}
+
+func (r *Reloader) modTimes() (map[string]time.Time, error) {
+	modTimes := map[string]time.Time{}
+	for _, path := range r.files() {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		modTimes[path] = info.ModTime()
+	}
+	return modTimes, nil
+}
+
+// load builds a tls.Config from the files named in the options.
+func (r *Reloader) load() (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(r.opts.CertFile, r.opts.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+
+	minVersion, err := parseVersion(r.opts.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	clientAuth, err := parseClientAuth(r.opts.ClientAuth)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   minVersion,
+		NextProtos:   nextProtos(r.opts.HTTP2),
+		ClientAuth:   clientAuth,
+	}
+
+	if r.opts.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.opts.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("client CA file contains no PEM certificates")
+		}
+		config.ClientCAs = pool
+	}
+
+	return config, nil
+}
+
+func nextProtos(http2 bool) []string {
+	if http2 {
+		return []string{"h2", "http/1.1"}
+	}
+	return []string{"http/1.1"}
+}
+
+func parseVersion(version string) (uint16, error) {
+	switch version {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("unsupported TLS version %q, want 1.2 or 1.3", version)
+}
+
+func parseClientAuth(clientAuth string) (tls.ClientAuthType, error) {
+	switch clientAuth {
+	case "", ClientAuthNone:
+		return tls.NoClientCert, nil
+	case ClientAuthRequest:
+		return tls.RequestClientCert, nil
+	case ClientAuthVerifyIfGiven:
+		return tls.VerifyClientCertIfGiven, nil
+	case ClientAuthRequire:
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return 0, fmt.Errorf("unsupported client auth %q", clientAuth)
+}
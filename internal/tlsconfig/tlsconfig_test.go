@@ -0,0 +1,221 @@
+package tlsconfig
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testCert is a certificate and key, either self-signed as a CA or signed by parent.
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	tls  tls.Certificate
+}
+
+func newTestCert(t *testing.T, name string, parent *testCert) testCert {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{"localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+
+	return testCert{
+		cert: cert,
+		key:  key,
+		tls:  tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: cert},
+	}
+}
+
+func (c testCert) write(t *testing.T, certFile string, keyFile string) {
+	t.Helper()
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600))
+	if keyFile != "" {
+		assert.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	}
+}
+
+// serve starts an HTTPS server using reloader and returns its address.
+func serve(t *testing.T, reloader *Reloader) string {
+	t.Helper()
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", reloader.Config())
+	assert.NoError(t, err)
+
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	return listener.Addr().String()
+}
+
+// peerName returns the common name of the certificate the server presents.
+func peerName(t *testing.T, addr string, config *tls.Config) (string, error) {
+	t.Helper()
+	conn, err := tls.Dial("tcp", addr, config)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	// TLS 1.3 reports a rejected client certificate on the first read.
+	_, _ = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\nConnection: close\r\n\r\n"))
+	if _, err := conn.Read(make([]byte, 1)); err != nil {
+		return "", err
+	}
+	return conn.ConnectionState().PeerCertificates[0].Subject.CommonName, nil
+}
+
+func TestReloaderRotatesCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	ca := newTestCert(t, "ca", nil)
+	newTestCert(t, "first", &ca).write(t, certFile, keyFile)
+
+	reloader, err := New(Options{CertFile: certFile, KeyFile: keyFile, ReloadInterval: time.Millisecond}, slog.Default())
+	assert.NoError(t, err)
+	addr := serve(t, reloader)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	client := &tls.Config{RootCAs: pool, ServerName: "localhost"}
+
+	name, err := peerName(t, addr, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", name)
+
+	newTestCert(t, "second", &ca).write(t, certFile, keyFile)
+	later := time.Now().Add(time.Minute)
+	assert.NoError(t, os.Chtimes(certFile, later, later))
+	time.Sleep(2 * time.Millisecond)
+
+	name, err = peerName(t, addr, client)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", name)
+}
+
+func TestReloaderKeepsCertificateOnBadReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	ca := newTestCert(t, "ca", nil)
+	newTestCert(t, "first", &ca).write(t, certFile, keyFile)
+
+	reloader, err := New(Options{CertFile: certFile, KeyFile: keyFile}, slog.Default())
+	assert.NoError(t, err)
+
+	assert.NoError(t, os.WriteFile(keyFile, []byte("not a key"), 0o600))
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, "first", reloader.config().Certificates[0].Leaf.Subject.CommonName)
+}
+
+func TestReloaderClientAuth(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.crt")
+	ca := newTestCert(t, "ca", nil)
+	newTestCert(t, "server", &ca).write(t, certFile, keyFile)
+	ca.write(t, caFile, "")
+
+	reloader, err := New(Options{
+		CertFile:     certFile,
+		KeyFile:      keyFile,
+		ClientCAFile: caFile,
+		ClientAuth:   ClientAuthRequire,
+	}, slog.Default())
+	assert.NoError(t, err)
+	addr := serve(t, reloader)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.cert)
+	untrusted := newTestCert(t, "untrusted-ca", nil)
+
+	tests := map[string]struct {
+		clientCerts []tls.Certificate
+		expectErr   bool
+	}{
+		"no client certificate": {
+			expectErr: true,
+		},
+		"certificate from another CA": {
+			clientCerts: []tls.Certificate{newTestCert(t, "client", &untrusted).tls},
+			expectErr:   true,
+		},
+		"trusted client certificate": {
+			clientCerts: []tls.Certificate{newTestCert(t, "client", &ca).tls},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := peerName(t, addr, &tls.Config{
+				RootCAs:      pool,
+				ServerName:   "localhost",
+				Certificates: tc.clientCerts,
+			})
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewInvalidOptions(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	ca := newTestCert(t, "ca", nil)
+	newTestCert(t, "server", &ca).write(t, certFile, keyFile)
+
+	tests := map[string]Options{
+		"missing files":      {CertFile: filepath.Join(dir, "missing.crt"), KeyFile: keyFile},
+		"unknown version":    {CertFile: certFile, KeyFile: keyFile, MinVersion: "1.0"},
+		"unknown clientauth": {CertFile: certFile, KeyFile: keyFile, ClientAuth: "always"},
+		"empty client CA":    {CertFile: certFile, KeyFile: keyFile, ClientCAFile: keyFile},
+	}
+
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(opts, slog.Default())
+			assert.Error(t, err)
+		})
+	}
+}
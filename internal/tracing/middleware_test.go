@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMiddleware(t *testing.T) {
+	_, err := New(context.Background(), Options{Exporter: ExporterNone})
+	assert.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	router := chi.NewRouter()
+	router.Use(Middleware)
+	router.Get("/api/person/{name}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	router.Get("/api/course", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	tests := map[string]struct {
+		path           string
+		traceparent    string
+		expectedName   string
+		expectedStatus int
+		expectedCode   codes.Code
+		expectedParent string
+	}{
+		"continues incoming trace": {
+			path:           "/api/person/Jobs",
+			traceparent:    "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			expectedName:   "GET /api/person/{name}",
+			expectedStatus: http.StatusOK,
+			expectedCode:   codes.Unset,
+			expectedParent: "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		"marks server errors": {
+			path:           "/api/course",
+			expectedName:   "GET /api/course",
+			expectedStatus: http.StatusInternalServerError,
+			expectedCode:   codes.Error,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.traceparent != "" {
+				req.Header.Set("traceparent", tc.traceparent)
+			}
+
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			spans := recorder.Ended()
+			assert.NotEmpty(t, spans)
+			span := spans[len(spans)-1]
+
+			assert.Equal(t, tc.expectedName, span.Name())
+			assert.Equal(t, tc.expectedCode, span.Status().Code)
+			assert.Contains(t, span.Attributes(), attribute.Int("http.response.status_code", tc.expectedStatus))
+			assert.NotEmpty(t, rr.Header().Get("traceparent"))
+			if tc.expectedParent != "" {
+				assert.Equal(t, tc.expectedParent, span.Parent().TraceID().String())
+				assert.Equal(t, tc.expectedParent, span.SpanContext().TraceID().String())
+			}
+		})
+	}
+}